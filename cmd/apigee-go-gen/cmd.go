@@ -15,10 +15,18 @@
 package main
 
 import (
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/diagram"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/diff"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/lint"
 	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/mock"
+	packagecmd "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/package"
 	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/serve"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/simulate"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/test"
 	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/transform"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/globals"
 	"github.com/spf13/cobra"
 	"os"
@@ -38,8 +46,17 @@ func init() {
 	RootCmd.AddCommand(render.Cmd)
 	RootCmd.AddCommand(transform.Cmd)
 	RootCmd.AddCommand(mock.Cmd)
+	RootCmd.AddCommand(lint.Cmd)
+	RootCmd.AddCommand(diff.Cmd)
+	RootCmd.AddCommand(simulate.Cmd)
+	RootCmd.AddCommand(diagram.Cmd)
+	RootCmd.AddCommand(test.Cmd)
+	RootCmd.AddCommand(packagecmd.Cmd)
+	RootCmd.AddCommand(serve.Cmd)
 	RootCmd.AddCommand(VersionCmd)
 
 	RootCmd.PersistentFlags().Var(&showStack, "show-stack", "show stack trace for errors")
 	globals.ShowStack = &showStack
+
+	RootCmd.PersistentFlags().Var(&git.Offline, "offline", "use only cached templates from git repositories, without network access")
 }
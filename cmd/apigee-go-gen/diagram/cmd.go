@@ -0,0 +1,68 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package diagram
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/diagram"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var input flags.String
+var output flags.String
+var format = flags.NewEnum([]string{"mermaid", "dot"})
+
+var Cmd = &cobra.Command{
+	Use:   "diagram",
+	Short: "Generate a flow diagram (Mermaid or Graphviz) of an API proxy or shared flow",
+	Long: `
+This command generates a diagram of the request/response pipeline of an API proxy (bundle zip, bundle dir, or YAML file).
+The diagram includes the proxy endpoints, conditional flows with their conditions, steps, route rules to
+target endpoints, FlowCallouts to shared flows, and fault rules.
+
+For shared flows, the diagram includes the steps of each shared flow, and the FlowCallouts between them.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, err := bundle.LoadModel(string(input))
+		if err != nil {
+			return err
+		}
+
+		graph, err := diagram.Build(model)
+		if err != nil {
+			return err
+		}
+
+		var outputText []byte
+		if format.Value == "dot" {
+			outputText = diagram.DOT(graph)
+		} else {
+			outputText = diagram.Mermaid(graph)
+		}
+
+		return utils.WriteOutputText(string(output), outputText)
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&input, "input", "i", "path to bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&format, "format", "f", "output format (default mermaid)")
+	Cmd.Flags().VarP(&output, "output", "o", "path to output file (default stdout)")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
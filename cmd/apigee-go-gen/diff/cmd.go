@@ -0,0 +1,86 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package diff
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/diff"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+)
+
+var oldInput flags.String
+var newInput flags.String
+var output flags.String
+var format = flags.NewEnum([]string{"text", "json"})
+var exitCode = flags.NewBool(false)
+
+var Cmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show semantic differences between two API proxies or shared flows",
+	Long: `
+This command compares two API proxies or shared flows (bundle zips, bundle dirs, or YAML files)
+and reports the policies, flows, steps, conditions, target URLs and resources that were
+added, removed, changed or moved. Whitespace around values, and the order of attributes is ignored.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldModel, err := bundle.LoadModel(string(oldInput))
+		if err != nil {
+			return err
+		}
+
+		newModel, err := bundle.LoadModel(string(newInput))
+		if err != nil {
+			return err
+		}
+
+		changes, err := diff.Compare(oldModel, newModel)
+		if err != nil {
+			return err
+		}
+
+		var outputText []byte
+		if format.Value == "json" {
+			if outputText, err = diff.JSON(changes); err != nil {
+				return err
+			}
+		} else {
+			outputText = diff.Text(changes)
+		}
+
+		if err = utils.WriteOutputText(string(output), outputText); err != nil {
+			return err
+		}
+
+		if bool(exitCode) && len(changes) > 0 {
+			return errors.Errorf("found %d change(s)", len(changes))
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&oldInput, "old", "a", "path to old bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&newInput, "new", "b", "path to new bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&format, "format", "f", "output format (default text)")
+	Cmd.Flags().VarP(&output, "output", "o", "path to output file (default stdout)")
+	Cmd.Flags().VarP(&exitCode, "exit-code", "", "exit with an error when there are changes")
+
+	_ = Cmd.MarkFlagRequired("old")
+	_ = Cmd.MarkFlagRequired("new")
+}
@@ -0,0 +1,98 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package lint
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/lint"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var input flags.String
+var output flags.String
+var config flags.String
+var format = flags.NewEnum([]string{"text", "json", "sarif"})
+
+var Cmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check an API proxy or shared flow for common problems",
+	Long:  Usage(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var lintConfig *lint.Config
+		var err error
+		if strings.TrimSpace(string(config)) != "" {
+			if lintConfig, err = lint.LoadConfig(string(config)); err != nil {
+				return err
+			}
+		}
+
+		model, err := bundle.LoadModel(string(input))
+		if err != nil {
+			return err
+		}
+
+		findings := lint.Lint(model, lintConfig)
+
+		var outputText []byte
+		switch format.Value {
+		case "json":
+			outputText, err = lint.JSON(findings)
+		case "sarif":
+			outputText, err = lint.SARIF(findings, string(input))
+		default:
+			outputText = lint.Text(findings)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = utils.WriteOutputText(string(output), outputText); err != nil {
+			return err
+		}
+
+		if count := lint.CountSeverity(findings, lint.SeverityError); count > 0 {
+			return errors.Errorf("found %d lint error(s)", count)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&input, "input", "i", "path to bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&config, "config", "c", "path to YAML file for enabling, disabling and changing severity of rules")
+	Cmd.Flags().VarP(&format, "format", "f", "output format (default text)")
+	Cmd.Flags().VarP(&output, "output", "o", "path to output file (default stdout)")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func Usage() string {
+	var rules strings.Builder
+	for _, rule := range lint.Rules() {
+		rules.WriteString("  " + rule.ID + " (" + string(rule.Severity) + ")\n      " + rule.Description + "\n")
+	}
+
+	return `
+This command checks an API proxy or shared flow for common problems.
+
+Rules:
+
+` + rules.String()
+}
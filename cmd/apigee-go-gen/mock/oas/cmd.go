@@ -18,9 +18,11 @@ import (
 	"fmt"
 	"github.com/apigee/apigee-go-gen/pkg/common/resources"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/mock"
 	"github.com/apigee/apigee-go-gen/pkg/render"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 var cFlags = render.NewCommonFlags()
@@ -35,7 +37,12 @@ var Cmd = &cobra.Command{
 	Short: "Generate a mock API proxy from an OpenAPI 3.X Description",
 	Long:  Usage(),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return mock.GenerateMockProxyBundle(string(input), string(output), cFlags, bool(debug))
+		inputFile, err := git.LocalFile(string(input), cFlags.GitSources)
+		if err != nil {
+			return err
+		}
+		cFlags.GitSources.Fprint(os.Stderr)
+		return mock.GenerateMockProxyBundle(inputFile, string(output), cFlags, bool(debug))
 	},
 }
 
@@ -44,6 +51,7 @@ func init() {
 	Cmd.Flags().VarP(&input, "input", "i", `path to OpenAPI Description (e.g. "./path/to/openapi.yaml")`)
 	Cmd.Flags().VarP(&output, "output", "o", `output directory or zip file (e.g. "./path/to/apiproxy.zip")`)
 	Cmd.Flags().VarP(&debug, "debug", "", `prints rendered template before creating API proxy bundle"`)
+	Cmd.Flags().Var(&cFlags.SHA256Manifest, "sha256-manifest", `writes the SHA-256 of each bundle file next to the output, e.g. "apiproxy.zip.sha256"`)
 	Cmd.Flags().Var(&setValue, "set", `sets a key=value (bool,float,string), e.g. "vertex.enabled=true"`)
 
 	_ = Cmd.MarkFlagRequired("input")
@@ -0,0 +1,31 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package packagecmd
+
+import (
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/package/create"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/package/inspect"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "package",
+	Short: "Create and inspect template packages",
+}
+
+func init() {
+	Cmd.AddCommand(create.Cmd)
+	Cmd.AddCommand(inspect.Cmd)
+}
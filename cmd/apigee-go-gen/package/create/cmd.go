@@ -0,0 +1,53 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package create
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/templatepkg"
+	"github.com/spf13/cobra"
+)
+
+var source flags.String
+var outputDir = flags.String(".")
+
+var Cmd = &cobra.Command{
+	Use:   "create",
+	Short: "Package a template directory into an archive",
+	Long: `
+This command packages a template directory, along with its dependencies, into "<name>-<version>.zip".
+
+The template directory must have a "package.yaml" manifest with the package name, version, type, main template,
+inputs, default values, and dependencies. Dependencies with a "source" are copied into the "packages" directory
+of the archive, so that it can be rendered on its own.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := templatepkg.Create(string(source), string(outputDir))
+		if err != nil {
+			return err
+		}
+		fmt.Println(archive)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&source, "source", "s", `path to the template package directory`)
+	Cmd.Flags().VarP(&outputDir, "output", "o", `directory where the archive is written`)
+
+	_ = Cmd.MarkFlagRequired("source")
+}
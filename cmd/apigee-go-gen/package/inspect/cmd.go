@@ -0,0 +1,50 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package inspect
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/templatepkg"
+	"github.com/spf13/cobra"
+)
+
+var source flags.String
+
+var Cmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Show the metadata, inputs, dependencies, and files of a template package",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkg, cleanup, err := templatepkg.Load(string(source))
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		text, err := pkg.Describe()
+		if err != nil {
+			return err
+		}
+		fmt.Print(text)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&source, "package", "p", `path to the template package archive or directory`)
+
+	_ = Cmd.MarkFlagRequired("package")
+}
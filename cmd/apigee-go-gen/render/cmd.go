@@ -16,8 +16,11 @@ package render
 
 import (
 	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/apiproxy"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/dir"
+	packagecmd "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/package"
 	sharedflow "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/sharedflow"
 	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/template"
+	"github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/render/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -30,5 +33,8 @@ func init() {
 	Cmd.AddCommand(apiproxy.Cmd)
 	Cmd.AddCommand(sharedflow.Cmd)
 	Cmd.AddCommand(template.Cmd)
+	Cmd.AddCommand(dir.Cmd)
+	Cmd.AddCommand(packagecmd.Cmd)
+	Cmd.AddCommand(workspace.Cmd)
 
 }
@@ -0,0 +1,130 @@
+//  Copyright 2025 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package dir
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/common/resources"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/watch"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var cFlags = render.NewCommonFlags()
+var dryRun = flags.NewBool(false)
+var setValue = flags.NewSetAny(cFlags.Values)
+var setValueStr = flags.NewSetString(cFlags.Values)
+var profile = flags.NewProfile("")
+var setValueFile = flags.NewProfileValues(cFlags.Values, &profile)
+var printValues = flags.NewBool(false)
+var watchMode = flags.NewBool(false)
+var setFile = flags.NewSetFile(cFlags.Values)
+var setOAS = flags.NewSetOAS(cFlags.Values)
+var setGraphQL = flags.NewSetGraphQL(cFlags.Values)
+var setGRPC = flags.NewSetGRPC(cFlags.Values)
+var setJSON = flags.NewSetJSON(cFlags.Values)
+var setTF = flags.NewSetTF(cFlags.Values)
+
+// values are set after parsing all flags, so that --offline and --profile can come after them
+var deferred flags.Deferred
+
+var Cmd = &cobra.Command{
+	Use:   "dir",
+	Short: "Render every file within a template directory",
+	Long:  Usage(),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return deferred.Apply(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchMode {
+			return watch.Command(cmd.Flags())
+		}
+		defer cFlags.GitSources.Fprint(os.Stderr)
+
+		if printValues {
+			return render.PrintValues(cFlags.Values, setValueFile.Sources)
+		}
+
+		if strings.TrimSpace(string(cFlags.OutputFile)) == "" && dryRun == false {
+			return errors.New("required flag(s) \"output\" not set")
+		}
+		return render.RenderDir(cFlags, bool(dryRun))
+	},
+}
+
+func init() {
+	// files fetched from git repositories are collected along with the template, for the manifest
+	setValueFile.GitSources = cFlags.GitSources
+	setFile.GitSources = cFlags.GitSources
+	setOAS.GitSources = cFlags.GitSources
+	setGraphQL.GitSources = cFlags.GitSources
+	setGRPC.GitSources = cFlags.GitSources
+	setTF.GitSources = cFlags.GitSources
+
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&cFlags.TemplateFile, "template", "t", `path to template directory`)
+	Cmd.Flags().VarP(&cFlags.IncludeList, "include", "i", `path to helper templates (globs allowed)`)
+	Cmd.Flags().VarP(&cFlags.OutputFile, "output", "o", `output directory`)
+	Cmd.Flags().VarP(&dryRun, "dry-run", "d", `prints each rendered file to stdout`)
+	Cmd.Flags().Var(&cFlags.Strict, "strict", `fails on missing keys, missing named templates, and values that render as "<no value>"`)
+	Cmd.Flags().Var(deferred.Value(&setValue), "set", `sets a key=value (bool,float,string), e.g. "use_ssl=true"`)
+	Cmd.Flags().Var(deferred.Value(&setValueStr), "set-string", `sets key=value (string), e.g. "base_path=/v1/hello" `)
+	Cmd.Flags().Var(&profile, "profile", `deep merges "values.<profile>.yaml" after each values file, e.g. "prod"`)
+	Cmd.Flags().Var(deferred.Value(&setValueFile), "values", `deep merges keys/values from YAML file, e.g. "./values.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setFile), "set-file", `sets key=value where value is the content of a file, e.g. "my_data=./from/file.txt"`)
+	Cmd.Flags().Var(deferred.Value(&setOAS), "set-oas", `sets key=value where value is an OpenAPI Description, e.g. "my_spec=./petstore.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setGRPC), "set-grpc", `sets key=value where value is a gRPC proto, e.g. "my_proto=./greeter.proto"`)
+	Cmd.Flags().Var(deferred.Value(&setGraphQL), "set-graphql", `sets key=value where value is a GraphQL schema, e.g. "my_schema=./resorts.graphql"`)
+	Cmd.Flags().Var(deferred.Value(&setTF), "set-tf", `sets key=value where value is a Terraform HCL file, e.g. "my_tf=./file.tf"`)
+	Cmd.Flags().Var(deferred.Value(&setJSON), "set-json", `sets key=value where value is JSON, e.g. 'servers=["server1","server2"]'`)
+	Cmd.Flags().Var(&printValues, "print-values", `prints the merged values, and where each value came from, instead of rendering`)
+	Cmd.Flags().Var(&watchMode, watch.Flag, `re-renders each time the template directory, or any of the files it reads change`)
+
+	_ = Cmd.MarkFlagRequired("template")
+}
+
+func Usage() string {
+	usageText := `
+This command renders each file within a template directory into the same path within the output directory.
+
+Files and directories with a name starting with "_" are partials (e.g. "_helpers.tmpl"), and are not rendered on their own.
+File and directory names may contain template actions (e.g. "{{ .Values.name }}.tf"). Files with a name that renders empty are skipped.
+Paths matching the patterns in the ".apigee-go-gen-ignore" file of the template directory are skipped.
+
+The rendering context includes the following data:
+
+%[1]s
+
+Helper functions:
+
+%[2]s
+
+`
+	helpersText, err := resources.FS.ReadFile("helper_functions.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	renderContextText, err := resources.FS.ReadFile("render_context.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	return fmt.Sprintf(usageText, renderContextText, helpersText)
+}
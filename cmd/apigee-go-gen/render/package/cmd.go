@@ -0,0 +1,122 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package packagecmd
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/templatepkg"
+	"github.com/apigee/apigee-go-gen/pkg/watch"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var source flags.String
+var cFlags = render.NewCommonFlags()
+var debug = flags.NewBool(false)
+var dryRun = flags.NewEnum([]string{"xml", "yaml"})
+var validate = flags.NewBool(true)
+var setValue = flags.NewSetAny(cFlags.Values)
+var setValueStr = flags.NewSetString(cFlags.Values)
+var profile = flags.NewProfile("")
+var setValueFile = flags.NewProfileValues(cFlags.Values, &profile)
+var printValues = flags.NewBool(false)
+var watchMode = flags.NewBool(false)
+var setFile = flags.NewSetFile(cFlags.Values)
+var setOAS = flags.NewSetOAS(cFlags.Values)
+var setGraphQL = flags.NewSetGraphQL(cFlags.Values)
+var setGRPC = flags.NewSetGRPC(cFlags.Values)
+var setJSON = flags.NewSetJSON(cFlags.Values)
+var setTF = flags.NewSetTF(cFlags.Values)
+
+// values are set after parsing all flags, so that --offline and --profile can come after them
+var deferred flags.Deferred
+
+var Cmd = &cobra.Command{
+	Use:   "package",
+	Short: "Render a template package",
+	Long: `
+This command renders a template package (an archive created with "package create", or a package directory).
+
+The package is rendered the same way as the render command for its type (apiproxy, sharedflow, template, or dir).
+The default values from the package manifest, and from its dependencies, are used for any key that is not set.
+The helper templates listed in the "includes" of the package, and of its dependencies, are included automatically.
+`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return deferred.Apply(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchMode {
+			return watch.Command(cmd.Flags())
+		}
+		defer cFlags.GitSources.Fprint(os.Stderr)
+
+		pkg, cleanup, err := templatepkg.Load(string(source))
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		if printValues {
+			if err = pkg.ApplyDefaults(cFlags.Values, setValueFile.Sources); err != nil {
+				return err
+			}
+			return render.PrintValues(cFlags.Values, setValueFile.Sources)
+		}
+
+		if strings.TrimSpace(string(cFlags.OutputFile)) == "" && dryRun.IsUnset() && bool(debug) == false {
+			return errors.New("required flag(s) \"output\" not set")
+		}
+
+		warnings, err := pkg.Render(cFlags, bool(validate), dryRun.Value, bool(debug))
+		render.PrintWarnings(warnings)
+		return err
+	},
+}
+
+func init() {
+	// files fetched from git repositories are collected along with the template, for the manifest
+	setValueFile.GitSources = cFlags.GitSources
+	setFile.GitSources = cFlags.GitSources
+	setOAS.GitSources = cFlags.GitSources
+	setGraphQL.GitSources = cFlags.GitSources
+	setGRPC.GitSources = cFlags.GitSources
+	setTF.GitSources = cFlags.GitSources
+
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&source, "package", "p", `path to the template package archive or directory`)
+	Cmd.Flags().VarP(&cFlags.IncludeList, "include", "i", `path to additional helper templates (globs allowed)`)
+	Cmd.Flags().VarP(&cFlags.OutputFile, "output", "o", `output directory or file`)
+	Cmd.Flags().VarP(&debug, "debug", "", `prints rendered template before transforming into API proxy or shared flow"`)
+	Cmd.Flags().VarP(&dryRun, "dry-run", "d", `prints rendered template after transforming into API proxy or shared flow (any value prints the output of template and dir packages)`)
+	Cmd.Flags().Var(&cFlags.Strict, "strict", `fails on missing keys, missing named templates, and values that render as "<no value>"`)
+	Cmd.Flags().VarP(&validate, "validate", "v", "check for unknown and missing elements")
+	Cmd.Flags().Var(deferred.Value(&setValue), "set", `sets a key=value (bool,float,string), e.g. "use_ssl=true"`)
+	Cmd.Flags().Var(deferred.Value(&setValueStr), "set-string", `sets key=value (string), e.g. "base_path=/v1/hello" `)
+	Cmd.Flags().Var(&profile, "profile", `deep merges "values.<profile>.yaml" after each values file, e.g. "prod"`)
+	Cmd.Flags().Var(deferred.Value(&setValueFile), "values", `deep merges keys/values from YAML file, e.g. "./values.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setFile), "set-file", `sets key=value where value is the content of a file, e.g. "my_data=./from/file.txt"`)
+	Cmd.Flags().Var(deferred.Value(&setOAS), "set-oas", `sets key=value where value is an OpenAPI Description, e.g. "my_spec=./petstore.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setGRPC), "set-grpc", `sets key=value where value is a gRPC proto, e.g. "my_proto=./greeter.proto"`)
+	Cmd.Flags().Var(deferred.Value(&setGraphQL), "set-graphql", `sets key=value where value is a GraphQL schema, e.g. "my_schema=./resorts.graphql"`)
+	Cmd.Flags().Var(deferred.Value(&setTF), "set-tf", `sets key=value where value is a Terraform HCL file, e.g. "my_tf=./file.tf"`)
+	Cmd.Flags().Var(deferred.Value(&setJSON), "set-json", `sets key=value where value is JSON, e.g. 'servers=["server1","server2"]'`)
+	Cmd.Flags().Var(&printValues, "print-values", `prints the merged values (including package defaults), and where each value came from, instead of rendering`)
+	Cmd.Flags().Var(&watchMode, watch.Flag, `re-renders each time the package, or any of the files it reads change`)
+
+	_ = Cmd.MarkFlagRequired("package")
+}
@@ -19,8 +19,10 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/common/resources"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
 	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/watch"
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
+	"os"
 	"strings"
 )
 
@@ -28,7 +30,10 @@ var cFlags = render.NewCommonFlags()
 var dryRun = flags.NewBool(false)
 var setValue = flags.NewSetAny(cFlags.Values)
 var setValueStr = flags.NewSetString(cFlags.Values)
-var setValueFile = flags.NewValues(cFlags.Values)
+var profile = flags.NewProfile("")
+var setValueFile = flags.NewProfileValues(cFlags.Values, &profile)
+var printValues = flags.NewBool(false)
+var watchMode = flags.NewBool(false)
 var setFile = flags.NewSetFile(cFlags.Values)
 var setOAS = flags.NewSetOAS(cFlags.Values)
 var setGraphQL = flags.NewSetGraphQL(cFlags.Values)
@@ -36,11 +41,26 @@ var setGRPC = flags.NewSetGRPC(cFlags.Values)
 var setJSON = flags.NewSetJSON(cFlags.Values)
 var setTF = flags.NewSetTF(cFlags.Values)
 
+// values are set after parsing all flags, so that --offline and --profile can come after them
+var deferred flags.Deferred
+
 var Cmd = &cobra.Command{
 	Use:   "template",
 	Short: "render a template",
 	Long:  Usage(),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return deferred.Apply(cmd.Flags())
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchMode {
+			return watch.Command(cmd.Flags())
+		}
+		defer cFlags.GitSources.Fprint(os.Stderr)
+
+		if printValues {
+			return render.PrintValues(cFlags.Values, setValueFile.Sources)
+		}
+
 		if strings.TrimSpace(string(cFlags.OutputFile)) == "" && dryRun == false {
 			return errors.New("required flag(s) \"output\" not set")
 		}
@@ -49,20 +69,32 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	// files fetched from git repositories are collected along with the template, for the manifest
+	setValueFile.GitSources = cFlags.GitSources
+	setFile.GitSources = cFlags.GitSources
+	setOAS.GitSources = cFlags.GitSources
+	setGraphQL.GitSources = cFlags.GitSources
+	setGRPC.GitSources = cFlags.GitSources
+	setTF.GitSources = cFlags.GitSources
+
 	Cmd.Flags().SortFlags = false
 	Cmd.Flags().VarP(&cFlags.TemplateFile, "template", "t", `path to main template"`)
 	Cmd.Flags().VarP(&cFlags.IncludeList, "include", "i", `path to helper templates (globs allowed)`)
 	Cmd.Flags().VarP(&cFlags.OutputFile, "output", "o", `output directory or file`)
 	Cmd.Flags().VarP(&dryRun, "dry-run", "d", `prints rendered template to stdout"`)
-	Cmd.Flags().Var(&setValue, "set", `sets a key=value (bool,float,string), e.g. "use_ssl=true"`)
-	Cmd.Flags().Var(&setValueStr, "set-string", `sets key=value (string), e.g. "base_path=/v1/hello" `)
-	Cmd.Flags().Var(&setValueFile, "values", `sets keys/values from YAML file, e.g. "./values.yaml"`)
-	Cmd.Flags().Var(&setFile, "set-file", `sets key=value where value is the content of a file, e.g. "my_data=./from/file.txt"`)
-	Cmd.Flags().Var(&setOAS, "set-oas", `sets key=value where value is an OpenAPI Description, e.g. "my_spec=./petstore.yaml"`)
-	Cmd.Flags().Var(&setGRPC, "set-grpc", `sets key=value where value is a gRPC proto, e.g. "my_proto=./greeter.proto"`)
-	Cmd.Flags().Var(&setGraphQL, "set-graphql", `sets key=value where value is a GraphQL schema, e.g. "my_schema=./resorts.graphql"`)
-	Cmd.Flags().Var(&setTF, "set-tf", `sets key=value where value is a Terraform HCL file, e.g. "my_tf=./file.tf"`)
-	Cmd.Flags().Var(&setJSON, "set-json", `sets key=value where value is JSON, e.g. 'servers=["server1","server2"]'`)
+	Cmd.Flags().Var(&cFlags.Strict, "strict", `fails on missing keys, missing named templates, and values that render as "<no value>"`)
+	Cmd.Flags().Var(deferred.Value(&setValue), "set", `sets a key=value (bool,float,string), e.g. "use_ssl=true"`)
+	Cmd.Flags().Var(deferred.Value(&setValueStr), "set-string", `sets key=value (string), e.g. "base_path=/v1/hello" `)
+	Cmd.Flags().Var(&profile, "profile", `deep merges "values.<profile>.yaml" after each values file, e.g. "prod"`)
+	Cmd.Flags().Var(deferred.Value(&setValueFile), "values", `deep merges keys/values from YAML file, e.g. "./values.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setFile), "set-file", `sets key=value where value is the content of a file, e.g. "my_data=./from/file.txt"`)
+	Cmd.Flags().Var(deferred.Value(&setOAS), "set-oas", `sets key=value where value is an OpenAPI Description, e.g. "my_spec=./petstore.yaml"`)
+	Cmd.Flags().Var(deferred.Value(&setGRPC), "set-grpc", `sets key=value where value is a gRPC proto, e.g. "my_proto=./greeter.proto"`)
+	Cmd.Flags().Var(deferred.Value(&setGraphQL), "set-graphql", `sets key=value where value is a GraphQL schema, e.g. "my_schema=./resorts.graphql"`)
+	Cmd.Flags().Var(deferred.Value(&setTF), "set-tf", `sets key=value where value is a Terraform HCL file, e.g. "my_tf=./file.tf"`)
+	Cmd.Flags().Var(deferred.Value(&setJSON), "set-json", `sets key=value where value is JSON, e.g. 'servers=["server1","server2"]'`)
+	Cmd.Flags().Var(&printValues, "print-values", `prints the merged values, and where each value came from, instead of rendering`)
+	Cmd.Flags().Var(&watchMode, watch.Flag, `re-renders each time the template, or any of the files it reads change`)
 
 	_ = Cmd.MarkFlagRequired("template")
 }
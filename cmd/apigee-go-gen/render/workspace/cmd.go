@@ -0,0 +1,91 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package workspace
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"os"
+	"runtime"
+	"time"
+)
+
+var file = flags.String(render.DefaultWorkspaceFile)
+var parallel = flags.NewInt(runtime.NumCPU())
+var only = flags.NewStringList(nil)
+var profile flags.String
+
+var Cmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Generate many API proxy and shared flow bundles from a workspace file",
+	Long: `
+This command reads a workspace file that lists many bundles, each with its own template, values,
+and output, and renders all of them in parallel.
+
+A line is printed for each bundle as soon as it is done, followed by a summary.
+The command fails if any of the bundles fails to render.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, err := render.LoadWorkspace(string(file))
+		if err != nil {
+			return err
+		}
+
+		if profile != "" {
+			workspace.Profile = string(profile)
+		}
+
+		bundles, err := workspace.Select(only)
+		if err != nil {
+			return err
+		}
+
+		progress := func(result render.WorkspaceResult) {
+			result.GitSources.Fprint(os.Stderr)
+			render.PrintWarnings(result.Warnings)
+			if result.Err != nil {
+				fmt.Printf("FAIL %s (%s)\n%s\n", result.Name, result.Duration.Round(time.Millisecond), result.Err.Error())
+				return
+			}
+			fmt.Printf("OK   %s -> %s (%s)\n", result.Name, result.Output, result.Duration.Round(time.Millisecond))
+		}
+
+		results := render.RenderWorkspace(workspace, bundles, int(parallel), progress)
+
+		failed := 0
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+
+		fmt.Printf("\n%d bundle(s) rendered, %d failed\n", len(results)-failed, failed)
+		if failed > 0 {
+			return errors.Errorf("%d of %d bundle(s) failed to render", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&file, "file", "f", `path to workspace file`)
+	Cmd.Flags().VarP(&parallel, "parallel", "p", `number of bundles to render at the same time`)
+	Cmd.Flags().VarP(&only, "only", "", `name of bundle to render (repeatable), renders all bundles if not set`)
+	Cmd.Flags().VarP(&profile, "profile", "", `profile for bundles that do not set their own, overrides the workspace profile`)
+}
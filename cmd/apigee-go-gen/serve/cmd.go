@@ -0,0 +1,93 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package serve
+
+import (
+	"context"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/server"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var addr = flags.NewString("localhost:8080")
+var maxRequestSize = flags.NewInt(server.DefaultMaxRequestSize)
+var maxOutputSize = flags.NewInt(server.DefaultMaxOutputSize)
+var timeout = flags.NewDuration(server.DefaultTimeout)
+
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve render, transform and mock generation over an HTTP/JSON API",
+	Long: `
+This command starts an HTTP server that exposes the render, transform and mock commands as a JSON API.
+Each request carries its own templates, values and specs, and is processed in memory,
+without using the working directory or temporary directories.
+
+  POST /v1/render/apiproxy                {"files": {...}, "template": "apiproxy.yaml", "values": {...}, "specs": {"spec": "openapi.yaml"}}
+  POST /v1/render/sharedflow              same as above, for a shared flow
+  POST /v1/mock/oas                       {"files": {...}, "spec": "openapi.yaml", "values": {...}}
+  POST /v1/transform/apiproxy-to-yaml     bundle zip
+  POST /v1/transform/sharedflow-to-yaml   bundle zip
+  POST /v1/transform/yaml-to-apiproxy     YAML document, or zip with the YAML document and resources
+  POST /v1/transform/yaml-to-sharedflow   YAML document, or zip with the YAML document and resources
+  POST /v1/transform/oas-overlay          {"files": {...}, "spec": "openapi.yaml", "overlay": "overlay.yaml"}
+  POST /v1/transform/resolve-refs         {"files": {...}, "input": "openapi.yaml"}
+
+Bundles are returned as zip (or YAML with ?format=yaml), use ?validate=false to skip validation.
+Errors are returned as {"errors": [{"message": "...", "position": {...}}]}.
+
+Posted templates cannot read the server environment, or write to its standard output (os_getenv, os_getenvs, fmt_printf, env, expandenv and getHostByName are not available).
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		httpServer := &http.Server{
+			Addr: string(addr),
+			Handler: server.NewHandler(server.Options{
+				MaxRequestSize: int64(maxRequestSize),
+				MaxOutputSize:  int64(maxOutputSize),
+				Timeout:        time.Duration(timeout),
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+		}()
+
+		_, _ = fmt.Fprintf(os.Stderr, "Listening on http://%s\n", addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return errors.New(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&addr, "addr", "", "address to listen on (default localhost:8080)")
+	Cmd.Flags().VarP(&maxRequestSize, "max-request-size", "", "maximum size of request bodies in bytes (default 10 MiB)")
+	Cmd.Flags().VarP(&maxOutputSize, "max-output-size", "", "maximum size of rendered templates and response bodies in bytes (default 50 MiB)")
+	Cmd.Flags().VarP(&timeout, "timeout", "", "time limit for processing each request (default 30s)")
+}
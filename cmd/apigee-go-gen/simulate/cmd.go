@@ -0,0 +1,116 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package simulate
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/simulate"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var input flags.String
+var verb = flags.String("GET")
+var path flags.String
+var headers = flags.NewStringList(nil)
+var queryParams = flags.NewStringList(nil)
+var variables = flags.NewStringList(nil)
+var output flags.String
+var format = flags.NewEnum([]string{"text", "json"})
+
+var Cmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Show which flows, steps and target are selected for a request",
+	Long: `
+This command walks an API proxy (bundle zip, bundle dir, or YAML file) the same way the Apigee runtime would
+for the given request. It selects the ProxyEndpoint by BasePath, evaluates the conditions of Flows, Steps,
+RouteRules and FaultRules, and prints the ordered list of policies that would execute along with the chosen target.
+
+Policies are not executed, so flow variables they would set are not available to conditions.
+Use --var to provide those values.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request := &simulate.Request{
+			Verb:      string(verb),
+			Path:      string(path),
+			Headers:   map[string]string{},
+			Query:     map[string]string{},
+			Variables: map[string]any{},
+		}
+
+		for _, header := range headers {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok {
+				return errors.Errorf(`header "%s" must be in the form "Name: value"`, header)
+			}
+			request.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+
+		for _, queryParam := range queryParams {
+			name, value, ok := strings.Cut(queryParam, "=")
+			if !ok {
+				return errors.Errorf(`query param "%s" must be in the form "name=value"`, queryParam)
+			}
+			request.Query[name] = value
+		}
+
+		for _, variable := range variables {
+			name, value, ok := strings.Cut(variable, "=")
+			if !ok {
+				return errors.Errorf(`variable "%s" must be in the form "name=value"`, variable)
+			}
+			request.Variables[name] = value
+		}
+
+		model, err := bundle.LoadModel(string(input))
+		if err != nil {
+			return err
+		}
+
+		result, err := simulate.Simulate(model, request)
+		if err != nil {
+			return err
+		}
+
+		var outputText []byte
+		if format.Value == "json" {
+			if outputText, err = simulate.JSON(result); err != nil {
+				return err
+			}
+		} else {
+			outputText = simulate.Text(result)
+		}
+
+		return utils.WriteOutputText(string(output), outputText)
+	},
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&input, "input", "i", "path to bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&verb, "verb", "X", "request verb (default GET)")
+	Cmd.Flags().VarP(&path, "path", "p", `request path including base path and query string, e.g. "/v1/pets/123?limit=10"`)
+	Cmd.Flags().VarP(&headers, "header", "H", `request header in the form "Name: value" (can be repeated)`)
+	Cmd.Flags().VarP(&queryParams, "query", "q", `request query param in the form "name=value" (can be repeated)`)
+	Cmd.Flags().VarP(&variables, "var", "", `flow variable in the form "name=value" (can be repeated)`)
+	Cmd.Flags().VarP(&format, "format", "f", "output format (default text)")
+	Cmd.Flags().VarP(&output, "output", "o", "path to output file (default stdout)")
+
+	_ = Cmd.MarkFlagRequired("input")
+	_ = Cmd.MarkFlagRequired("path")
+}
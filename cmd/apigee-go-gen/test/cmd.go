@@ -0,0 +1,98 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package test
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+var templateFile flags.String
+var only = flags.NewStringList(nil)
+var update = flags.NewBool(false)
+
+var Cmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run golden file tests for an API proxy or shared flow template",
+	Long: `
+This command looks for test cases in the "tests" directory next to the template. Each test case is a
+directory with a "test.yaml" file that lists the inputs (values, set, set-oas, set-grpc, set-graphql, etc.),
+and the expected output, either as YAML in "expected.yaml", or as a bundle tree in the "expected" directory.
+
+The template is rendered for each test case, and the result is compared with the expected output.
+Use the --update flag to write the expected output, instead of comparing with it.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tests, err := render.LoadTemplateTests(string(templateFile))
+		if err != nil {
+			return err
+		}
+
+		tests, err = render.SelectTemplateTests(tests, only)
+		if err != nil {
+			return err
+		}
+
+		progress := func(result render.TemplateTestResult) {
+			duration := result.Duration.Round(time.Millisecond)
+			if result.Err != nil {
+				fmt.Printf("FAIL %s (%s)\n%s\n", result.Name, duration, indent(result.Err.Error()))
+				return
+			}
+			if result.Updated {
+				fmt.Printf("UPDATED %s (%s)\n", result.Name, duration)
+				return
+			}
+			fmt.Printf("PASS %s (%s)\n", result.Name, duration)
+		}
+
+		results := render.RunTemplateTests(string(templateFile), tests, bool(update), progress)
+
+		failed := 0
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+
+		if update {
+			fmt.Printf("\n%d test(s) updated, %d failed\n", len(results)-failed, failed)
+		} else {
+			fmt.Printf("\n%d test(s) passed, %d failed\n", len(results)-failed, failed)
+		}
+		if failed > 0 {
+			return errors.Errorf("%d of %d test(s) failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
+
+func init() {
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&templateFile, "template", "t", `path to main template`)
+	Cmd.Flags().VarP(&only, "only", "", `name of test case to run (repeatable), runs all test cases if not set`)
+	Cmd.Flags().VarP(&update, "update", "", `writes the expected output of each test case, instead of comparing with it`)
+
+	_ = Cmd.MarkFlagRequired("template")
+}
@@ -0,0 +1,42 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package apiproxy_to_oas
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/spf13/cobra"
+)
+
+var input flags.String
+var output flags.String
+
+var Cmd = &cobra.Command{
+	Use:   "apiproxy-to-oas",
+	Short: "Generates an OpenAPI 3 Description from the flows of an API proxy",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return bundle.Bundle2OASFile(string(input), string(output))
+	},
+}
+
+func init() {
+
+	Cmd.Flags().SortFlags = false
+	Cmd.Flags().VarP(&input, "input", "i", "path to bundle zip, bundle dir, or YAML file")
+	Cmd.Flags().VarP(&output, "output", "o", "path to output file, or omit to use stdout")
+
+	_ = Cmd.MarkFlagRequired("input")
+
+}
@@ -16,6 +16,7 @@ package transform
 
 //goland:noinspection GoSnakeCaseUsage
 import (
+	apiproxy_to_oas "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/transform/apiproxy-to-oas"
 	apiproxy_to_yaml "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/transform/apiproxy-to-yaml"
 	json_to_tf "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/transform/json-to-tf"
 	json_to_yaml "github.com/apigee/apigee-go-gen/cmd/apigee-go-gen/transform/json-to-yaml"
@@ -45,6 +46,7 @@ func init() {
 	Cmd.AddCommand(sharedflow_to_yaml.Cmd)
 	Cmd.AddCommand(yaml_to_sharedflow.Cmd)
 	Cmd.AddCommand(oas2_to_oas3.Cmd)
+	Cmd.AddCommand(apiproxy_to_oas.Cmd)
 	Cmd.AddCommand(resolve_refs.Cmd)
 	Cmd.AddCommand(json_to_yaml.Cmd)
 	Cmd.AddCommand(yaml_to_json.Cmd)
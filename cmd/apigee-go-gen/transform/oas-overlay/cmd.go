@@ -16,8 +16,10 @@ package oas_overlay
 
 import (
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 var spec flags.String
@@ -28,7 +30,17 @@ var Cmd = &cobra.Command{
 	Use:   "oas-overlay",
 	Short: "Transforms OpenAPI Description by applying Overlay file",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return utils.OASOverlay(string(overlay), string(spec), string(output))
+		var gitSources git.Sources
+		overlayFile, err := git.LocalFile(string(overlay), &gitSources)
+		if err != nil {
+			return err
+		}
+		specFile, err := git.LocalFile(string(spec), &gitSources)
+		if err != nil {
+			return err
+		}
+		gitSources.Fprint(os.Stderr)
+		return utils.OASOverlay(overlayFile, specFile, string(output))
 	},
 }
 
@@ -16,8 +16,10 @@ package resolve_refs
 
 import (
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 var input flags.String
@@ -28,7 +30,13 @@ var Cmd = &cobra.Command{
 	Use:   "resolve-refs",
 	Short: "Resolves external $refs within the input JSON or YAML",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return utils.ResolveDollarRefs(string(input), string(output), bool(allowCycles))
+		var gitSources git.Sources
+		inputFile, err := git.LocalFile(string(input), &gitSources)
+		if err != nil {
+			return err
+		}
+		gitSources.Fprint(os.Stderr)
+		return utils.ResolveDollarRefs(inputFile, string(output), bool(allowCycles))
 	},
 }
 
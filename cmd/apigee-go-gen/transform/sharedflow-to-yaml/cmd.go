@@ -17,6 +17,7 @@ package sharedflow_to_yaml
 import (
 	"github.com/apigee/apigee-go-gen/pkg/flags"
 	"github.com/apigee/apigee-go-gen/pkg/sharedflow"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
 	"strings"
@@ -25,6 +26,9 @@ import (
 var input flags.String
 var output flags.String
 var dryRun = flags.NewBool(false)
+var maxZipEntries = flags.NewInt(zip.DefaultLimits.MaxEntries)
+var maxZipFileSize = flags.NewInt(int(zip.DefaultLimits.MaxFileSize))
+var maxZipSize = flags.NewInt(int(zip.DefaultLimits.MaxTotalSize))
 
 var Cmd = &cobra.Command{
 	Use:   "sharedflow-to-yaml",
@@ -34,7 +38,12 @@ var Cmd = &cobra.Command{
 			return errors.New("required flag(s) \"output\" not set")
 		}
 
-		return sharedflow.Bundle2YAMLFile(string(input), string(output), bool(dryRun))
+		limits := zip.Limits{
+			MaxEntries:   int(maxZipEntries),
+			MaxFileSize:  int64(maxZipFileSize),
+			MaxTotalSize: int64(maxZipSize),
+		}
+		return sharedflow.Bundle2YAMLFileLimits(string(input), string(output), bool(dryRun), limits)
 	},
 }
 
@@ -44,6 +53,9 @@ func init() {
 	Cmd.Flags().VarP(&input, "input", "i", "path to bundle zip or dir")
 	Cmd.Flags().VarP(&output, "output", "o", "path to output YAML file or dir")
 	Cmd.Flags().VarP(&dryRun, "dry-run", "d", "prints YAML document to stdout")
+	Cmd.Flags().VarP(&maxZipEntries, "max-zip-entries", "", "maximum number of entries within the bundle zip (default 10000)")
+	Cmd.Flags().VarP(&maxZipFileSize, "max-zip-file-size", "", "maximum decompressed size of each file within the bundle zip in bytes (default 100 MiB)")
+	Cmd.Flags().VarP(&maxZipSize, "max-zip-size", "", "maximum decompressed size of all files within the bundle zip in bytes (default 500 MiB)")
 
 	_ = Cmd.MarkFlagRequired("input")
 
@@ -18,6 +18,7 @@ import (
 	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
 	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/watch"
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
 	"strings"
@@ -27,11 +28,16 @@ var input flags.String
 var output flags.String
 var dryRun = flags.NewEnum([]string{"xml", "yaml"})
 var validate = flags.NewBool(true)
+var sha256Manifest = flags.NewBool(false)
+var watchMode = flags.NewBool(false)
 
 var Cmd = &cobra.Command{
 	Use:   "yaml-to-apiproxy",
 	Short: "Transforms a YAML file into an apiproxy",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchMode {
+			return watch.Command(cmd.Flags())
+		}
 
 		if strings.TrimSpace(string(output)) == "" && dryRun.IsUnset() {
 			return errors.New("required flag(s) \"output\" not set")
@@ -42,8 +48,8 @@ var Cmd = &cobra.Command{
 			return err
 		}
 
-		err = render.CreateBundle(model, string(output), bool(validate), dryRun.Value)
-
+		warnings, err := render.CreateBundleManifest(model, string(output), bool(validate), dryRun.Value, bool(sha256Manifest))
+		render.PrintWarnings(warnings)
 		return err
 
 	},
@@ -55,7 +61,9 @@ func init() {
 	Cmd.Flags().VarP(&input, "input", "i", "path to API proxy YAML file")
 	Cmd.Flags().VarP(&output, "output", "o", "path to output zip file or dir")
 	Cmd.Flags().VarP(&dryRun, "dry-run", "d", "print XML or YAML to stdout")
-	Cmd.Flags().VarP(&validate, "validate", "v", "check for unknown elements")
+	Cmd.Flags().VarP(&validate, "validate", "v", "check for unknown and missing elements")
+	Cmd.Flags().Var(&sha256Manifest, "sha256-manifest", `writes the SHA-256 of each bundle file next to the output, e.g. "apiproxy.zip.sha256"`)
+	Cmd.Flags().Var(&watchMode, watch.Flag, "transforms again each time the input file, or the files it references change")
 
 	_ = Cmd.MarkFlagRequired("input")
 }
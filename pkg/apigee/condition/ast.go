@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Operator string
+
+const (
+	OpAnd                   Operator = "and"
+	OpOr                    Operator = "or"
+	OpNot                   Operator = "not"
+	OpEquals                Operator = "="
+	OpNotEquals             Operator = "!="
+	OpEqualsCaseInsensitive Operator = ":="
+	OpGreaterThan           Operator = ">"
+	OpGreaterThanOrEquals   Operator = ">="
+	OpLesserThan            Operator = "<"
+	OpLesserThanOrEquals    Operator = "<="
+	OpMatches               Operator = "Matches"
+	OpJavaRegex             Operator = "JavaRegex"
+	OpMatchesPath           Operator = "MatchesPath"
+	OpStartsWith            Operator = "StartsWith"
+)
+
+var symbolOperators = map[string]Operator{
+	"&&": OpAnd,
+	"||": OpOr,
+	"!":  OpNot,
+	"=":  OpEquals,
+	"==": OpEquals,
+	"!=": OpNotEquals,
+	":=": OpEqualsCaseInsensitive,
+	">":  OpGreaterThan,
+	">=": OpGreaterThanOrEquals,
+	"<":  OpLesserThan,
+	"<=": OpLesserThanOrEquals,
+	"~":  OpMatches,
+	"~~": OpJavaRegex,
+	"~/": OpMatchesPath,
+	"=|": OpStartsWith,
+}
+
+// keywordOperators are matched case-insensitively
+var keywordOperators = map[string]Operator{
+	"and":                   OpAnd,
+	"or":                    OpOr,
+	"not":                   OpNot,
+	"equals":                OpEquals,
+	"is":                    OpEquals,
+	"notequals":             OpNotEquals,
+	"isnot":                 OpNotEquals,
+	"equalscaseinsensitive": OpEqualsCaseInsensitive,
+	"greaterthan":           OpGreaterThan,
+	"greaterthanorequals":   OpGreaterThanOrEquals,
+	"lesserthan":            OpLesserThan,
+	"lesserthanorequals":    OpLesserThanOrEquals,
+	"matches":               OpMatches,
+	"like":                  OpMatches,
+	"javaregex":             OpJavaRegex,
+	"matchespath":           OpMatchesPath,
+	"likepath":              OpMatchesPath,
+	"startswith":            OpStartsWith,
+}
+
+func (o Operator) IsLogical() bool {
+	return o == OpAnd || o == OpOr || o == OpNot
+}
+
+// Node is an element of a parsed condition
+type Node interface {
+	// Pos is the byte offset of the node within the condition
+	Pos() int
+	String() string
+}
+
+// BinaryExpr is either a comparison (e.g. request.verb = "GET") or a logical and/or
+type BinaryExpr struct {
+	Position int
+	Op       Operator
+	Left     Node
+	Right    Node
+}
+
+func (n *BinaryExpr) Pos() int {
+	return n.Position
+}
+
+func (n *BinaryExpr) String() string {
+	if !n.Op.IsLogical() {
+		return fmt.Sprintf("%s %s %s", n.Left, n.Op, n.Right)
+	}
+	return fmt.Sprintf("%s %s %s", n.operandString(n.Left), n.Op, n.operandString(n.Right))
+}
+
+func (n *BinaryExpr) operandString(operand Node) string {
+	if b, ok := operand.(*BinaryExpr); ok && b.Op.IsLogical() && b.Op != n.Op {
+		return fmt.Sprintf("(%s)", b)
+	}
+	return operand.String()
+}
+
+type NotExpr struct {
+	Position int
+	Operand  Node
+}
+
+func (n *NotExpr) Pos() int {
+	return n.Position
+}
+
+func (n *NotExpr) String() string {
+	if _, ok := n.Operand.(*BinaryExpr); ok {
+		return fmt.Sprintf("not (%s)", n.Operand)
+	}
+	return fmt.Sprintf("not %s", n.Operand)
+}
+
+// Variable is a reference to a flow variable such as request.header.Content-Type
+type Variable struct {
+	Position int
+	Name     string
+}
+
+func (n *Variable) Pos() int {
+	return n.Position
+}
+
+func (n *Variable) String() string {
+	return n.Name
+}
+
+type LiteralKind int
+
+const (
+	LiteralString LiteralKind = iota
+	LiteralNumber
+	LiteralBoolean
+	LiteralNull
+)
+
+type Literal struct {
+	Position int
+	Kind     LiteralKind
+	Value    string
+}
+
+func (n *Literal) Pos() int {
+	return n.Position
+}
+
+func (n *Literal) String() string {
+	if n.Kind != LiteralString {
+		return n.Value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(n.Value)
+	return fmt.Sprintf(`"%s"`, escaped)
+}
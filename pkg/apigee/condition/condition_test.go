@@ -0,0 +1,198 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		want      string
+		wantErr   string
+	}{
+		{
+			"comparison",
+			`request.verb = "POST"`,
+			`request.verb = "POST"`,
+			"",
+		},
+		{
+			"keyword operators",
+			`request.verb Equals "POST" AND request.header.Content-Type EqualsCaseInsensitive "application/json"`,
+			`request.verb = "POST" and request.header.Content-Type := "application/json"`,
+			"",
+		},
+		{
+			"grouping and negation",
+			`!(proxy.pathsuffix MatchesPath "/identity/api/auth/*") AND !(request.verb = "OPTIONS")`,
+			`not (proxy.pathsuffix MatchesPath "/identity/api/auth/*") and not (request.verb = "OPTIONS")`,
+			"",
+		},
+		{
+			"null and starts with",
+			`request.header.authorization = null || NOT (request.header.authorization =| "Bearer ")`,
+			`request.header.authorization = null or not (request.header.authorization StartsWith "Bearer ")`,
+			"",
+		},
+		{
+			"precedence",
+			`a = 1 or b = 2 and c ~~ "[0-9]+"`,
+			`a = 1 or (b = 2 and c JavaRegex "[0-9]+")`,
+			"",
+		},
+		{
+			"explicit grouping",
+			`(a = 1 or b = 2) and c ~ "foo*"`,
+			`(a = 1 or b = 2) and c Matches "foo*"`,
+			"",
+		},
+		{
+			"unquoted path",
+			`proxy.pathsuffix ~/ /pets/**`,
+			`proxy.pathsuffix MatchesPath "/pets/**"`,
+			"",
+		},
+		{
+			"escaped quote",
+			`request.content = "say \"hi\""`,
+			`request.content = "say \"hi\""`,
+			"",
+		},
+		{
+			"literal",
+			`true`,
+			`true`,
+			"",
+		},
+		{
+			"empty",
+			`  `,
+			"",
+			"syntax error at column 1: empty condition",
+		},
+		{
+			"missing closing paren",
+			`(request.verb = "GET"`,
+			"",
+			`syntax error at column 22: expected ")" to match "(" at column 1, found end of condition`,
+		},
+		{
+			"missing operand",
+			`request.verb = `,
+			"",
+			"syntax error at column 16: expected operand, found end of condition",
+		},
+		{
+			"operator as operand",
+			`request.verb = and`,
+			"",
+			`syntax error at column 16: expected operand, found operator "and"`,
+		},
+		{
+			"unterminated string",
+			`request.verb = "GET`,
+			"",
+			"syntax error at column 16: unterminated string",
+		},
+		{
+			"unexpected character",
+			`request.verb # "GET"`,
+			"",
+			"syntax error at column 14: unexpected character '#'",
+		},
+		{
+			"trailing tokens",
+			`request.verb = "GET" "POST"`,
+			"",
+			`syntax error at column 22: unexpected "POST"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.condition)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, node.String())
+
+			//the canonical form must parse to the same tree
+			reparsed, err := Parse(node.String())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, reparsed.String())
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]any{
+		"request.verb":                 "POST",
+		"request.header.Content-Type":  "Application/JSON",
+		"request.header.authorization": "Bearer abc",
+		"proxy.pathsuffix":             "/pets/123",
+		"response.status.code":         404,
+		"buff_ready":                   true,
+		"error_body":                   nil,
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   string
+	}{
+		{"equals", `request.verb = "POST"`, true, ""},
+		{"not equals", `request.verb != "POST"`, false, ""},
+		{"case sensitive", `request.header.Content-Type = "application/json"`, false, ""},
+		{"case insensitive", `request.header.Content-Type := "application/json"`, true, ""},
+		{"numeric", `response.status.code = 404.0`, true, ""},
+		{"greater than", `response.status.code > 399`, true, ""},
+		{"lesser than", `response.status.code LesserThan 300`, false, ""},
+		{"boolean", `buff_ready = true`, true, ""},
+		{"null", `error_body = null`, true, ""},
+		{"missing is null", `missing.variable = null`, true, ""},
+		{"not null", `error_body != null`, false, ""},
+		{"starts with", `request.header.authorization =| "Bearer "`, true, ""},
+		{"matches path", `proxy.pathsuffix MatchesPath "/pets/*"`, true, ""},
+		{"matches path single segment", `proxy.pathsuffix MatchesPath "/*"`, false, ""},
+		{"matches path multiple segments", `proxy.pathsuffix MatchesPath "/**"`, true, ""},
+		{"matches", `proxy.pathsuffix Matches "/p*"`, true, ""},
+		{"java regex", `proxy.pathsuffix ~~ "/pets/[0-9]+"`, true, ""},
+		{"java regex whole value", `proxy.pathsuffix ~~ "[0-9]+"`, false, ""},
+		{"invalid regex", `proxy.pathsuffix ~~ "[0-9"`, false, `invalid JavaRegex pattern "[0-9". error parsing regexp: missing closing ]: ` + "`[0-9)$`"},
+		{"and", `request.verb = "POST" and proxy.pathsuffix ~/ "/pets/*"`, true, ""},
+		{"or", `request.verb = "GET" or proxy.pathsuffix ~/ "/pets/*"`, true, ""},
+		{"not", `NOT (request.verb = "GET")`, true, ""},
+		{"variable", `buff_ready`, true, ""},
+		{"literal", `true`, true, ""},
+		{"missing variable", `missing.variable`, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateString(tt.condition, vars)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Evaluate evaluates the parsed condition against the given flow variables.
+// Variables that are missing from the map (or nil) are treated as null.
+func Evaluate(node Node, vars map[string]any) (bool, error) {
+	switch n := node.(type) {
+	case *BinaryExpr:
+		switch n.Op {
+		case OpAnd:
+			left, err := Evaluate(n.Left, vars)
+			if err != nil || !left {
+				return false, err
+			}
+			return Evaluate(n.Right, vars)
+		case OpOr:
+			left, err := Evaluate(n.Left, vars)
+			if err != nil || left {
+				return left, err
+			}
+			return Evaluate(n.Right, vars)
+		}
+		return compare(n, vars)
+	case *NotExpr:
+		result, err := Evaluate(n.Operand, vars)
+		return !result, err
+	case *Variable, *Literal:
+		value, ok := operandValue(n, vars)
+		return ok && strings.EqualFold(value, "true"), nil
+	}
+	return false, errors.Errorf("unsupported condition node %T", node)
+}
+
+// EvaluateString parses and evaluates the condition against the given flow variables
+func EvaluateString(condition string, vars map[string]any) (bool, error) {
+	node, err := Parse(condition)
+	if err != nil {
+		return false, err
+	}
+	return Evaluate(node, vars)
+}
+
+// operandValue returns the string value of the operand, and false if it is null
+func operandValue(node Node, vars map[string]any) (string, bool) {
+	switch n := node.(type) {
+	case *Literal:
+		if n.Kind == LiteralNull {
+			return "", false
+		}
+		return n.Value, true
+	case *Variable:
+		value, ok := vars[n.Name]
+		if !ok || value == nil {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+func compare(n *BinaryExpr, vars map[string]any) (bool, error) {
+	if _, ok := n.Left.(*BinaryExpr); ok {
+		return false, errors.Errorf("cannot compare expression %q using %s", n.Left.String(), n.Op)
+	}
+	if _, ok := n.Right.(*BinaryExpr); ok {
+		return false, errors.Errorf("cannot compare expression %q using %s", n.Right.String(), n.Op)
+	}
+
+	left, leftOk := operandValue(n.Left, vars)
+	right, rightOk := operandValue(n.Right, vars)
+
+	switch n.Op {
+	case OpEquals:
+		return equals(left, leftOk, right, rightOk), nil
+	case OpNotEquals:
+		return !equals(left, leftOk, right, rightOk), nil
+	case OpEqualsCaseInsensitive:
+		if !leftOk || !rightOk {
+			return leftOk == rightOk, nil
+		}
+		return strings.EqualFold(left, right), nil
+	}
+
+	if !leftOk || !rightOk {
+		return false, nil
+	}
+
+	switch n.Op {
+	case OpGreaterThan:
+		return order(left, right) > 0, nil
+	case OpGreaterThanOrEquals:
+		return order(left, right) >= 0, nil
+	case OpLesserThan:
+		return order(left, right) < 0, nil
+	case OpLesserThanOrEquals:
+		return order(left, right) <= 0, nil
+	case OpStartsWith:
+		return strings.HasPrefix(left, right), nil
+	case OpMatches:
+		return MatchWildcard(right, left), nil
+	case OpMatchesPath:
+		return MatchPath(right, left), nil
+	case OpJavaRegex:
+		regex, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", right))
+		if err != nil {
+			return false, errors.Errorf("invalid JavaRegex pattern %q. %s", right, err.Error())
+		}
+		return regex.MatchString(left), nil
+	}
+	return false, errors.Errorf("unsupported operator %s", n.Op)
+}
+
+func equals(left string, leftOk bool, right string, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return leftOk == rightOk
+	}
+	leftNum, leftErr := strconv.ParseFloat(left, 64)
+	rightNum, rightErr := strconv.ParseFloat(right, 64)
+	if leftErr == nil && rightErr == nil {
+		return leftNum == rightNum
+	}
+	return left == right
+}
+
+// order compares numerically when both values are numbers, and lexically otherwise
+func order(left string, right string) int {
+	leftNum, leftErr := strconv.ParseFloat(left, 64)
+	rightNum, rightErr := strconv.ParseFloat(right, 64)
+	if leftErr == nil && rightErr == nil {
+		switch {
+		case leftNum < rightNum:
+			return -1
+		case leftNum > rightNum:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(left, right)
+}
+
+// MatchWildcard reports whether the value matches the pattern, where "*" matches any sequence of characters
+func MatchWildcard(pattern string, value string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile(fmt.Sprintf("^%s$", strings.Join(parts, ".*"))).MatchString(value)
+}
+
+// MatchPath reports whether the path matches the pattern, where "*" matches a single
+// path segment and "**" matches any number of path segments
+func MatchPath(pattern string, path string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 || !MatchWildcard(pattern[0], path[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
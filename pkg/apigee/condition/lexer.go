@@ -0,0 +1,178 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenString
+	tokenNumber
+	tokenIdent
+	tokenSymbol
+	tokenPath
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of condition"
+	}
+	if t.kind == tokenString {
+		return t.text
+	}
+	return fmt.Sprintf(`"%s"`, t.text)
+}
+
+// SyntaxError is returned when a condition cannot be parsed
+type SyntaxError struct {
+	Condition string
+	Offset    int
+	Message   string
+}
+
+// Column is the 1-based position (in characters) of the error within the condition
+func (e *SyntaxError) Column() int {
+	return column(e.Condition, e.Offset)
+}
+
+func column(condition string, offset int) int {
+	return utf8.RuneCountInString(condition[:offset]) + 1
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Column(), e.Message)
+}
+
+func newSyntaxError(condition string, offset int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{
+		Condition: condition,
+		Offset:    offset,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// symbols are listed longest first, so that "~~" is matched before "~"
+var symbols = []string{"~~", "~/", "=|", "==", "!=", ":=", ">=", "<=", "&&", "||", "=", ">", "<", "~", "!"}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '.' || r == '-'
+}
+
+func lex(condition string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(condition) {
+		r, size := utf8.DecodeRuneInString(condition[i:])
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: start})
+			i += size
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: start})
+			i += size
+		case r == '"':
+			value, end, err := lexString(condition, start)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: condition[start:end], value: value, pos: start})
+			i = end
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(condition) && condition[i+1] >= '0' && condition[i+1] <= '9'):
+			i++
+			for i < len(condition) && (condition[i] >= '0' && condition[i] <= '9' || condition[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: condition[start:i], value: condition[start:i], pos: start})
+		case isIdentStart(r):
+			for i < len(condition) {
+				r, size = utf8.DecodeRuneInString(condition[i:])
+				if !isIdentPart(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: condition[start:i], value: condition[start:i], pos: start})
+		case r == '/':
+			//unquoted path literals are allowed as the right operand of MatchesPath
+			for i < len(condition) {
+				r, size = utf8.DecodeRuneInString(condition[i:])
+				if unicode.IsSpace(r) || r == ')' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenPath, text: condition[start:i], value: condition[start:i], pos: start})
+		default:
+			symbol := ""
+			for _, s := range symbols {
+				if strings.HasPrefix(condition[i:], s) {
+					symbol = s
+					break
+				}
+			}
+			if symbol == "" {
+				return nil, newSyntaxError(condition, start, "unexpected character '%c'", r)
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: symbol, value: symbol, pos: start})
+			i += len(symbol)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(condition)})
+	return tokens, nil
+}
+
+func lexString(condition string, start int) (string, int, error) {
+	var value strings.Builder
+	i := start + 1
+	for i < len(condition) {
+		c := condition[i]
+		switch {
+		case c == '\\' && i+1 < len(condition):
+			value.WriteByte(condition[i+1])
+			i += 2
+		case c == '"':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, newSyntaxError(condition, start, "unterminated string")
+}
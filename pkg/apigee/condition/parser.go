@@ -0,0 +1,176 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"strings"
+)
+
+type parser struct {
+	condition string
+	tokens    []token
+	current   int
+}
+
+// Parse parses an Apigee condition expression such as
+//
+//	(proxy.pathsuffix MatchesPath "/pets/*") and (request.verb = "GET")
+//
+// The returned error is a *SyntaxError holding the position of the problem.
+func Parse(condition string) (Node, error) {
+	tokens, err := lex(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{condition: condition, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, newSyntaxError(condition, 0, "empty condition")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.errorAt(next, "unexpected %s", next)
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.current]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.current]
+	if t.kind != tokenEOF {
+		p.current++
+	}
+	return t
+}
+
+func (p *parser) errorAt(t token, format string, args ...any) error {
+	return newSyntaxError(p.condition, t.pos, format, args...)
+}
+
+// operator returns the operator for the token, if it is one
+func (p *parser) operator(t token) (Operator, bool) {
+	switch t.kind {
+	case tokenSymbol:
+		op, ok := symbolOperators[t.text]
+		return op, ok
+	case tokenIdent:
+		op, ok := keywordOperators[strings.ToLower(t.text)]
+		return op, ok
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseLogical(OpOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseLogical(OpAnd, p.parseUnary)
+}
+
+func (p *parser) parseLogical(op Operator, parseOperand func() (Node, error)) (Node, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if tokenOp, ok := p.operator(t); !ok || tokenOp != op {
+			return left, nil
+		}
+		p.next()
+
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Position: t.pos, Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	t := p.peek()
+	if op, ok := p.operator(t); ok && op == OpNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Position: t.pos, Operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	op, ok := p.operator(t)
+	if !ok || op.IsLogical() {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &BinaryExpr{Position: t.pos, Op: op, Left: left, Right: right}, nil
+}
+
+func (p *parser) parseOperand() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, `expected ")" to match "(" at column %d, found %s`, column(p.condition, t.pos), closing)
+		}
+		return node, nil
+	case tokenString, tokenPath:
+		return &Literal{Position: t.pos, Kind: LiteralString, Value: t.value}, nil
+	case tokenNumber:
+		return &Literal{Position: t.pos, Kind: LiteralNumber, Value: t.value}, nil
+	case tokenIdent:
+		if _, ok := p.operator(t); ok {
+			return nil, p.errorAt(t, "expected operand, found operator %s", t)
+		}
+		switch strings.ToLower(t.text) {
+		case "null":
+			return &Literal{Position: t.pos, Kind: LiteralNull, Value: "null"}, nil
+		case "true", "false":
+			return &Literal{Position: t.pos, Kind: LiteralBoolean, Value: strings.ToLower(t.text)}, nil
+		}
+		return &Variable{Position: t.pos, Name: t.text}, nil
+	case tokenSymbol:
+		return nil, p.errorAt(t, "expected operand, found operator %s", t)
+	}
+	return nil, p.errorAt(t, "expected operand, found %s", t)
+}
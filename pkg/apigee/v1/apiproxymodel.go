@@ -19,12 +19,17 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
-	"path/filepath"
+	"io/fs"
 )
 
 func NewAPIProxyModel(input string) (*APIProxyModel, error) {
+	return NewAPIProxyModelFS(utils.OSFS, input)
+}
+
+// NewAPIProxyModelFS is like NewAPIProxyModel, but the YAML file (and the files it references) are read from fsys
+func NewAPIProxyModelFS(fsys fs.FS, input string) (*APIProxyModel, error) {
 	proxyModel := &APIProxyModel{}
-	err := proxyModel.Hydrate(input)
+	err := proxyModel.HydrateFS(fsys, input)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +46,8 @@ type APIProxyModel struct {
 
 	UnknownNode AnyList `xml:",any"`
 
-	YAMLDoc *yaml.Node `xml:"-"`
+	YAMLDoc       *yaml.Node           `xml:"-"`
+	YAMLPositions *utils.YAMLPositions `xml:"-"`
 }
 
 func (a *APIProxyModel) Name() string {
@@ -68,26 +74,44 @@ func (a *APIProxyModel) Validate() error {
 	err := utils.MultiError{Errors: []error{}}
 	path := "Root"
 	if len(a.UnknownNode) > 0 {
-		err.Errors = append(err.Errors, NewUnknownNodeError(path, a.UnknownNode[0]))
+		err.Errors = append(err.Errors, WithPositions([]error{NewUnknownNodeError(path, a.UnknownNode[0])}, a.YAMLDoc, a.YAMLPositions)...)
 		return err
 	}
 
 	var subErrors []error
 	subErrors = append(subErrors, ValidateAPIProxy(&a.APIProxy, path)...)
+	subErrors = append(subErrors, ValidatePolicies(&a.Policies, path)...)
 	subErrors = append(subErrors, ValidateProxyEndpoints(&a.ProxyEndpoints, path)...)
 	subErrors = append(subErrors, ValidateTargetEndpoints(&a.TargetEndpoints, path)...)
 	subErrors = append(subErrors, ValidateIntegrationEndpoints(&a.IntegrationEndpoints, path)...)
 
 	subErrors = append(subErrors, ValidateResources(&a.Resources, path)...)
 
+	referenceErrors, _ := ValidateReferences(a)
+	subErrors = append(subErrors, referenceErrors...)
+	subErrors = append(subErrors, ValidateConditions(a)...)
+
 	if len(subErrors) > 0 {
-		err.Errors = append(err.Errors, subErrors...)
+		err.Errors = append(err.Errors, WithPositions(subErrors, a.YAMLDoc, a.YAMLPositions)...)
 		return err
 	}
 
 	return nil
 }
 
+func (a *APIProxyModel) Warnings() []error {
+	if a == nil {
+		return nil
+	}
+
+	_, warnings := ValidateReferences(a)
+	return WithPositions(warnings, a.YAMLDoc, a.YAMLPositions)
+}
+
+func (a *APIProxyModel) Positions() *utils.YAMLPositions {
+	return a.YAMLPositions
+}
+
 func (a *APIProxyModel) BundleRoot() string {
 	return "apiproxy"
 }
@@ -129,9 +153,13 @@ func (a *APIProxyModel) YAML() ([]byte, error) {
 }
 
 func (a *APIProxyModel) Hydrate(filePath string) error {
+	return a.HydrateFS(utils.OSFS, filePath)
+}
+
+func (a *APIProxyModel) HydrateFS(fsys fs.FS, filePath string) error {
 	var err error
 
-	a.YAMLDoc, err = utils.YAMLFile2YAML(filePath)
+	a.YAMLDoc, a.YAMLPositions, err = utils.YAMLFile2YAMLWithPositionsFS(fsys, filePath)
 
 	if err != nil {
 		return err
@@ -149,7 +177,7 @@ func (a *APIProxyModel) Hydrate(filePath string) error {
 		return errors.New(err)
 	}
 
-	err = HydrateResources(a, filepath.Dir(filePath))
+	err = HydrateResourcesFS(a, fsys, utils.FSDir(fsys, filePath))
 	if err != nil {
 		return errors.New(err)
 	}
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -89,3 +90,105 @@ func TestNewAPIProxyModel(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIProxyModel_Validate(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		wantErr      string
+		wantWarnings []string
+	}{
+		{
+			"policies-valid",
+			"",
+			nil,
+		},
+		{
+			"policy-unknown-node",
+			`testdata/validate/policy-unknown-node/apiproxy.yaml:20:7: unknown node "AssignTooo" found at "Root.Policies.0.AssignMessage(name: AM-SetHeaders)"`,
+			nil,
+		},
+		{
+			"policy-missing-node",
+			`testdata/validate/policy-missing-node/apiproxy.yaml:18:5: missing node "Rate" at "Root.Policies.0.SpikeArrest(name: SA-Default)"`,
+			nil,
+		},
+		{
+			"policy-missing-one-of",
+			`testdata/validate/policy-missing-one-of/apiproxy.yaml:18:5: missing node at "Root.Policies.0.ExtractVariables(name: EV-Empty)", one of URIPath, QueryParam, Header, FormParam, JSONPayload, XMLPayload is required
+testdata/validate/policy-missing-one-of/apiproxy.yaml:21:5: missing node at "Root.Policies.1.MessageLogging(name: ML-Empty)", one of Syslog, CloudLogging, File is required`,
+			nil,
+		},
+		{
+			"ref-position",
+			`testdata/validate/ref-position/apiproxy.yaml:17:1: unknown node "AssignTooo" found at "Root.Policies.0.AssignMessage(name: AM-SetHeaders)"`,
+			nil,
+		},
+		{
+			"dangling-references",
+			`testdata/validate/dangling-references/apiproxy.yaml:33:7: target endpoint "missing" referenced at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).RouteRules.0.RouteRule(name: default)" does not exist
+testdata/validate/dangling-references/apiproxy.yaml:29:13: policy "JS-Missing" referenced at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).PreFlow.Request.Steps.1.Step" does not exist
+testdata/validate/dangling-references/apiproxy.yaml:18:5: resource "jsc://missing.js" referenced at "Root.Policies.0.Javascript(name: JS-Hello)" does not exist`,
+			nil,
+		},
+		{
+			"invalid-condition",
+			`testdata/validate/invalid-condition/apiproxy.yaml:29:15: invalid condition '(request.verb = "GET"' at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).PreFlow.Request.Steps.0.Step.Condition". syntax error at column 22: expected ")" to match "(" at column 1, found end of condition
+testdata/validate/invalid-condition/apiproxy.yaml:33:13: invalid condition '(proxy.pathsuffix MatchesPath "/pets") and (request.verb = )' at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).Flows.0.Flow.Condition". syntax error at column 60: expected operand, found ")"
+testdata/validate/invalid-condition/apiproxy.yaml:38:9: invalid condition 'request.verb ! "GET"' at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).RouteRules.0.RouteRule(name: default).Condition". syntax error at column 14: unexpected "!"`,
+			nil,
+		},
+		{
+			"step-unknown-node",
+			`testdata/validate/step-unknown-node/apiproxy.yaml:29:15: unknown node "Conditon" found at "Root.ProxyEndpoints.0.ProxyEndpoint(name: default).PreFlow.Request.Steps.0.Step"`,
+			nil,
+		},
+		{
+			"unused-references",
+			"",
+			[]string{
+				`testdata/validate/unused-references/apiproxy.yaml:21:5: policy "JS-Unused" at "Root.Policies.1.Javascript(name: JS-Unused)" is not referenced`,
+				`testdata/validate/unused-references/apiproxy.yaml:46:5: resource "jsc://unused.js" at "Root.Resources.1.Resource" is not referenced`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		ttDir := filepath.Join("testdata", "validate", tt.name)
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile := filepath.Join(ttDir, "apiproxy.yaml")
+
+			model, err := NewAPIProxyModel(inputFile)
+			require.NoError(t, err)
+
+			if tt.wantWarnings != nil {
+				var warnings []string
+				for _, warning := range model.Warnings() {
+					warnings = append(warnings, warning.Error())
+				}
+				require.Equal(t, tt.wantWarnings, warnings)
+			}
+
+			err = model.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBundleManifest(t *testing.T) {
+	files := map[string][]byte{
+		"apiproxy/proxies/default.xml": []byte("<ProxyEndpoint/>"),
+		"apiproxy/hello.xml":           []byte("<APIProxy/>"),
+	}
+
+	manifest := string(BundleManifest(files, "git https://example.com/templates.git/-/main#apiproxy.yaml main 0123abcd"))
+	lines := strings.Split(strings.TrimSuffix(manifest, "\n"), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "# git https://example.com/templates.git/-/main#apiproxy.yaml main 0123abcd", lines[0])
+	require.Regexp(t, `^[0-9a-f]{64}  apiproxy/hello.xml$`, lines[1])
+	require.Regexp(t, `^[0-9a-f]{64}  apiproxy/proxies/default.xml$`, lines[2])
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/condition"
+)
+
+type InvalidConditionError struct {
+	Location  string
+	Condition string
+	Err       error
+}
+
+func (e *InvalidConditionError) Error() string {
+	return fmt.Sprintf(`invalid condition '%s' at "%s". %s`, e.Condition, e.Location, e.Err.Error())
+}
+
+func (e *InvalidConditionError) Unwrap() error {
+	return e.Err
+}
+
+func NewInvalidConditionError(location string, condition string, err error) *InvalidConditionError {
+	return &InvalidConditionError{
+		Location:  location,
+		Condition: condition,
+		Err:       err,
+	}
+}
+
+func ValidateCondition(v string, path string) []error {
+	if v == "" {
+		return nil
+	}
+
+	if _, err := condition.Parse(v); err != nil {
+		return []error{NewInvalidConditionError(fmt.Sprintf("%s.Condition", path), v, err)}
+	}
+
+	return nil
+}
+
+// ValidateConditions checks the syntax of the conditions within the API proxy or shared flow model (steps, flows, route rules and fault rules)
+func ValidateConditions(model Model) []error {
+	var errs []error
+	for _, location := range StepLocations(model) {
+		errs = append(errs, ValidateCondition(location.Step.Condition, location.Path)...)
+	}
+
+	apiProxy, ok := model.(*APIProxyModel)
+	if !ok {
+		return errs
+	}
+
+	for i, proxyEndpoint := range apiProxy.ProxyEndpoints.List {
+		path := fmt.Sprintf("Root.ProxyEndpoints.%v.ProxyEndpoint(name: %s)", i, proxyEndpoint.Name)
+		errs = append(errs, flowsConditionErrors(proxyEndpoint.Flows, path)...)
+		if proxyEndpoint.RouteRules != nil {
+			for j, routeRule := range *proxyEndpoint.RouteRules {
+				if routeRule == nil {
+					continue
+				}
+				errs = append(errs, ValidateCondition(routeRule.Condition, fmt.Sprintf("%s.RouteRules.%v.RouteRule(name: %s)", path, j, routeRule.Name))...)
+			}
+		}
+		errs = append(errs, faultRulesConditionErrors(proxyEndpoint.FaultRules, path)...)
+	}
+
+	for i, targetEndpoint := range apiProxy.TargetEndpoints.List {
+		path := fmt.Sprintf("Root.TargetEndpoints.%v.TargetEndpoint(name: %s)", i, targetEndpoint.Name)
+		errs = append(errs, flowsConditionErrors(&targetEndpoint.Flows, path)...)
+		errs = append(errs, faultRulesConditionErrors(targetEndpoint.FaultRules, path)...)
+	}
+	return errs
+}
+
+func flowsConditionErrors(flows *Flows, path string) []error {
+	if flows == nil {
+		return nil
+	}
+	var errs []error
+	for i, flow := range flows.List {
+		if flow == nil {
+			continue
+		}
+		errs = append(errs, ValidateCondition(flow.Condition, fmt.Sprintf("%s.Flows.%v.Flow", path, i))...)
+	}
+	return errs
+}
+
+func faultRulesConditionErrors(faultRules *FaultRules, path string) []error {
+	if faultRules == nil {
+		return nil
+	}
+	var errs []error
+	for i, faultRule := range faultRules.List {
+		if faultRule == nil {
+			continue
+		}
+		errs = append(errs, ValidateCondition(faultRule.Condition, fmt.Sprintf("%s.FaultRules.%v.FaultRule", path, i))...)
+	}
+	return errs
+}
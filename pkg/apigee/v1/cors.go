@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type CORS struct {
+	PolicyCommon
+
+	AllowOrigins              string `xml:"AllowOrigins,omitempty"`
+	AllowMethods              string `xml:"AllowMethods,omitempty"`
+	AllowHeaders              string `xml:"AllowHeaders,omitempty"`
+	ExposeHeaders             string `xml:"ExposeHeaders,omitempty"`
+	MaxAge                    string `xml:"MaxAge,omitempty"`
+	AllowCredentials          string `xml:"AllowCredentials,omitempty"`
+	GeneratePreflightResponse string `xml:"GeneratePreflightResponse,omitempty"`
+	IgnoreUnresolvedVariables string `xml:"IgnoreUnresolvedVariables,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateCORS(v *CORS, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.CORS(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.AllowOrigins == "" {
+		subErrors = append(subErrors, NewMissingNodeError(subPath, "AllowOrigins"))
+	}
+
+	return subErrors
+}
@@ -37,5 +37,5 @@ func ValidateDefaultFaultRule(v *DefaultFaultRule, path string) []error {
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSteps(&v.Steps, subPath)...)
 
-	return nil
+	return subErrors
 }
@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type ExtractVariables struct {
+	PolicyCommon
+
+	Source                    *ExtractVariablesSource `xml:"Source,omitempty"`
+	VariablePrefix            string                  `xml:"VariablePrefix,omitempty"`
+	IgnoreUnresolvedVariables string                  `xml:"IgnoreUnresolvedVariables,omitempty"`
+	Headers                   AnyList                 `xml:"Header,omitempty"`
+	QueryParams               AnyList                 `xml:"QueryParam,omitempty"`
+	FormParams                AnyList                 `xml:"FormParam,omitempty"`
+	URIPaths                  AnyList                 `xml:"URIPath,omitempty"`
+	JSONPayload               *AnyNode                `xml:"JSONPayload,omitempty"`
+	XMLPayload                *AnyNode                `xml:"XMLPayload,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateExtractVariables(v *ExtractVariables, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.ExtractVariables(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if len(v.Headers) == 0 && len(v.QueryParams) == 0 && len(v.FormParams) == 0 &&
+		len(v.URIPaths) == 0 && v.JSONPayload == nil && v.XMLPayload == nil {
+		subErrors = append(subErrors, NewMissingOneOfNodeError(subPath, "URIPath", "QueryParam", "Header", "FormParam", "JSONPayload", "XMLPayload"))
+	}
+
+	return subErrors
+}
+
+type ExtractVariablesSource struct {
+	ClearPayload string `xml:"clearPayload,attr,omitempty"`
+	Value        string `xml:",chardata"`
+}
@@ -37,5 +37,5 @@ func ValidateFaultRule(v *FaultRule, path string) []error {
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSteps(&v.Steps, subPath)...)
 
-	return nil
+	return subErrors
 }
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type FlowCallout struct {
+	PolicyCommon
+
+	Parameters       *FlowCalloutParameters `xml:"Parameters,omitempty"`
+	SharedFlowBundle string                 `xml:"SharedFlowBundle,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateFlowCallout(v *FlowCallout, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.FlowCallout(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.SharedFlowBundle == "" {
+		subErrors = append(subErrors, NewMissingNodeError(subPath, "SharedFlowBundle"))
+	}
+	subErrors = append(subErrors, ValidateFlowCalloutParameters(v.Parameters, subPath)...)
+
+	return subErrors
+}
+
+type FlowCalloutParameter struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr,omitempty"`
+	Ref   string `xml:"ref,attr,omitempty"`
+	Text  string `xml:",chardata"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+type FlowCalloutParameters struct {
+	List []*FlowCalloutParameter `xml:"Parameter,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateFlowCalloutParameters(v *FlowCalloutParameters, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Parameters", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	for i, vv := range v.List {
+		paramPath := fmt.Sprintf("%s.Parameter.%v", subPath, i)
+		if len(vv.UnknownNode) > 0 {
+			return []error{NewUnknownNodeError(paramPath, vv.UnknownNode[0])}
+		}
+		if vv.Name == "" {
+			return []error{NewMissingNodeError(paramPath, ".name")}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+// The message operations below are shared by AssignMessage, RaiseFault and ServiceCallout
+
+type MessagePayload struct {
+	ContentType    string `xml:"contentType,attr,omitempty"`
+	VariablePrefix string `xml:"variablePrefix,attr,omitempty"`
+	VariableSuffix string `xml:"variableSuffix,attr,omitempty"`
+	Value          string `xml:",chardata"`
+
+	//XML payloads are allowed to contain any elements
+	Content AnyList `xml:",any"`
+}
+
+type MessageAdd struct {
+	Headers     *MessageHeaders     `xml:"Headers,omitempty"`
+	QueryParams *MessageQueryParams `xml:"QueryParams,omitempty"`
+	FormParams  *MessageFormParams  `xml:"FormParams,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageAdd(v *MessageAdd, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Add", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateMessageHeaders(v.Headers, subPath)...)
+	subErrors = append(subErrors, ValidateMessageQueryParams(v.QueryParams, subPath)...)
+	subErrors = append(subErrors, ValidateMessageFormParams(v.FormParams, subPath)...)
+
+	return subErrors
+}
+
+type MessageCopy struct {
+	Source       string              `xml:"source,attr,omitempty"`
+	Headers      *MessageHeaders     `xml:"Headers,omitempty"`
+	QueryParams  *MessageQueryParams `xml:"QueryParams,omitempty"`
+	FormParams   *MessageFormParams  `xml:"FormParams,omitempty"`
+	Payload      string              `xml:"Payload,omitempty"`
+	Path         string              `xml:"Path,omitempty"`
+	StatusCode   string              `xml:"StatusCode,omitempty"`
+	ReasonPhrase string              `xml:"ReasonPhrase,omitempty"`
+	Verb         string              `xml:"Verb,omitempty"`
+	Version      string              `xml:"Version,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageCopy(v *MessageCopy, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Copy", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateMessageHeaders(v.Headers, subPath)...)
+	subErrors = append(subErrors, ValidateMessageQueryParams(v.QueryParams, subPath)...)
+	subErrors = append(subErrors, ValidateMessageFormParams(v.FormParams, subPath)...)
+
+	return subErrors
+}
+
+type MessageRemove struct {
+	Headers     *MessageHeaders     `xml:"Headers,omitempty"`
+	QueryParams *MessageQueryParams `xml:"QueryParams,omitempty"`
+	FormParams  *MessageFormParams  `xml:"FormParams,omitempty"`
+	Payload     string              `xml:"Payload,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageRemove(v *MessageRemove, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Remove", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateMessageHeaders(v.Headers, subPath)...)
+	subErrors = append(subErrors, ValidateMessageQueryParams(v.QueryParams, subPath)...)
+	subErrors = append(subErrors, ValidateMessageFormParams(v.FormParams, subPath)...)
+
+	return subErrors
+}
+
+type MessageSet struct {
+	Headers      *MessageHeaders     `xml:"Headers,omitempty"`
+	QueryParams  *MessageQueryParams `xml:"QueryParams,omitempty"`
+	FormParams   *MessageFormParams  `xml:"FormParams,omitempty"`
+	Payload      *MessagePayload     `xml:"Payload,omitempty"`
+	Path         string              `xml:"Path,omitempty"`
+	StatusCode   string              `xml:"StatusCode,omitempty"`
+	ReasonPhrase string              `xml:"ReasonPhrase,omitempty"`
+	Verb         string              `xml:"Verb,omitempty"`
+	Version      string              `xml:"Version,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageSet(v *MessageSet, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Set", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateMessageHeaders(v.Headers, subPath)...)
+	subErrors = append(subErrors, ValidateMessageQueryParams(v.QueryParams, subPath)...)
+	subErrors = append(subErrors, ValidateMessageFormParams(v.FormParams, subPath)...)
+
+	return subErrors
+}
+
+type AssignVariable struct {
+	Name           string `xml:"Name"`
+	Value          string `xml:"Value,omitempty"`
+	Ref            string `xml:"Ref,omitempty"`
+	Template       string `xml:"Template,omitempty"`
+	ResourceURL    string `xml:"ResourceURL,omitempty"`
+	PropertySetRef string `xml:"PropertySetRef,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+type AssignVariableList []*AssignVariable
+
+func ValidateAssignVariables(v AssignVariableList, path string) []error {
+	for i, vv := range v {
+		subPath := fmt.Sprintf("%s.AssignVariable.%v", path, i)
+		if len(vv.UnknownNode) > 0 {
+			return []error{NewUnknownNodeError(subPath, vv.UnknownNode[0])}
+		}
+		if vv.Name == "" {
+			return []error{NewMissingNodeError(subPath, "Name")}
+		}
+	}
+
+	return nil
+}
+
+type AssignTo struct {
+	CreateNew string `xml:"createNew,attr,omitempty"`
+	Transport string `xml:"transport,attr,omitempty"`
+	Type      string `xml:"type,attr,omitempty"`
+	Value     string `xml:",chardata"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateAssignTo(v *AssignTo, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.AssignTo", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+type MissingNodeError struct {
+	Location string
+	NodeName string
+
+	// OneOf lists the accepted nodes when any one of them is required, instead of NodeName
+	OneOf []string
+
+	Stack []byte
+}
+
+func (e *MissingNodeError) Error() string {
+	if len(e.OneOf) > 0 {
+		return fmt.Sprintf(`missing node at "%s", one of %s is required`, e.Location, strings.Join(e.OneOf, ", "))
+	}
+	return fmt.Sprintf(`missing node "%s" at "%s"`, e.NodeName, e.Location)
+}
+
+func (e *MissingNodeError) String() string {
+	return fmt.Sprintf("%s\n%s", e.Error(), e.Stack)
+}
+
+func NewMissingNodeError(location string, nodeName string) *MissingNodeError {
+	return &MissingNodeError{
+		Location: location,
+		NodeName: nodeName,
+		Stack:    debug.Stack(),
+	}
+}
+
+// NewMissingOneOfNodeError is for elements that require at least one of the given child elements
+func NewMissingOneOfNodeError(location string, nodeNames ...string) *MissingNodeError {
+	return &MissingNodeError{
+		Location: location,
+		OneOf:    nodeNames,
+		Stack:    debug.Stack(),
+	}
+}
@@ -15,12 +15,18 @@
 package v1
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/apigee/apigee-go-gen/pkg/zip"
 	"github.com/go-errors/errors"
+	"io/fs"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 type Model interface {
@@ -31,11 +37,28 @@ type Model interface {
 	BundleFiles() []BundleFile
 	BundleRoot() string
 	Hydrate(filePath string) error
+	HydrateFS(fsys fs.FS, filePath string) error
 	XML() ([]byte, error)
 	YAML() ([]byte, error)
 	GetResources() *Resources
 }
 
+// ModelWarnings returns the problems that do not fail validation (e.g. unused policies), for models that report them
+func ModelWarnings(model Model) []error {
+	if warner, ok := model.(interface{ Warnings() []error }); ok {
+		return warner.Warnings()
+	}
+	return nil
+}
+
+// ModelPositions returns the location of each YAML node the model was created from, for models that keep them
+func ModelPositions(model Model) *utils.YAMLPositions {
+	if positioned, ok := model.(interface{ Positions() *utils.YAMLPositions }); ok {
+		return positioned.Positions()
+	}
+	return nil
+}
+
 func Model2Bundle(model Model, output string) error {
 	extension := filepath.Ext(output)
 	if extension == ".zip" {
@@ -57,29 +80,19 @@ func Model2Bundle(model Model, output string) error {
 }
 
 func Model2BundleDir(model Model, output string) error {
-	err := os.MkdirAll(output, os.ModePerm)
+	bundleFiles, err := Model2BundleFiles(model)
 	if err != nil {
-		return errors.New(err)
+		return err
 	}
 
-	bundleFiles := model.BundleFiles()
-	for _, bundleFile := range bundleFiles {
-		filePath := filepath.Join(model.BundleRoot(), bundleFile.FilePath())
-		fileDir := filepath.Dir(filePath)
-
-		dirDiskPath := filepath.Join(output, fileDir)
-		err := os.MkdirAll(dirDiskPath, os.ModePerm)
+	for _, filePath := range slices.Sorted(maps.Keys(bundleFiles)) {
+		fileDiskPath := filepath.Join(output, filepath.FromSlash(filePath))
+		err := os.MkdirAll(filepath.Dir(fileDiskPath), os.ModePerm)
 		if err != nil {
 			return errors.New(err)
 		}
 
-		fileContent, err := bundleFile.FileContents()
-		if err != nil {
-			return err
-		}
-
-		fileDiskPath := filepath.Join(output, filePath)
-		err = os.WriteFile(fileDiskPath, fileContent, os.ModePerm)
+		err = os.WriteFile(fileDiskPath, bundleFiles[filePath], os.ModePerm)
 		if err != nil {
 			return errors.New(err)
 		}
@@ -89,36 +102,100 @@ func Model2BundleDir(model Model, output string) error {
 }
 
 func Model2BundleZip(model Model, outputZip string) error {
-	tmpDir, err := os.MkdirTemp("", "unzipped-bundle-*")
+	zipBytes, err := Model2BundleZipBytes(model)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(outputZip), os.ModePerm)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	err = Model2BundleDir(model, tmpDir)
+	err = os.WriteFile(outputZip, zipBytes, os.ModePerm)
 	if err != nil {
-		return err
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// Model2BundleFiles returns the bundle files in memory. It maps the slash-separated path of each file
+// (e.g. "apiproxy/policies/AM-Example.xml") to its contents.
+func Model2BundleFiles(model Model) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, bundleFile := range model.BundleFiles() {
+		fileContent, err := bundleFile.FileContents()
+		if err != nil {
+			return nil, err
+		}
+
+		filePath := filepath.ToSlash(filepath.Join(model.BundleRoot(), bundleFile.FilePath()))
+		files[filePath] = fileContent
 	}
+	return files, nil
+}
 
-	err = zip.Zip(outputZip, tmpDir)
+// Model2BundleZipBytes returns the bundle zip in memory
+func Model2BundleZipBytes(model Model) ([]byte, error) {
+	files, err := Model2BundleFiles(model)
+	if err != nil {
+		return nil, err
+	}
+	return zip.ZipFiles(files)
+}
+
+// ManifestExtension is appended to the bundle output (e.g. "apiproxy.zip.sha256") to name its manifest file
+const ManifestExtension = ".sha256"
+
+// BundleManifest lists the SHA-256 of each bundle file sorted by path, in the format of sha256sum
+// (e.g. "<hex>  apiproxy/policies/AM-Example.xml"). It only depends on the contents of the bundle files,
+// so CI can compare it with the previous manifest to skip deploying a bundle that has not changed.
+// Each comment is written first as a line starting with "# ", which sha256sum ignores.
+func BundleManifest(files map[string][]byte, comments ...string) []byte {
+	manifest := bytes.Buffer{}
+	for _, comment := range comments {
+		manifest.WriteString(fmt.Sprintf("# %s\n", comment))
+	}
+	for _, filePath := range slices.Sorted(maps.Keys(files)) {
+		manifest.WriteString(fmt.Sprintf("%x  %s\n", sha256.Sum256(files[filePath]), filePath))
+	}
+	return manifest.Bytes()
+}
+
+// Model2BundleManifest writes the manifest of the bundle files (see BundleManifest) to manifestFile
+func Model2BundleManifest(model Model, manifestFile string, comments ...string) error {
+	files, err := Model2BundleFiles(model)
 	if err != nil {
 		return err
 	}
 
+	err = os.WriteFile(manifestFile, BundleManifest(files, comments...), os.ModePerm)
+	if err != nil {
+		return errors.New(err)
+	}
 	return nil
 }
 
+// HydrateResources reads the content of each resource, resource paths are relative to fromDir (e.g. the directory of the YAML file)
 func HydrateResources(model Model, fromDir string) error {
-	// switch to directory relative to the YAML file so that resource paths are valid
-	popd := utils.PushDir(fromDir)
-	defer popd()
+	return HydrateResourcesFS(model, utils.OSFS, fromDir)
+}
 
+// HydrateResourcesFS is like HydrateResources, but resources are read from fsys
+func HydrateResourcesFS(model Model, fsys fs.FS, fromDir string) error {
 	for _, resource := range model.GetResources().List {
 		parsedUrl, err := url.Parse(resource.Path)
 		if err != nil {
 			return errors.New(err)
 		}
 
-		content, err := os.ReadFile(parsedUrl.Path)
+		resourcePath := parsedUrl.Path
+		if !utils.FSIsAbs(fsys, resourcePath) {
+			resourcePath = utils.FSJoin(fsys, fromDir, resourcePath)
+		}
+
+		content, err := fs.ReadFile(fsys, resourcePath)
 		if err != nil {
 			return errors.New(err)
 		}
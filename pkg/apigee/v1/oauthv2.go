@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type OAuthV2 struct {
+	PolicyCommon
+
+	Operation                   string   `xml:"Operation,omitempty"`
+	AccessToken                 *AnyNode `xml:"AccessToken,omitempty"`
+	AccessTokenPrefix           *AnyNode `xml:"AccessTokenPrefix,omitempty"`
+	Algorithm                   *AnyNode `xml:"Algorithm,omitempty"`
+	AppEndUser                  *AnyNode `xml:"AppEndUser,omitempty"`
+	Attributes                  *AnyNode `xml:"Attributes,omitempty"`
+	CacheExpiryInSeconds        *AnyNode `xml:"CacheExpiryInSeconds,omitempty"`
+	ClientId                    *AnyNode `xml:"ClientId,omitempty"`
+	Code                        *AnyNode `xml:"Code,omitempty"`
+	ExpiresIn                   *AnyNode `xml:"ExpiresIn,omitempty"`
+	ExternalAccessToken         *AnyNode `xml:"ExternalAccessToken,omitempty"`
+	ExternalAuthorization       *AnyNode `xml:"ExternalAuthorization,omitempty"`
+	ExternalAuthorizationCode   *AnyNode `xml:"ExternalAuthorizationCode,omitempty"`
+	ExternalRefreshToken        *AnyNode `xml:"ExternalRefreshToken,omitempty"`
+	GenerateErrorResponse       *AnyNode `xml:"GenerateErrorResponse,omitempty"`
+	GenerateResponse            *AnyNode `xml:"GenerateResponse,omitempty"`
+	GrantType                   *AnyNode `xml:"GrantType,omitempty"`
+	Issuer                      *AnyNode `xml:"Issuer,omitempty"`
+	PassWord                    *AnyNode `xml:"PassWord,omitempty"`
+	PrivateKey                  *AnyNode `xml:"PrivateKey,omitempty"`
+	PublicKey                   *AnyNode `xml:"PublicKey,omitempty"`
+	RedirectUri                 *AnyNode `xml:"RedirectUri,omitempty"`
+	RefreshToken                *AnyNode `xml:"RefreshToken,omitempty"`
+	RefreshTokenExpiresIn       *AnyNode `xml:"RefreshTokenExpiresIn,omitempty"`
+	RefreshTokenPrefix          *AnyNode `xml:"RefreshTokenPrefix,omitempty"`
+	ResponseType                *AnyNode `xml:"ResponseType,omitempty"`
+	ReuseRefreshToken           *AnyNode `xml:"ReuseRefreshToken,omitempty"`
+	RFCCompliantRequestResponse *AnyNode `xml:"RFCCompliantRequestResponse,omitempty"`
+	Scope                       *AnyNode `xml:"Scope,omitempty"`
+	SecretKey                   *AnyNode `xml:"SecretKey,omitempty"`
+	State                       *AnyNode `xml:"State,omitempty"`
+	StoreToken                  *AnyNode `xml:"StoreToken,omitempty"`
+	SupportedGrantTypes         *AnyNode `xml:"SupportedGrantTypes,omitempty"`
+	Tokens                      *AnyNode `xml:"Tokens,omitempty"`
+	UserName                    *AnyNode `xml:"UserName,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateOAuthV2(v *OAuthV2, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.OAuthV2(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.Operation == "" {
+		subErrors = append(subErrors, NewMissingNodeError(subPath, "Operation"))
+	}
+
+	return subErrors
+}
@@ -14,7 +14,24 @@
 
 package v1
 
+import "fmt"
+
 type PolicyList []*Policy
 type Policies struct {
 	List PolicyList `xml:",any"`
 }
+
+func ValidatePolicies(v *Policies, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Policies", path)
+
+	var subErrors []error
+	for index, vv := range v.List {
+		subErrors = append(subErrors, ValidatePolicy(vv, fmt.Sprintf("%s.%v", subPath, index))...)
+	}
+
+	return subErrors
+}
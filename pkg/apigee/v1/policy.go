@@ -15,8 +15,10 @@
 package v1
 
 import (
+	"encoding/xml"
 	"fmt"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
 	"path/filepath"
 )
 
@@ -35,6 +37,16 @@ func (p *Policy) Name() string {
 	return ""
 }
 
+// Attr returns the value of the given attribute (e.g. "continueOnError"), or empty if not set
+func (p *Policy) Attr(name string) string {
+	for _, attr := range p.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
 func (p *Policy) FileContents() ([]byte, error) {
 	return p.XML()
 }
@@ -50,3 +62,52 @@ func (p *Policy) FilePath() string {
 func (p *Policy) XML() ([]byte, error) {
 	return utils.Struct2XMLDocText(p)
 }
+
+// Unmarshal decodes the policy into one of the typed policy structs (e.g. *SpikeArrest)
+func (p *Policy) Unmarshal(v any) error {
+	xmlText, err := xml.Marshal(p)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	if err = xml.Unmarshal(xmlText, v); err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// Typed returns the typed struct for policy types in the catalog.
+// Unknown policy types are returned as is.
+func (p *Policy) Typed() (any, error) {
+	entry, ok := policyCatalog[p.Type()]
+	if !ok {
+		return p, nil
+	}
+
+	typed := entry.newPolicy()
+	if err := p.Unmarshal(typed); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+func ValidatePolicy(v *Policy, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	entry, ok := policyCatalog[v.Type()]
+	if !ok {
+		//unknown policy types are treated as opaque
+		return nil
+	}
+
+	typed := entry.newPolicy()
+	if err := v.Unmarshal(typed); err != nil {
+		return []error{err}
+	}
+
+	return entry.validate(typed, path)
+}
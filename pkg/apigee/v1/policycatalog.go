@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"slices"
+)
+
+type policyCatalogEntry struct {
+	newPolicy func() any
+	validate  func(v any, path string) []error
+}
+
+func newPolicyCatalogEntry[T any](validate func(v *T, path string) []error) policyCatalogEntry {
+	return policyCatalogEntry{
+		newPolicy: func() any {
+			return new(T)
+		},
+		validate: func(v any, path string) []error {
+			return validate(v.(*T), path)
+		},
+	}
+}
+
+var policyCatalog = map[string]policyCatalogEntry{
+	"AssignMessage":    newPolicyCatalogEntry(ValidateAssignMessage),
+	"CORS":             newPolicyCatalogEntry(ValidateCORS),
+	"ExtractVariables": newPolicyCatalogEntry(ValidateExtractVariables),
+	"FlowCallout":      newPolicyCatalogEntry(ValidateFlowCallout),
+	"Javascript":       newPolicyCatalogEntry(ValidateJavascript),
+	"MessageLogging":   newPolicyCatalogEntry(ValidateMessageLogging),
+	"OASValidation":    newPolicyCatalogEntry(ValidateOASValidation),
+	"OAuthV2":          newPolicyCatalogEntry(ValidateOAuthV2),
+	"Quota":            newPolicyCatalogEntry(ValidateQuota),
+	"RaiseFault":       newPolicyCatalogEntry(ValidateRaiseFault),
+	"ServiceCallout":   newPolicyCatalogEntry(ValidateServiceCallout),
+	"SpikeArrest":      newPolicyCatalogEntry(ValidateSpikeArrest),
+	"VerifyAPIKey":     newPolicyCatalogEntry(ValidateVerifyAPIKey),
+}
+
+// PolicyTypes returns the sorted list of policy types that have typed validation
+func PolicyTypes() []string {
+	var types []string
+	for policyType := range policyCatalog {
+		types = append(types, policyType)
+	}
+	slices.Sort(types)
+	return types
+}
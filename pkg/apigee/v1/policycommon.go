@@ -0,0 +1,144 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+// PolicyCommon holds the attributes and elements shared by all policy types
+type PolicyCommon struct {
+	Name            string `xml:"name,attr"`
+	Enabled         string `xml:"enabled,attr,omitempty"`
+	ContinueOnError string `xml:"continueOnError,attr,omitempty"`
+	Async           string `xml:"async,attr,omitempty"`
+
+	DisplayName string      `xml:"DisplayName,omitempty"`
+	Description string      `xml:"Description,omitempty"`
+	Properties  *Properties `xml:"Properties,omitempty"`
+
+	//-- deprecated fields
+	FaultRules *Deprecated `xml:"FaultRules"`
+}
+
+func ValidatePolicyCommon(v *PolicyCommon, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	var subErrors []error
+	if v.Name == "" {
+		subErrors = append(subErrors, NewMissingNodeError(path, ".name"))
+	}
+	subErrors = append(subErrors, ValidateProperties(v.Properties, path)...)
+
+	return subErrors
+}
+
+// MessageParam is a name/value element such as a <Header>, <QueryParam> or <FormParam>
+type MessageParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+type MessageParamList []*MessageParam
+
+func ValidateMessageParams(v MessageParamList, path string, name string) []error {
+	for i, vv := range v {
+		subPath := fmt.Sprintf("%s.%s.%v", path, name, i)
+		if len(vv.UnknownNode) > 0 {
+			return []error{NewUnknownNodeError(subPath, vv.UnknownNode[0])}
+		}
+	}
+	return nil
+}
+
+type MessageHeaders struct {
+	List MessageParamList `xml:"Header,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageHeaders(v *MessageHeaders, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Headers", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return ValidateMessageParams(v.List, subPath, "Header")
+}
+
+type MessageQueryParams struct {
+	List MessageParamList `xml:"QueryParam,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageQueryParams(v *MessageQueryParams, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.QueryParams", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return ValidateMessageParams(v.List, subPath, "QueryParam")
+}
+
+type MessageFormParams struct {
+	List MessageParamList `xml:"FormParam,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateMessageFormParams(v *MessageFormParams, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.FormParams", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return ValidateMessageParams(v.List, subPath, "FormParam")
+}
+
+// PolicyRef is an element that holds either a literal value or a ref="flow.variable" attribute
+type PolicyRef struct {
+	Ref   string `xml:"ref,attr,omitempty"`
+	Value string `xml:",chardata"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidatePolicyRef(v *PolicyRef, path string, name string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.%s", path, name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// PositionError is a validation error along with the position of the offending node in the YAML file
+type PositionError struct {
+	Position utils.YAMLPosition
+	Err      error
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Position, e.Err.Error())
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// errorLocation returns the model path (e.g. "Root.ProxyEndpoints.0.ProxyEndpoint(name: default)") of the error
+func errorLocation(err error) (string, bool) {
+	var unknownNodeError *UnknownNodeError
+	var missingNodeError *MissingNodeError
+	var danglingReferenceError *DanglingReferenceError
+	var unusedWarning *UnusedWarning
+	var invalidConditionError *InvalidConditionError
+
+	switch {
+	case errors.As(err, &unknownNodeError):
+		return fmt.Sprintf("%s.%s", unknownNodeError.Location, unknownNodeError.Node.XMLName.Local), true
+	case errors.As(err, &missingNodeError):
+		return missingNodeError.Location, true
+	case errors.As(err, &danglingReferenceError):
+		return danglingReferenceError.Location, true
+	case errors.As(err, &unusedWarning):
+		return unusedWarning.Location, true
+	case errors.As(err, &invalidConditionError):
+		return invalidConditionError.Location, true
+	}
+	return "", false
+}
+
+// WithPositions annotates the errors with the position of the YAML node they refer to
+func WithPositions(errs []error, doc *yaml.Node, positions *utils.YAMLPositions) []error {
+	if positions == nil {
+		return errs
+	}
+
+	var result []error
+	for _, err := range errs {
+		location, ok := errorLocation(err)
+		if !ok {
+			result = append(result, err)
+			continue
+		}
+
+		position, ok := LocatePosition(doc, positions, location)
+		if !ok {
+			result = append(result, err)
+			continue
+		}
+		result = append(result, &PositionError{Position: position, Err: err})
+	}
+	return result
+}
+
+// LocatePosition finds the position of the YAML node for the given model path.
+//
+// Model paths do not map one-to-one to YAML keys (e.g. "Steps" has no key of its own), so
+// segments that cannot be found are skipped, and the position of the deepest node found is returned.
+func LocatePosition(doc *yaml.Node, positions *utils.YAMLPositions, location string) (utils.YAMLPosition, bool) {
+	segments := splitLocation(location)
+	if len(segments) > 0 && segments[0] == "Root" {
+		segments = segments[1:]
+	}
+
+	node := doc
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node == nil {
+		return utils.YAMLPosition{}, false
+	}
+
+	best, found := positions.Position(node)
+	for _, segment := range segments {
+		key, _, _ := strings.Cut(segment, "(")
+		var next *yaml.Node
+		var nextPosition *yaml.Node
+
+		if index, err := strconv.Atoi(key); err == nil {
+			if node.Kind == yaml.SequenceNode && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				nextPosition = next
+			}
+		} else if node.Kind == yaml.MappingNode {
+			nextPosition, next = lookupYAMLKey(node, key)
+		}
+
+		if next == nil {
+			continue
+		}
+
+		position, ok := positions.Position(nextPosition)
+		if !ok {
+			//the node came from another file through a $ref
+			break
+		}
+		node = next
+		best, found = position, true
+	}
+
+	return best, found
+}
+
+// lookupYAMLKey finds the key and value for the given key, including within "-" prefixed keys
+// (which do not produce an element of their own)
+func lookupYAMLKey(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !strings.HasPrefix(node.Content[i].Value, "-") {
+			continue
+		}
+		value := node.Content[i+1]
+		switch value.Kind {
+		case yaml.MappingNode:
+			if k, v := lookupYAMLKey(value, key); k != nil {
+				return k, v
+			}
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yaml.MappingNode {
+					continue
+				}
+				if k, v := lookupYAMLKey(item, key); k != nil {
+					return k, v
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// splitLocation splits the model path on "." except within parenthesis, as names may contain dots
+func splitLocation(location string) []string {
+	var segments []string
+	depth := 0
+	start := 0
+	for i, c := range location {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, location[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, location[start:])
+}
+
+// ModelPosition finds the position of the YAML node for the given model path within the model's YAML file
+func ModelPosition(model Model, location string) (utils.YAMLPosition, bool) {
+	var doc *yaml.Node
+	switch m := model.(type) {
+	case *APIProxyModel:
+		doc = m.YAMLDoc
+	case *SharedFlowBundleModel:
+		doc = m.YAMLDoc
+	default:
+		return utils.YAMLPosition{}, false
+	}
+	return LocatePosition(doc, ModelPositions(model), location)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type Quota struct {
+	PolicyCommon
+
+	Type                       string      `xml:"type,attr,omitempty"`
+	Allow                      *QuotaAllow `xml:"Allow,omitempty"`
+	Interval                   *PolicyRef  `xml:"Interval,omitempty"`
+	TimeUnit                   *PolicyRef  `xml:"TimeUnit,omitempty"`
+	StartTime                  string      `xml:"StartTime,omitempty"`
+	Distributed                string      `xml:"Distributed,omitempty"`
+	Synchronous                string      `xml:"Synchronous,omitempty"`
+	AsynchronousConfiguration  *AnyNode    `xml:"AsynchronousConfiguration,omitempty"`
+	Identifier                 *PolicyRef  `xml:"Identifier,omitempty"`
+	MessageWeight              *PolicyRef  `xml:"MessageWeight,omitempty"`
+	UseQuotaConfigInAPIProduct *AnyNode    `xml:"UseQuotaConfigInAPIProduct,omitempty"`
+	SharedName                 string      `xml:"SharedName,omitempty"`
+	CountOnly                  string      `xml:"CountOnly,omitempty"`
+	EnforceOnly                string      `xml:"EnforceOnly,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateQuota(v *Quota, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Quota(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.UseQuotaConfigInAPIProduct == nil {
+		//without an API product, the quota settings must be set in the policy itself
+		if v.Allow == nil {
+			subErrors = append(subErrors, NewMissingNodeError(subPath, "Allow"))
+		}
+		if v.Interval == nil {
+			subErrors = append(subErrors, NewMissingNodeError(subPath, "Interval"))
+		}
+		if v.TimeUnit == nil {
+			subErrors = append(subErrors, NewMissingNodeError(subPath, "TimeUnit"))
+		}
+	}
+	subErrors = append(subErrors, ValidateQuotaAllow(v.Allow, subPath)...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.Interval, subPath, "Interval")...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.TimeUnit, subPath, "TimeUnit")...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.Identifier, subPath, "Identifier")...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.MessageWeight, subPath, "MessageWeight")...)
+
+	return subErrors
+}
+
+type QuotaAllow struct {
+	Count    string   `xml:"count,attr,omitempty"`
+	CountRef string   `xml:"countRef,attr,omitempty"`
+	Class    *AnyNode `xml:"Class,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateQuotaAllow(v *QuotaAllow, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Allow", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	return nil
+}
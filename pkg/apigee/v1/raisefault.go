@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type RaiseFault struct {
+	PolicyCommon
+
+	FaultResponse             *FaultResponse `xml:"FaultResponse,omitempty"`
+	IgnoreUnresolvedVariables string         `xml:"IgnoreUnresolvedVariables,omitempty"`
+	ShortFaultReason          string         `xml:"ShortFaultReason,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+type FaultResponse struct {
+	AssignVariables AssignVariableList `xml:"AssignVariable,omitempty"`
+	Add             []*MessageAdd      `xml:"Add,omitempty"`
+	Copy            []*MessageCopy     `xml:"Copy,omitempty"`
+	Remove          []*MessageRemove   `xml:"Remove,omitempty"`
+	Set             []*MessageSet      `xml:"Set,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateRaiseFault(v *RaiseFault, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.RaiseFault(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	subErrors = append(subErrors, ValidateFaultResponse(v.FaultResponse, subPath)...)
+
+	return subErrors
+}
+
+func ValidateFaultResponse(v *FaultResponse, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.FaultResponse", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateAssignVariables(v.AssignVariables, subPath)...)
+	for _, vv := range v.Add {
+		subErrors = append(subErrors, ValidateMessageAdd(vv, subPath)...)
+	}
+	for _, vv := range v.Copy {
+		subErrors = append(subErrors, ValidateMessageCopy(vv, subPath)...)
+	}
+	for _, vv := range v.Remove {
+		subErrors = append(subErrors, ValidateMessageRemove(vv, subPath)...)
+	}
+	for _, vv := range v.Set {
+		subErrors = append(subErrors, ValidateMessageSet(vv, subPath)...)
+	}
+
+	return subErrors
+}
@@ -0,0 +1,324 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type DanglingReferenceError struct {
+	Location string
+	Kind     string
+	Name     string
+}
+
+func (e *DanglingReferenceError) Error() string {
+	return fmt.Sprintf(`%s "%s" referenced at "%s" does not exist`, e.Kind, e.Name, e.Location)
+}
+
+func NewDanglingReferenceError(location string, kind string, name string) *DanglingReferenceError {
+	return &DanglingReferenceError{
+		Location: location,
+		Kind:     kind,
+		Name:     name,
+	}
+}
+
+type UnusedWarning struct {
+	Location string
+	Kind     string
+	Name     string
+}
+
+func (e *UnusedWarning) Error() string {
+	return fmt.Sprintf(`%s "%s" at "%s" is not referenced`, e.Kind, e.Name, e.Location)
+}
+
+func NewUnusedWarning(location string, kind string, name string) *UnusedWarning {
+	return &UnusedWarning{
+		Location: location,
+		Kind:     kind,
+		Name:     name,
+	}
+}
+
+// StepLocation is a step along with its path within the model
+type StepLocation struct {
+	Path string
+	Step *Step
+}
+
+func stepLocations(steps StepList, path string) []StepLocation {
+	var result []StepLocation
+	for i, step := range steps {
+		if step == nil {
+			continue
+		}
+		result = append(result, StepLocation{
+			Path: fmt.Sprintf("%s.Steps.%v.Step", path, i),
+			Step: step,
+		})
+	}
+	return result
+}
+
+func requestResponseStepLocations(request *Request, response *Response, path string) []StepLocation {
+	var result []StepLocation
+	if request != nil {
+		result = append(result, stepLocations(request.Steps, path+".Request")...)
+	}
+	if response != nil {
+		result = append(result, stepLocations(response.Steps, path+".Response")...)
+	}
+	return result
+}
+
+func faultRuleStepLocations(faultRules *FaultRules, defaultFaultRule *DefaultFaultRule, path string) []StepLocation {
+	var result []StepLocation
+	if faultRules != nil {
+		for i, faultRule := range faultRules.List {
+			if faultRule == nil {
+				continue
+			}
+			result = append(result, stepLocations(faultRule.Steps, fmt.Sprintf("%s.FaultRules.%v.FaultRule", path, i))...)
+		}
+	}
+	if defaultFaultRule != nil {
+		result = append(result, stepLocations(defaultFaultRule.Steps, path+".DefaultFaultRule")...)
+	}
+	return result
+}
+
+func flowsStepLocations(flows *Flows, path string) []StepLocation {
+	var result []StepLocation
+	if flows == nil {
+		return nil
+	}
+	for i, flow := range flows.List {
+		if flow == nil {
+			continue
+		}
+		result = append(result, requestResponseStepLocations(flow.Request, flow.Response, fmt.Sprintf("%s.Flows.%v.Flow", path, i))...)
+	}
+	return result
+}
+
+// ProxyEndpointStepLocations returns all the steps within the proxy endpoint, including fault rules
+func ProxyEndpointStepLocations(v *ProxyEndpoint, path string) []StepLocation {
+	subPath := fmt.Sprintf("%s.ProxyEndpoint(name: %s)", path, v.Name)
+
+	var result []StepLocation
+	result = append(result, faultRuleStepLocations(v.FaultRules, v.DefaultFaultRule, subPath)...)
+	if v.PreFlow != nil {
+		result = append(result, requestResponseStepLocations(v.PreFlow.Request, v.PreFlow.Response, subPath+".PreFlow")...)
+	}
+	if v.EventFlow != nil {
+		result = append(result, requestResponseStepLocations(nil, v.EventFlow.Response, subPath+".EventFlow")...)
+	}
+	result = append(result, flowsStepLocations(v.Flows, subPath)...)
+	if v.PostFlow != nil {
+		result = append(result, requestResponseStepLocations(v.PostFlow.Request, v.PostFlow.Response, subPath+".PostFlow")...)
+	}
+	if v.PostClientFlow != nil {
+		result = append(result, requestResponseStepLocations(nil, v.PostClientFlow.Response, subPath+".PostClientFlow")...)
+	}
+	return result
+}
+
+// TargetEndpointStepLocations returns all the steps within the target endpoint, including fault rules
+func TargetEndpointStepLocations(v *TargetEndpoint, path string) []StepLocation {
+	subPath := fmt.Sprintf("%s.TargetEndpoint(name: %s)", path, v.Name)
+
+	var result []StepLocation
+	result = append(result, faultRuleStepLocations(v.FaultRules, v.DefaultFaultRule, subPath)...)
+	result = append(result, requestResponseStepLocations(v.PreFlow.Request, v.PreFlow.Response, subPath+".PreFlow")...)
+	if v.EventFlow != nil {
+		result = append(result, requestResponseStepLocations(nil, v.EventFlow.Response, subPath+".EventFlow")...)
+	}
+	result = append(result, flowsStepLocations(&v.Flows, subPath)...)
+	result = append(result, requestResponseStepLocations(v.PostFlow.Request, v.PostFlow.Response, subPath+".PostFlow")...)
+	return result
+}
+
+// SharedFlowStepLocations returns all the steps within the shared flow
+func SharedFlowStepLocations(v *SharedFlow, path string) []StepLocation {
+	return stepLocations(v.Steps, fmt.Sprintf("%s.SharedFlow", path))
+}
+
+// StepLocations returns all the steps within the API proxy or shared flow model
+func StepLocations(model Model) []StepLocation {
+	var result []StepLocation
+	switch m := model.(type) {
+	case *APIProxyModel:
+		for i, proxyEndpoint := range m.ProxyEndpoints.List {
+			result = append(result, ProxyEndpointStepLocations(proxyEndpoint, fmt.Sprintf("Root.ProxyEndpoints.%v", i))...)
+		}
+		for i, targetEndpoint := range m.TargetEndpoints.List {
+			result = append(result, TargetEndpointStepLocations(targetEndpoint, fmt.Sprintf("Root.TargetEndpoints.%v", i))...)
+		}
+	case *SharedFlowBundleModel:
+		for i, sharedFlow := range m.SharedFlows.List {
+			result = append(result, SharedFlowStepLocations(sharedFlow, fmt.Sprintf("Root.SharedFlows.%v", i))...)
+		}
+	}
+	return result
+}
+
+var resourceURLTypes = []string{"graphql", "hosted", "java", "jsc", "oas", "properties", "py", "wsdl", "xsd", "xsl"}
+var resourceURLRegex = regexp.MustCompile(`^([a-z]+)://([^/\s]+)$`)
+
+// ParseResourceURL splits a resource URL such as "jsc://foo.js" into its type and file name
+func ParseResourceURL(resourceURL string) (string, string, bool) {
+	matches := resourceURLRegex.FindStringSubmatch(strings.TrimSpace(resourceURL))
+	if len(matches) != 3 || !slices.Contains(resourceURLTypes, matches[1]) {
+		return "", "", false
+	}
+	return matches[1], matches[2], true
+}
+
+// PolicyResourceURLs returns all the resource URLs (e.g. "jsc://foo.js") used within the policy
+func PolicyResourceURLs(v *Policy) []string {
+	var result []string
+	var walk func(node *AnyNode)
+	walk = func(node *AnyNode) {
+		if _, _, ok := ParseResourceURL(string(node.CharData)); ok {
+			result = append(result, strings.TrimSpace(string(node.CharData)))
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	walk((*AnyNode)(v))
+	return result
+}
+
+var sharedFlowNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+func ValidateReferences(model Model) (errs []error, warnings []error) {
+	var policies *Policies
+	switch m := model.(type) {
+	case *APIProxyModel:
+		policies = &m.Policies
+		errs = append(errs, validateEndpointReferences(m)...)
+	case *SharedFlowBundleModel:
+		policies = &m.Policies
+	default:
+		return nil, nil
+	}
+
+	//steps must refer to an existing policy
+	policyNames := map[string]bool{}
+	for _, policy := range policies.List {
+		policyNames[policy.Name()] = false
+	}
+
+	for _, location := range StepLocations(model) {
+		if _, ok := policyNames[location.Step.Name]; !ok {
+			errs = append(errs, NewDanglingReferenceError(location.Path, "policy", location.Step.Name))
+			continue
+		}
+		policyNames[location.Step.Name] = true
+	}
+
+	//resource URLs must refer to an existing resource
+	resources := model.GetResources()
+	resourceURLs := map[string]bool{}
+	for _, resource := range resources.List {
+		resourceURLs[fmt.Sprintf("%s://%s", resource.Type, resource.FileName())] = false
+	}
+
+	for i, policy := range policies.List {
+		policyPath := fmt.Sprintf("Root.Policies.%v.%s(name: %s)", i, policy.Type(), policy.Name())
+		for _, resourceURL := range PolicyResourceURLs(policy) {
+			if _, ok := resourceURLs[resourceURL]; !ok {
+				errs = append(errs, NewDanglingReferenceError(policyPath, "resource", resourceURL))
+				continue
+			}
+			resourceURLs[resourceURL] = true
+		}
+
+		if policy.Type() != "FlowCallout" {
+			continue
+		}
+
+		flowCallout := &FlowCallout{}
+		if err := policy.Unmarshal(flowCallout); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if flowCallout.SharedFlowBundle != "" && !sharedFlowNameRegex.MatchString(flowCallout.SharedFlowBundle) {
+			errs = append(errs, errors.Errorf(`shared flow bundle name "%s" at "%s" is not valid`, flowCallout.SharedFlowBundle, policyPath))
+		}
+		if m, ok := model.(*SharedFlowBundleModel); ok && flowCallout.SharedFlowBundle == m.Name() {
+			errs = append(errs, errors.Errorf(`shared flow bundle "%s" at "%s" calls itself`, flowCallout.SharedFlowBundle, policyPath))
+		}
+	}
+
+	//unused policies and resources are only warnings
+	for i, policy := range policies.List {
+		if !policyNames[policy.Name()] {
+			policyPath := fmt.Sprintf("Root.Policies.%v.%s(name: %s)", i, policy.Type(), policy.Name())
+			warnings = append(warnings, NewUnusedWarning(policyPath, "policy", policy.Name()))
+		}
+	}
+
+	for i, resource := range resources.List {
+		//properties files are referenced through flow variables, not URLs
+		if resource.Type == "properties" {
+			continue
+		}
+		resourceURL := fmt.Sprintf("%s://%s", resource.Type, resource.FileName())
+		if !resourceURLs[resourceURL] {
+			warnings = append(warnings, NewUnusedWarning(fmt.Sprintf("Root.Resources.%v.Resource", i), "resource", resourceURL))
+		}
+	}
+
+	return errs, warnings
+}
+
+func validateEndpointReferences(m *APIProxyModel) []error {
+	var errs []error
+
+	targetEndpointNames := map[string]bool{}
+	for _, targetEndpoint := range m.TargetEndpoints.List {
+		targetEndpointNames[targetEndpoint.Name] = true
+	}
+
+	integrationEndpointNames := map[string]bool{}
+	for _, integrationEndpoint := range m.IntegrationEndpoints.List {
+		integrationEndpointNames[integrationEndpoint.Name] = true
+	}
+
+	for i, proxyEndpoint := range m.ProxyEndpoints.List {
+		if proxyEndpoint.RouteRules == nil {
+			continue
+		}
+		for j, routeRule := range *proxyEndpoint.RouteRules {
+			routeRulePath := fmt.Sprintf("Root.ProxyEndpoints.%v.ProxyEndpoint(name: %s).RouteRules.%v.RouteRule(name: %s)", i, proxyEndpoint.Name, j, routeRule.Name)
+			if routeRule.TargetEndpoint != "" && !targetEndpointNames[routeRule.TargetEndpoint] {
+				errs = append(errs, NewDanglingReferenceError(routeRulePath, "target endpoint", routeRule.TargetEndpoint))
+			}
+			if routeRule.IntegrationEndpoint != "" && !integrationEndpointNames[routeRule.IntegrationEndpoint] {
+				errs = append(errs, NewDanglingReferenceError(routeRulePath, "integration endpoint", routeRule.IntegrationEndpoint))
+			}
+		}
+	}
+
+	return errs
+}
@@ -35,5 +35,5 @@ func ValidateRequest(v *Request, path string) []error {
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSteps(&v.Steps, subPath)...)
 
-	return nil
+	return subErrors
 }
@@ -35,5 +35,5 @@ func ValidateResponse(v *Response, path string) []error {
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSteps(&v.Steps, subPath)...)
 
-	return nil
+	return subErrors
 }
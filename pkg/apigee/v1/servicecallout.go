@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type ServiceCallout struct {
+	PolicyCommon
+
+	Request               *ServiceCalloutRequest `xml:"Request,omitempty"`
+	Response              string                 `xml:"Response,omitempty"`
+	Timeout               string                 `xml:"Timeout,omitempty"`
+	HTTPTargetConnection  *HTTPTargetConnection  `xml:"HTTPTargetConnection,omitempty"`
+	LocalTargetConnection *LocalTargetConnection `xml:"LocalTargetConnection,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateServiceCallout(v *ServiceCallout, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.ServiceCallout(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.HTTPTargetConnection == nil && v.LocalTargetConnection == nil {
+		subErrors = append(subErrors, NewMissingNodeError(subPath, "HTTPTargetConnection"))
+	}
+	subErrors = append(subErrors, ValidateServiceCalloutRequest(v.Request, subPath)...)
+	subErrors = append(subErrors, ValidateHTTPTargetConnection(v.HTTPTargetConnection, subPath)...)
+	subErrors = append(subErrors, ValidateLocalTargetConnection(v.LocalTargetConnection, subPath)...)
+
+	return subErrors
+}
+
+type ServiceCalloutRequest struct {
+	ClearPayload              string             `xml:"clearPayload,attr,omitempty"`
+	Variable                  string             `xml:"variable,attr,omitempty"`
+	IgnoreUnresolvedVariables string             `xml:"IgnoreUnresolvedVariables,omitempty"`
+	Add                       *MessageAdd        `xml:"Add,omitempty"`
+	AssignVariables           AssignVariableList `xml:"AssignVariable,omitempty"`
+	Copy                      *MessageCopy       `xml:"Copy,omitempty"`
+	Remove                    *MessageRemove     `xml:"Remove,omitempty"`
+	Set                       *MessageSet        `xml:"Set,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateServiceCalloutRequest(v *ServiceCalloutRequest, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.Request", path)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidateMessageAdd(v.Add, subPath)...)
+	subErrors = append(subErrors, ValidateAssignVariables(v.AssignVariables, subPath)...)
+	subErrors = append(subErrors, ValidateMessageCopy(v.Copy, subPath)...)
+	subErrors = append(subErrors, ValidateMessageRemove(v.Remove, subPath)...)
+	subErrors = append(subErrors, ValidateMessageSet(v.Set, subPath)...)
+
+	return subErrors
+}
@@ -57,5 +57,5 @@ func ValidateSharedFlow(v *SharedFlow, path string) []error {
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSteps(&v.Steps, subPath)...)
 
-	return nil
+	return subErrors
 }
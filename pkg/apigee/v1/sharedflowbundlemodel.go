@@ -19,12 +19,17 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
-	"path/filepath"
+	"io/fs"
 )
 
 func NewSharedFlowBundleModel(input string) (*SharedFlowBundleModel, error) {
+	return NewSharedFlowBundleModelFS(utils.OSFS, input)
+}
+
+// NewSharedFlowBundleModelFS is like NewSharedFlowBundleModel, but the YAML file (and the files it references) are read from fsys
+func NewSharedFlowBundleModelFS(fsys fs.FS, input string) (*SharedFlowBundleModel, error) {
 	sharedFlowModel := &SharedFlowBundleModel{}
-	err := sharedFlowModel.Hydrate(input)
+	err := sharedFlowModel.HydrateFS(fsys, input)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +44,8 @@ type SharedFlowBundleModel struct {
 
 	UnknownNode AnyList `xml:",any"`
 
-	YAMLDoc *yaml.Node `xml:"-"`
+	YAMLDoc       *yaml.Node           `xml:"-"`
+	YAMLPositions *utils.YAMLPositions `xml:"-"`
 }
 
 func (a *SharedFlowBundleModel) Name() string {
@@ -59,9 +65,13 @@ func (a *SharedFlowBundleModel) GetResources() *Resources {
 }
 
 func (a *SharedFlowBundleModel) Hydrate(filePath string) error {
+	return a.HydrateFS(utils.OSFS, filePath)
+}
+
+func (a *SharedFlowBundleModel) HydrateFS(fsys fs.FS, filePath string) error {
 	var err error
 
-	a.YAMLDoc, err = utils.YAMLFile2YAML(filePath)
+	a.YAMLDoc, a.YAMLPositions, err = utils.YAMLFile2YAMLWithPositionsFS(fsys, filePath)
 
 	if err != nil {
 		return err
@@ -79,7 +89,7 @@ func (a *SharedFlowBundleModel) Hydrate(filePath string) error {
 		return errors.New(err)
 	}
 
-	err = HydrateResources(a, filepath.Dir(filePath))
+	err = HydrateResourcesFS(a, fsys, utils.FSDir(fsys, filePath))
 	if err != nil {
 		return errors.New(err)
 	}
@@ -87,6 +97,19 @@ func (a *SharedFlowBundleModel) Hydrate(filePath string) error {
 	return nil
 }
 
+func (a *SharedFlowBundleModel) Warnings() []error {
+	if a == nil {
+		return nil
+	}
+
+	_, warnings := ValidateReferences(a)
+	return WithPositions(warnings, a.YAMLDoc, a.YAMLPositions)
+}
+
+func (a *SharedFlowBundleModel) Positions() *utils.YAMLPositions {
+	return a.YAMLPositions
+}
+
 func (a *SharedFlowBundleModel) BundleRoot() string {
 	return "sharedflowbundle"
 }
@@ -127,17 +150,22 @@ func (a *SharedFlowBundleModel) Validate() error {
 	err := utils.MultiError{Errors: []error{}}
 	path := "Root"
 	if len(a.UnknownNode) > 0 {
-		err.Errors = append(err.Errors, NewUnknownNodeError(path, a.UnknownNode[0]))
+		err.Errors = append(err.Errors, WithPositions([]error{NewUnknownNodeError(path, a.UnknownNode[0])}, a.YAMLDoc, a.YAMLPositions)...)
 		return err
 	}
 
 	var subErrors []error
 	subErrors = append(subErrors, ValidateSharedFlowBundle(&a.SharedFlowBundle, path)...)
+	subErrors = append(subErrors, ValidatePolicies(&a.Policies, path)...)
 	subErrors = append(subErrors, ValidateSharedFlows(&a.SharedFlows, path)...)
 	subErrors = append(subErrors, ValidateResources(&a.Resources, path)...)
 
+	referenceErrors, _ := ValidateReferences(a)
+	subErrors = append(subErrors, referenceErrors...)
+	subErrors = append(subErrors, ValidateConditions(a)...)
+
 	if len(subErrors) > 0 {
-		err.Errors = append(err.Errors, subErrors...)
+		err.Errors = append(err.Errors, WithPositions(subErrors, a.YAMLDoc, a.YAMLPositions)...)
 		return err
 	}
 
@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+type SpikeArrest struct {
+	PolicyCommon
+
+	Identifier        *PolicyRef `xml:"Identifier,omitempty"`
+	MessageWeight     *PolicyRef `xml:"MessageWeight,omitempty"`
+	Rate              *PolicyRef `xml:"Rate,omitempty"`
+	UseEffectiveCount string     `xml:"UseEffectiveCount,omitempty"`
+
+	UnknownNode AnyList `xml:",any"`
+}
+
+func ValidateSpikeArrest(v *SpikeArrest, path string) []error {
+	if v == nil {
+		return nil
+	}
+
+	subPath := fmt.Sprintf("%s.SpikeArrest(name: %s)", path, v.Name)
+	if len(v.UnknownNode) > 0 {
+		return []error{NewUnknownNodeError(subPath, v.UnknownNode[0])}
+	}
+
+	var subErrors []error
+	subErrors = append(subErrors, ValidatePolicyCommon(&v.PolicyCommon, subPath)...)
+	if v.Rate == nil {
+		subErrors = append(subErrors, NewMissingNodeError(subPath, "Rate"))
+	}
+	subErrors = append(subErrors, ValidatePolicyRef(v.Identifier, subPath, "Identifier")...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.MessageWeight, subPath, "MessageWeight")...)
+	subErrors = append(subErrors, ValidatePolicyRef(v.Rate, subPath, "Rate")...)
+
+	return subErrors
+}
@@ -72,6 +72,35 @@ func TestProxyBundle2YAMLFile(t *testing.T) {
 	}
 }
 
+func TestBundleZip2YAML(t *testing.T) {
+	tests := []struct {
+		dir string
+	}{
+		{
+			"helloworld",
+		},
+		{
+			"oauth-validate-key-secret",
+		},
+		{
+			"integration-target",
+		},
+	}
+
+	bundlesDir := filepath.Join("testdata", "bundles")
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			zipBytes := utils.MustReadFileBytes(filepath.Join(bundlesDir, tt.dir, "apiproxy.zip"))
+			expectedYAMLBytes := utils.MustReadFileBytes(filepath.Join(bundlesDir, tt.dir, "apiproxy.yaml"))
+
+			yamlBytes, _, err := BundleZip2YAML(zipBytes)
+			require.NoError(t, err)
+
+			assert.YAMLEq(t, string(expectedYAMLBytes), string(yamlBytes))
+		})
+	}
+}
+
 func TestAPIProxyModel2BundleZip(t *testing.T) {
 	tests := []struct {
 		dir string
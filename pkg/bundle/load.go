@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/apiproxy"
+	"github.com/apigee/apigee-go-gen/pkg/sharedflow"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing/fstest"
+)
+
+// LoadModel loads an API proxy or shared flow from a bundle zip, a bundle dir, or a YAML file
+func LoadModel(input string) (v1.Model, error) {
+	stat, err := os.Stat(input)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	if stat.IsDir() {
+		return loadBundleDir(input)
+	}
+
+	switch filepath.Ext(input) {
+	case ".zip":
+		return loadBundleZip(input)
+	case ".yaml", ".yml":
+		return loadYAMLFile(input)
+	}
+
+	return nil, errors.Errorf("input extension %s is not supported", filepath.Ext(input))
+}
+
+func loadYAMLFile(input string) (v1.Model, error) {
+	text, err := os.ReadFile(input)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	keys := map[string]any{}
+	if err = yaml.Unmarshal(text, &keys); err != nil {
+		return nil, errors.Errorf("%s: %s", input, err.Error())
+	}
+
+	if _, ok := keys["SharedFlowBundle"]; ok {
+		return v1.NewSharedFlowBundleModel(input)
+	}
+	return v1.NewAPIProxyModel(input)
+}
+
+func loadBundleZip(input string) (v1.Model, error) {
+	zipBytes, err := os.ReadFile(input)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	fsys, err := zip.BytesFS(zipBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadBundleFS(fsys, input)
+}
+
+func loadBundleDir(input string) (v1.Model, error) {
+	return loadBundleFS(os.DirFS(input), input)
+}
+
+// LoadModelFS loads an API proxy or shared flow from a bundle in memory (e.g. a zip opened with zip.BytesFS).
+// The "apiproxy" or "sharedflowbundle" directory must be at the root of fsys.
+func LoadModelFS(fsys fs.FS) (v1.Model, error) {
+	return loadBundleFS(fsys, ".")
+}
+
+// loadBundleFS converts the bundle to YAML in memory, and loads the model from it. The input is used in error messages.
+func loadBundleFS(fsys fs.FS, input string) (v1.Model, error) {
+	const yamlFile = "bundle.yaml"
+
+	var docBytes []byte
+	var resources map[string][]byte
+	var err error
+	var newModelFunc func(fs.FS, string) (v1.Model, error)
+	if isDirFS(fsys, "apiproxy") {
+		docBytes, resources, err = apiproxy.BundleFS2YAML(fsys)
+		newModelFunc = func(fsys fs.FS, input string) (v1.Model, error) {
+			return v1.NewAPIProxyModelFS(fsys, input)
+		}
+	} else if isDirFS(fsys, "sharedflowbundle") {
+		docBytes, resources, err = sharedflow.BundleFS2YAML(fsys)
+		newModelFunc = func(fsys fs.FS, input string) (v1.Model, error) {
+			return v1.NewSharedFlowBundleModelFS(fsys, input)
+		}
+	} else {
+		return nil, errors.Errorf("neither apiproxy nor sharedflowbundle dir found in %s", input)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	yamlFS := fstest.MapFS{yamlFile: {Data: docBytes}}
+	for fileName, contents := range resources {
+		yamlFS[fileName] = &fstest.MapFile{Data: contents}
+	}
+
+	model, err := newModelFunc(yamlFS, yamlFile)
+	if err != nil {
+		return nil, err
+	}
+
+	//the intermediate YAML file only exists in memory, so there are no meaningful positions to report
+	if positions := v1.ModelPositions(model); positions != nil {
+		*positions = utils.YAMLPositions{}
+	}
+
+	return model, nil
+}
+
+func isDirFS(fsys fs.FS, dir string) bool {
+	stat, err := fs.Stat(fsys, dir)
+	return err == nil && stat.IsDir()
+}
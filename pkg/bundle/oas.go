@@ -0,0 +1,587 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/condition"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-errors/errors"
+	libopenapijson "github.com/pb33f/libopenapi/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// oasBuilder creates an OpenAPI 3 skeleton from the flows and policies of an API proxy
+type oasBuilder struct {
+	model    *v1.APIProxyModel
+	doc      *openapi3.T
+	policies map[string]*v1.Policy
+	spec     *openapi3.T
+	warnings []error
+}
+
+// APIProxy2OAS3 reverse-engineers an OpenAPI 3 Description from the API proxy.
+//
+// Paths and operations come from the proxy.pathsuffix and request.verb conditions of the ProxyEndpoint flows.
+// Parameters, request bodies and security schemes come from the ExtractVariables, VerifyAPIKey and OAuthV2
+// policies used in those flows. If an OASValidation policy refers to an OpenAPI Description within the bundle,
+// its info and matching operations are used as-is.
+//
+// Flows that cannot be mapped to an operation are skipped, and reported back as warnings.
+func APIProxy2OAS3(model *v1.APIProxyModel) (*openapi3.T, []error, error) {
+	b := &oasBuilder{
+		model:    model,
+		policies: map[string]*v1.Policy{},
+	}
+	for _, policy := range model.Policies.List {
+		b.policies[policy.Name()] = policy
+	}
+
+	title := model.APIProxy.DisplayName
+	if title == "" {
+		title = model.APIProxy.Name
+	}
+
+	b.doc = &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       title,
+			Description: strings.TrimSpace(model.APIProxy.Description),
+			Version:     "1.0.0",
+		},
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{},
+	}
+
+	if err := b.loadBundledSpec(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := b.addProxyEndpoints(); err != nil {
+		return nil, nil, err
+	}
+
+	if componentsJSON, err := json.Marshal(b.doc.Components); err == nil && string(componentsJSON) == "{}" {
+		b.doc.Components = nil
+	}
+
+	return b.doc, b.warnings, nil
+}
+
+// Bundle2OASFile reverse-engineers an OpenAPI 3 Description from an API proxy bundle zip, bundle dir, or YAML file.
+// The output is written as JSON if the output file has a .json extension, otherwise as YAML.
+func Bundle2OASFile(input string, output string) error {
+	model, err := LoadModel(input)
+	if err != nil {
+		return err
+	}
+
+	proxyModel, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return errors.Errorf("input %s is not an API proxy", input)
+	}
+
+	doc, warnings, err := APIProxy2OAS3(proxyModel)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %s\n", warning.Error())
+	}
+
+	oasNode, err := utils.OAS3ToYAML(doc)
+	if err != nil {
+		return err
+	}
+
+	var outputText []byte
+	if filepath.Ext(output) == ".json" {
+		outputText, err = libopenapijson.YAMLNodeToJSON(oasNode, "  ")
+		if err != nil {
+			return errors.New(err)
+		}
+	} else {
+		outputText, err = utils.YAML2Text(utils.UnFlowYAMLNode(oasNode), 2)
+		if err != nil {
+			return err
+		}
+	}
+
+	return utils.WriteOutputText(output, outputText)
+}
+
+// loadBundledSpec loads the OpenAPI Description referenced by an OASValidation policy, if it is part of the bundle
+func (b *oasBuilder) loadBundledSpec() error {
+	for _, policy := range b.model.Policies.List {
+		if policy.Type() != "OASValidation" {
+			continue
+		}
+
+		oasValidation := &v1.OASValidation{}
+		if err := policy.Unmarshal(oasValidation); err != nil {
+			return err
+		}
+
+		resourceType, fileName, ok := v1.ParseResourceURL(oasValidation.OASResource)
+		if !ok || resourceType != "oas" {
+			continue
+		}
+
+		for _, resource := range b.model.Resources.List {
+			if resource.Type != "oas" || resource.FileName() != fileName {
+				continue
+			}
+
+			spec, err := openapi3.NewLoader().LoadFromData(resource.Content)
+			if err != nil {
+				b.warnings = append(b.warnings, errors.Errorf(`could not load "%s" used by OASValidation policy "%s". %s`, oasValidation.OASResource, policy.Name(), err.Error()))
+				continue
+			}
+
+			b.spec = spec
+			if spec.Info != nil {
+				b.doc.Info = spec.Info
+			}
+			//operations copied from the bundled OpenAPI Description may refer to its components
+			//the components are copied, so that adding security schemes does not change the bundled OpenAPI Description
+			if spec.Components != nil {
+				componentsJSON, err := json.Marshal(spec.Components)
+				if err != nil {
+					return errors.New(err)
+				}
+				components := &openapi3.Components{}
+				if err = json.Unmarshal(componentsJSON, components); err != nil {
+					return errors.New(err)
+				}
+				b.doc.Components = components
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func (b *oasBuilder) addProxyEndpoints() error {
+	//when all proxy endpoints share the same base path, it becomes the server URL
+	basePaths := map[string]bool{}
+	for _, proxyEndpoint := range b.model.ProxyEndpoints.List {
+		basePaths[proxyEndpointBasePath(proxyEndpoint)] = true
+	}
+
+	commonBasePath := "/"
+	if len(basePaths) == 1 {
+		for basePath := range basePaths {
+			commonBasePath = basePath
+		}
+	}
+	b.doc.Servers = openapi3.Servers{{URL: commonBasePath}}
+
+	for _, proxyEndpoint := range b.model.ProxyEndpoints.List {
+		prefix := ""
+		if len(basePaths) > 1 {
+			prefix = strings.TrimSuffix(proxyEndpointBasePath(proxyEndpoint), "/")
+		}
+
+		var preFlowSteps v1.StepList
+		if proxyEndpoint.PreFlow != nil && proxyEndpoint.PreFlow.Request != nil {
+			preFlowSteps = proxyEndpoint.PreFlow.Request.Steps
+		}
+
+		//security policies in the PreFlow apply to all operations
+		security, err := b.securityRequirements(preFlowSteps)
+		if err != nil {
+			return err
+		}
+		for _, requirement := range security {
+			if !slices.ContainsFunc(b.doc.Security, func(r openapi3.SecurityRequirement) bool { return fmt.Sprint(r) == fmt.Sprint(requirement) }) {
+				b.doc.Security = append(b.doc.Security, requirement)
+			}
+		}
+
+		if proxyEndpoint.Flows == nil {
+			continue
+		}
+		for _, flow := range proxyEndpoint.Flows.List {
+			if flow == nil {
+				continue
+			}
+			if err = b.addFlow(proxyEndpoint, flow, prefix, preFlowSteps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func proxyEndpointBasePath(v *v1.ProxyEndpoint) string {
+	if v.HTTPProxyConnection == nil || strings.TrimSpace(v.HTTPProxyConnection.BasePath) == "" {
+		return "/"
+	}
+	return strings.TrimSpace(v.HTTPProxyConnection.BasePath)
+}
+
+func (b *oasBuilder) addFlow(proxyEndpoint *v1.ProxyEndpoint, flow *v1.Flow, prefix string, preFlowSteps v1.StepList) error {
+	//flows without a condition (e.g. CatchAll) do not map to an operation
+	if strings.TrimSpace(flow.Condition) == "" {
+		return nil
+	}
+
+	location := fmt.Sprintf("ProxyEndpoint(name: %s).Flow(name: %s)", proxyEndpoint.Name, flow.Name)
+	node, err := condition.Parse(flow.Condition)
+	if err != nil {
+		b.warnings = append(b.warnings, errors.Errorf(`skipped flow at "%s". %s`, location, err.Error()))
+		return nil
+	}
+
+	paths, verbs, ok := routeConstraints(node)
+	if !ok || len(paths) == 0 || len(verbs) == 0 {
+		b.warnings = append(b.warnings, errors.Errorf(`skipped flow at "%s". condition '%s' does not match on both proxy.pathsuffix and request.verb`, location, strings.TrimSpace(flow.Condition)))
+		return nil
+	}
+
+	var flowSteps v1.StepList
+	if flow.Request != nil {
+		flowSteps = flow.Request.Steps
+	}
+	steps := append(slices.Clone(preFlowSteps), flowSteps...)
+
+	hints, err := b.extractVariablesHints(steps)
+	if err != nil {
+		return err
+	}
+
+	security, err := b.securityRequirements(flowSteps)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		oasPath, pathParams := oasPathTemplate(path, hints.uriPatterns)
+		for j, verb := range verbs {
+			operationId := flow.Name
+			if len(paths) > 1 {
+				operationId = fmt.Sprintf("%s-%d", operationId, i+1)
+			}
+			if len(verbs) > 1 {
+				operationId = fmt.Sprintf("%s-%s", operationId, strings.ToLower(verbs[j]))
+			}
+
+			if specPath, operation := b.specOperation(prefix, oasPath, verb); operation != nil {
+				b.doc.AddOperation(specPath, verb, operation)
+				continue
+			}
+
+			operation := openapi3.NewOperation()
+			operation.OperationID = operationId
+			operation.Description = strings.TrimSpace(flow.Description)
+			operation.Responses = openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK")}))
+
+			for _, name := range pathParams {
+				operation.AddParameter(openapi3.NewPathParameter(name).WithSchema(openapi3.NewStringSchema()))
+			}
+			for _, name := range hints.queryParams {
+				operation.AddParameter(openapi3.NewQueryParameter(name).WithSchema(openapi3.NewStringSchema()))
+			}
+			for _, name := range hints.headers {
+				operation.AddParameter(openapi3.NewHeaderParameter(name).WithSchema(openapi3.NewStringSchema()))
+			}
+
+			if verb != "GET" && verb != "HEAD" && verb != "DELETE" {
+				operation.RequestBody = hints.requestBody()
+			}
+
+			if len(security) > 0 {
+				operation.Security = &security
+			}
+
+			b.doc.AddOperation(prefix+oasPath, verb, operation)
+		}
+	}
+
+	return nil
+}
+
+// specOperation returns the matching operation (and its path) from the bundled OpenAPI Description, if any.
+// Paths within the bundled OpenAPI Description may be relative to the base path, or include it.
+func (b *oasBuilder) specOperation(prefix string, path string, verb string) (string, *openapi3.Operation) {
+	if b.spec == nil || b.spec.Paths == nil {
+		return "", nil
+	}
+
+	//paths that include the base path are preferred, and paths are visited in a fixed order, so that the same operation is always picked
+	specPaths := b.spec.Paths.InMatchingOrder()
+	for _, specPath := range specPaths {
+		if operation := b.spec.Paths.Value(specPath).GetOperation(verb); operation != nil && wildcardPath(specPath) == wildcardPath(prefix+path) {
+			return specPath, operation
+		}
+	}
+	for _, specPath := range specPaths {
+		if operation := b.spec.Paths.Value(specPath).GetOperation(verb); operation != nil && wildcardPath(specPath) == wildcardPath(path) {
+			return prefix + specPath, operation
+		}
+	}
+	return "", nil
+}
+
+var pathParamRegex = regexp.MustCompile(`\{[^}]*}`)
+
+// wildcardPath replaces path parameters with "*", so that path templates with different parameter names can be compared
+func wildcardPath(path string) string {
+	return pathParamRegex.ReplaceAllString(path, "*")
+}
+
+// routeConstraints extracts the path patterns and verbs from a flow condition such as
+// (proxy.pathsuffix MatchesPath "/pets/*") and (request.verb = "GET")
+func routeConstraints(node condition.Node) (paths []string, verbs []string, ok bool) {
+	for _, term := range conjunction(node) {
+		if values, isPath := comparisonValues(term, "proxy.pathsuffix", condition.OpMatchesPath, condition.OpMatches, condition.OpEquals, condition.OpEqualsCaseInsensitive); isPath {
+			if paths != nil {
+				return nil, nil, false
+			}
+			paths = values
+			continue
+		}
+		if values, isVerb := comparisonValues(term, "request.verb", condition.OpEquals, condition.OpEqualsCaseInsensitive); isVerb {
+			if verbs != nil {
+				return nil, nil, false
+			}
+			for _, value := range values {
+				verbs = append(verbs, strings.ToUpper(value))
+			}
+			continue
+		}
+		//other terms (e.g. header checks) do not change the path or verb of the operation
+		if strings.Contains(term.String(), "proxy.pathsuffix") || strings.Contains(term.String(), "request.verb") {
+			return nil, nil, false
+		}
+	}
+	return paths, verbs, true
+}
+
+// conjunction flattens a chain of "and" expressions into its terms
+func conjunction(node condition.Node) []condition.Node {
+	if b, ok := node.(*condition.BinaryExpr); ok && b.Op == condition.OpAnd {
+		return append(conjunction(b.Left), conjunction(b.Right)...)
+	}
+	return []condition.Node{node}
+}
+
+// comparisonValues returns the literal values the variable is compared against, for a single comparison
+// or an "or" of comparisons on the same variable
+func comparisonValues(node condition.Node, variable string, ops ...condition.Operator) ([]string, bool) {
+	b, ok := node.(*condition.BinaryExpr)
+	if !ok {
+		return nil, false
+	}
+
+	if b.Op == condition.OpOr {
+		left, ok := comparisonValues(b.Left, variable, ops...)
+		if !ok {
+			return nil, false
+		}
+		right, ok := comparisonValues(b.Right, variable, ops...)
+		if !ok {
+			return nil, false
+		}
+		return append(left, right...), true
+	}
+
+	if !slices.Contains(ops, b.Op) {
+		return nil, false
+	}
+	v, ok := b.Left.(*condition.Variable)
+	if !ok || !strings.EqualFold(v.Name, variable) {
+		return nil, false
+	}
+	l, ok := b.Right.(*condition.Literal)
+	if !ok || l.Kind != condition.LiteralString {
+		return nil, false
+	}
+	return []string{l.Value}, true
+}
+
+// oasPathTemplate converts a MatchesPath pattern such as "/pets/*" into a path template such as "/pets/{petId}".
+// Parameter names are taken from ExtractVariables URIPath patterns with the same shape, or generated otherwise.
+func oasPathTemplate(pattern string, uriPatterns []string) (string, []string) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+
+	var names []string
+	for _, uriPattern := range uriPatterns {
+		uriSegments := strings.Split(strings.Trim(uriPattern, "/"), "/")
+		if len(uriSegments) != len(segments) {
+			continue
+		}
+		var candidate []string
+		for i, segment := range segments {
+			isWildcard := segment == "*" || segment == "**"
+			isParam := strings.HasPrefix(uriSegments[i], "{") && strings.HasSuffix(uriSegments[i], "}")
+			if isWildcard && isParam {
+				candidate = append(candidate, strings.Trim(uriSegments[i], "{}"))
+			} else if isWildcard || isParam || segment != uriSegments[i] {
+				candidate = nil
+				break
+			}
+		}
+		if candidate != nil {
+			names = candidate
+			break
+		}
+	}
+
+	var params []string
+	for i, segment := range segments {
+		if segment != "*" && segment != "**" {
+			continue
+		}
+		name := fmt.Sprintf("param%d", len(params)+1)
+		if len(params) < len(names) {
+			name = names[len(params)]
+		}
+		params = append(params, name)
+		segments[i] = fmt.Sprintf("{%s}", name)
+	}
+
+	return "/" + strings.Join(segments, "/"), params
+}
+
+// extractVariablesHints holds what ExtractVariables policies tell us about the request
+type extractVariablesHints struct {
+	uriPatterns []string
+	queryParams []string
+	headers     []string
+	formParams  []string
+	jsonPayload bool
+}
+
+func (h *extractVariablesHints) requestBody() *openapi3.RequestBodyRef {
+	if h.jsonPayload {
+		return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())}
+	}
+	if len(h.formParams) > 0 {
+		schema := openapi3.NewObjectSchema()
+		for _, name := range h.formParams {
+			schema.WithProperty(name, openapi3.NewStringSchema())
+		}
+		return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithFormDataSchema(schema)}
+	}
+	return nil
+}
+
+func (b *oasBuilder) extractVariablesHints(steps v1.StepList) (*extractVariablesHints, error) {
+	hints := &extractVariablesHints{}
+	for _, step := range steps {
+		policy := b.policies[step.Name]
+		if policy == nil || policy.Type() != "ExtractVariables" {
+			continue
+		}
+
+		extractVariables := &v1.ExtractVariables{}
+		if err := policy.Unmarshal(extractVariables); err != nil {
+			return nil, err
+		}
+		if extractVariables.Source != nil && strings.TrimSpace(extractVariables.Source.Value) != "" && strings.TrimSpace(extractVariables.Source.Value) != "request" {
+			continue
+		}
+
+		for _, uriPath := range extractVariables.URIPaths {
+			for _, pattern := range uriPath.Children {
+				if pattern.XMLName.Local == "Pattern" {
+					hints.uriPatterns = append(hints.uriPatterns, strings.TrimSpace(string(pattern.CharData)))
+				}
+			}
+		}
+		hints.queryParams = appendNames(hints.queryParams, extractVariables.QueryParams)
+		hints.headers = appendNames(hints.headers, extractVariables.Headers)
+		hints.formParams = appendNames(hints.formParams, extractVariables.FormParams)
+		if extractVariables.JSONPayload != nil {
+			hints.jsonPayload = true
+		}
+	}
+	return hints, nil
+}
+
+func appendNames(names []string, nodes v1.AnyList) []string {
+	for _, node := range nodes {
+		for _, attr := range node.Attrs {
+			if attr.Name.Local == "name" && !slices.Contains(names, attr.Value) {
+				names = append(names, attr.Value)
+			}
+		}
+	}
+	return names
+}
+
+// securityRequirements adds a security scheme for each VerifyAPIKey and OAuthV2 (VerifyAccessToken) policy within the steps
+func (b *oasBuilder) securityRequirements(steps v1.StepList) (openapi3.SecurityRequirements, error) {
+	var requirements openapi3.SecurityRequirements
+	for _, step := range steps {
+		policy := b.policies[step.Name]
+		if policy == nil {
+			continue
+		}
+
+		var scheme *openapi3.SecurityScheme
+		switch policy.Type() {
+		case "VerifyAPIKey":
+			verifyAPIKey := &v1.VerifyAPIKey{}
+			if err := policy.Unmarshal(verifyAPIKey); err != nil {
+				return nil, err
+			}
+			if scheme = apiKeySecurityScheme(verifyAPIKey); scheme == nil {
+				b.warnings = append(b.warnings, errors.Errorf(`could not determine where VerifyAPIKey policy "%s" reads the API key from`, policy.Name()))
+				continue
+			}
+		case "OAuthV2":
+			oauthV2 := &v1.OAuthV2{}
+			if err := policy.Unmarshal(oauthV2); err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(oauthV2.Operation) != "VerifyAccessToken" {
+				continue
+			}
+			scheme = &openapi3.SecurityScheme{Type: "http", Scheme: "bearer"}
+		default:
+			continue
+		}
+
+		if b.doc.Components.SecuritySchemes == nil {
+			b.doc.Components.SecuritySchemes = openapi3.SecuritySchemes{}
+		}
+		b.doc.Components.SecuritySchemes[policy.Name()] = &openapi3.SecuritySchemeRef{Value: scheme}
+		requirements = append(requirements, openapi3.NewSecurityRequirement().Authenticate(policy.Name()))
+	}
+	return requirements, nil
+}
+
+func apiKeySecurityScheme(v *v1.VerifyAPIKey) *openapi3.SecurityScheme {
+	if v.APIKey == nil {
+		return nil
+	}
+	ref := strings.TrimSpace(v.APIKey.Ref)
+	for prefix, in := range map[string]string{"request.queryparam.": "query", "request.header.": "header"} {
+		if strings.HasPrefix(strings.ToLower(ref), prefix) {
+			return &openapi3.SecurityScheme{Type: "apiKey", In: in, Name: ref[len(prefix):]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"context"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBundle2OASFile(t *testing.T) {
+	tests := []struct {
+		dir      string
+		warnings []string
+	}{
+		{
+			"petstore",
+			[]string{
+				`skipped flow at "ProxyEndpoint(name: default).Flow(name: debug)". condition 'request.header.x-debug = "true"' does not match on both proxy.pathsuffix and request.verb`,
+			},
+		},
+		{
+			"with-spec",
+			nil,
+		},
+		{
+			"with-spec-prefix",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			ttDir := filepath.Join("testdata", "oas", tt.dir)
+			outputFile := filepath.Join(t.TempDir(), "openapi.yaml")
+
+			err := Bundle2OASFile(filepath.Join(ttDir, "apiproxy.yaml"), outputFile)
+			require.NoError(t, err)
+
+			want, err := os.ReadFile(filepath.Join(ttDir, "out-openapi.yaml"))
+			require.NoError(t, err)
+			got, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got))
+
+			doc, err := openapi3.NewLoader().LoadFromData(got)
+			require.NoError(t, err)
+			require.NoError(t, doc.Validate(context.Background()))
+
+			model, err := LoadModel(filepath.Join(ttDir, "apiproxy.yaml"))
+			require.NoError(t, err)
+			_, warnings, err := APIProxy2OAS3(model.(*v1.APIProxyModel))
+			require.NoError(t, err)
+
+			var gotWarnings []string
+			for _, warning := range warnings {
+				gotWarnings = append(gotWarnings, warning.Error())
+			}
+			require.Equal(t, tt.warnings, gotWarnings)
+		})
+	}
+}
+
+func TestAPIProxy2OAS3_BundledSpec(t *testing.T) {
+	model, err := LoadModel(filepath.Join("testdata", "oas", "with-spec-prefix", "apiproxy.yaml"))
+	require.NoError(t, err)
+	proxyModel := model.(*v1.APIProxyModel)
+
+	//the operation that includes the base path is always picked over the relative one
+	for i := 0; i < 20; i++ {
+		doc, _, err := APIProxy2OAS3(proxyModel)
+		require.NoError(t, err)
+		require.Nil(t, doc.Paths.Value("/v1/pets/{petId}"))
+		require.Equal(t, "showPetById", doc.Paths.Value("/v1/pets/{id}").Get.OperationID)
+	}
+
+	b := &oasBuilder{model: proxyModel, doc: &openapi3.T{Components: &openapi3.Components{}}}
+	require.NoError(t, b.loadBundledSpec())
+	b.doc.Components.SecuritySchemes["VA-Key"] = &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme()}
+	require.Contains(t, b.doc.Components.SecuritySchemes, "BearerAuth")
+	require.NotContains(t, b.spec.Components.SecuritySchemes, "VA-Key")
+}
@@ -0,0 +1,429 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagram
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/go-errors/errors"
+	"strings"
+)
+
+type target struct {
+	entry string
+	ends  []end
+}
+
+type builder struct {
+	g           *Graph
+	policies    map[string]*v1.Policy
+	sharedFlows map[string]string
+	targets     map[string]*target
+	model       *v1.APIProxyModel
+}
+
+// Build creates the diagram of the request/response pipeline of an API proxy, or the steps of a shared flow bundle
+func Build(model v1.Model) (*Graph, error) {
+	b := &builder{
+		g:           NewGraph(),
+		policies:    map[string]*v1.Policy{},
+		sharedFlows: map[string]string{},
+		targets:     map[string]*target{},
+	}
+
+	switch m := model.(type) {
+	case *v1.APIProxyModel:
+		b.model = m
+		b.addPolicies(m.Policies.List)
+		if err := b.buildAPIProxy(m); err != nil {
+			return nil, err
+		}
+	case *v1.SharedFlowBundleModel:
+		b.addPolicies(m.Policies.List)
+		if err := b.buildSharedFlowBundle(m); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported model type %T", model)
+	}
+
+	return b.g, nil
+}
+
+func (b *builder) addPolicies(policies v1.PolicyList) {
+	for _, policy := range policies {
+		b.policies[policy.Name()] = policy
+	}
+}
+
+func (b *builder) buildAPIProxy(m *v1.APIProxyModel) error {
+	client := b.g.AddNode(b.g.Root, Circle, "Client")
+
+	for _, proxyEndpoint := range m.ProxyEndpoints.List {
+		basePath := "/"
+		if proxyEndpoint.HTTPProxyConnection != nil && proxyEndpoint.HTTPProxyConnection.BasePath != "" {
+			basePath = proxyEndpoint.HTTPProxyConnection.BasePath
+		}
+
+		cluster := b.g.AddCluster(b.g.Root, fmt.Sprintf("ProxyEndpoint: %s", proxyEndpoint.Name))
+		entry := b.g.AddNode(cluster, Rounded, proxyEndpoint.Name, basePath)
+		b.g.AddEdge(client, entry, "", false)
+
+		//request
+		ends := []end{{from: entry}}
+		var err error
+		if proxyEndpoint.PreFlow != nil {
+			if ends, err = b.request(cluster, "PreFlow (Request)", ends, proxyEndpoint.PreFlow.Request); err != nil {
+				return err
+			}
+		}
+		if proxyEndpoint.Flows != nil {
+			if ends, err = b.flows(cluster, ends, proxyEndpoint.Flows.List, true); err != nil {
+				return err
+			}
+		}
+		if proxyEndpoint.PostFlow != nil {
+			if ends, err = b.request(cluster, "PostFlow (Request)", ends, proxyEndpoint.PostFlow.Request); err != nil {
+				return err
+			}
+		}
+
+		//route rules
+		response := b.g.AddNode(cluster, Rounded, "Response")
+		if proxyEndpoint.RouteRules != nil && len(*proxyEndpoint.RouteRules) > 0 {
+			decision := b.g.AddNode(cluster, Diamond, "RouteRules")
+			b.g.connect(ends, decision)
+			catchAll := false
+			for _, routeRule := range *proxyEndpoint.RouteRules {
+				if routeRule == nil {
+					continue
+				}
+				label := branchLabel(routeRule.Name, routeRule.Condition)
+				switch {
+				case routeRule.TargetEndpoint != "":
+					t, err := b.target(routeRule.TargetEndpoint)
+					if err != nil {
+						return err
+					}
+					b.g.AddEdge(decision, t.entry, label, false)
+					b.g.connect(t.ends, response)
+				case routeRule.IntegrationEndpoint != "":
+					integration := b.g.AddNode(b.g.Root, Cylinder, "IntegrationEndpoint", routeRule.IntegrationEndpoint)
+					b.g.AddEdge(decision, integration, label, false)
+					b.g.AddEdge(integration, response, "", false)
+				default:
+					b.g.AddEdge(decision, response, label, false)
+				}
+				if strings.TrimSpace(routeRule.Condition) == "" {
+					catchAll = true
+					break
+				}
+			}
+			if !catchAll {
+				b.g.AddEdge(decision, response, "no match", false)
+			}
+		} else {
+			b.g.connect(ends, response)
+		}
+
+		//response
+		ends = []end{{from: response}}
+		if proxyEndpoint.PreFlow != nil {
+			if ends, err = b.response(cluster, "PreFlow (Response)", ends, proxyEndpoint.PreFlow.Response); err != nil {
+				return err
+			}
+		}
+		if proxyEndpoint.Flows != nil {
+			if ends, err = b.flows(cluster, ends, proxyEndpoint.Flows.List, false); err != nil {
+				return err
+			}
+		}
+		if proxyEndpoint.PostFlow != nil {
+			if ends, err = b.response(cluster, "PostFlow (Response)", ends, proxyEndpoint.PostFlow.Response); err != nil {
+				return err
+			}
+		}
+
+		sent := b.g.AddNode(cluster, Circle, "Client")
+		b.g.connect(ends, sent)
+
+		if proxyEndpoint.PostClientFlow != nil {
+			if _, err = b.response(cluster, "PostClientFlow", []end{{from: sent, label: "after response"}}, proxyEndpoint.PostClientFlow.Response); err != nil {
+				return err
+			}
+		}
+
+		if err = b.faultRules(cluster, proxyEndpoint.FaultRules, proxyEndpoint.DefaultFaultRule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// target creates the target endpoint cluster the first time it is referenced
+func (b *builder) target(name string) (*target, error) {
+	if t, ok := b.targets[name]; ok {
+		return t, nil
+	}
+
+	var targetEndpoint *v1.TargetEndpoint
+	for _, candidate := range b.model.TargetEndpoints.List {
+		if candidate.Name == name {
+			targetEndpoint = candidate
+			break
+		}
+	}
+
+	t := &target{}
+	b.targets[name] = t
+	if targetEndpoint == nil {
+		t.entry = b.g.AddNode(b.g.Root, Rounded, name, "(missing)")
+		t.ends = []end{{from: t.entry}}
+		return t, nil
+	}
+
+	cluster := b.g.AddCluster(b.g.Root, fmt.Sprintf("TargetEndpoint: %s", name))
+	t.entry = b.g.AddNode(cluster, Rounded, name)
+
+	ends := []end{{from: t.entry}}
+	var err error
+	if ends, err = b.request(cluster, "PreFlow (Request)", ends, targetEndpoint.PreFlow.Request); err != nil {
+		return nil, err
+	}
+	if ends, err = b.flows(cluster, ends, targetEndpoint.Flows.List, true); err != nil {
+		return nil, err
+	}
+	if ends, err = b.request(cluster, "PostFlow (Request)", ends, targetEndpoint.PostFlow.Request); err != nil {
+		return nil, err
+	}
+
+	backend := b.g.AddNode(cluster, Cylinder, targetLabel(targetEndpoint)...)
+	b.g.connect(ends, backend)
+
+	ends = []end{{from: backend}}
+	if ends, err = b.response(cluster, "PreFlow (Response)", ends, targetEndpoint.PreFlow.Response); err != nil {
+		return nil, err
+	}
+	if ends, err = b.flows(cluster, ends, targetEndpoint.Flows.List, false); err != nil {
+		return nil, err
+	}
+	if ends, err = b.response(cluster, "PostFlow (Response)", ends, targetEndpoint.PostFlow.Response); err != nil {
+		return nil, err
+	}
+	t.ends = ends
+
+	if err = b.faultRules(cluster, targetEndpoint.FaultRules, targetEndpoint.DefaultFaultRule); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// targetLabel describes where the target endpoint sends requests to
+func targetLabel(v *v1.TargetEndpoint) []string {
+	if v.LocalTargetConnection != nil {
+		label := []string{"LocalTargetConnection"}
+		if v.LocalTargetConnection.APIProxy != nil {
+			label = append(label, fmt.Sprintf("APIProxy: %s", strings.TrimSpace(v.LocalTargetConnection.APIProxy.Value)))
+		}
+		if v.LocalTargetConnection.Path != "" {
+			label = append(label, fmt.Sprintf("Path: %s", v.LocalTargetConnection.Path))
+		}
+		return label
+	}
+	if v.HTTPTargetConnection == nil {
+		return []string{"Backend"}
+	}
+	if v.HTTPTargetConnection.URL != "" {
+		return []string{strings.TrimSpace(v.HTTPTargetConnection.URL)}
+	}
+	label := []string{"LoadBalancer"}
+	if loadBalancer := v.HTTPTargetConnection.LoadBalancer; loadBalancer != nil && loadBalancer.Servers != nil {
+		for _, server := range *loadBalancer.Servers {
+			label = append(label, server.Name)
+		}
+	}
+	if v.HTTPTargetConnection.Path != "" {
+		label = append(label, fmt.Sprintf("Path: %s", v.HTTPTargetConnection.Path))
+	}
+	return label
+}
+
+// flows adds a decision node with a branch for each conditional flow.
+// Flows after the first unconditional flow are unreachable, and are not included.
+func (b *builder) flows(cluster *Cluster, ends []end, flows v1.FlowList, request bool) ([]end, error) {
+	if len(flows) == 0 {
+		return ends, nil
+	}
+
+	decision := b.g.AddNode(cluster, Diamond, "Flows")
+	b.g.connect(ends, decision)
+
+	var result []end
+	catchAll := false
+	for _, flow := range flows {
+		if flow == nil {
+			continue
+		}
+
+		branch := []end{{from: decision, label: branchLabel(flow.Name, flow.Condition)}}
+		var err error
+		if request {
+			branch, err = b.request(cluster, fmt.Sprintf("Flow: %s (Request)", flow.Name), branch, flow.Request)
+		} else {
+			branch, err = b.response(cluster, fmt.Sprintf("Flow: %s (Response)", flow.Name), branch, flow.Response)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, branch...)
+
+		if strings.TrimSpace(flow.Condition) == "" {
+			catchAll = true
+			break
+		}
+	}
+
+	if !catchAll {
+		result = append(result, end{from: decision, label: "no match"})
+	}
+	return result, nil
+}
+
+func (b *builder) faultRules(parent *Cluster, faultRules *v1.FaultRules, defaultFaultRule *v1.DefaultFaultRule) error {
+	if (faultRules == nil || len(faultRules.List) == 0) && defaultFaultRule == nil {
+		return nil
+	}
+
+	cluster := b.g.AddCluster(parent, "Fault Handling")
+	fault := b.g.AddNode(cluster, Rounded, "Fault")
+	ends := []end{{from: fault}}
+
+	var matched []end
+	if faultRules != nil && len(faultRules.List) > 0 {
+		decision := b.g.AddNode(cluster, Diamond, "FaultRules")
+		b.g.connect(ends, decision)
+		ends = []end{{from: decision, label: "no match"}}
+
+		for _, faultRule := range faultRules.List {
+			if faultRule == nil {
+				continue
+			}
+			branch := []end{{from: decision, label: branchLabel(faultRule.Name, faultRule.Condition)}}
+			branch, err := b.steps(b.g.AddCluster(cluster, fmt.Sprintf("FaultRule: %s", faultRule.Name)), branch, faultRule.Steps)
+			if err != nil {
+				return err
+			}
+			matched = append(matched, branch...)
+		}
+	}
+
+	if defaultFaultRule != nil {
+		if defaultFaultRule.AlwaysEnforce {
+			ends = append(ends, matched...)
+		}
+		if _, err := b.steps(b.g.AddCluster(cluster, "DefaultFaultRule"), ends, defaultFaultRule.Steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *builder) buildSharedFlowBundle(m *v1.SharedFlowBundleModel) error {
+	//create all the shared flows first, so that FlowCallouts between them link to the right cluster
+	clusters := map[string]*Cluster{}
+	for _, sharedFlow := range m.SharedFlows.List {
+		cluster := b.g.AddCluster(b.g.Root, fmt.Sprintf("SharedFlow: %s", sharedFlow.Name))
+		b.sharedFlows[sharedFlow.Name] = b.g.AddNode(cluster, Subroutine, "SharedFlow", sharedFlow.Name)
+		clusters[sharedFlow.Name] = cluster
+	}
+
+	for _, sharedFlow := range m.SharedFlows.List {
+		entry := b.sharedFlows[sharedFlow.Name]
+		if _, err := b.steps(clusters[sharedFlow.Name], []end{{from: entry}}, sharedFlow.Steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sharedFlow returns the node for the shared flow, creating it the first time it is referenced
+func (b *builder) sharedFlow(name string) string {
+	if id, ok := b.sharedFlows[name]; ok {
+		return id
+	}
+	id := b.g.AddNode(b.g.Root, Subroutine, "SharedFlow", name)
+	b.sharedFlows[name] = id
+	return id
+}
+
+func (b *builder) request(parent *Cluster, label string, ends []end, v *v1.Request) ([]end, error) {
+	if v == nil || len(v.Steps) == 0 {
+		return ends, nil
+	}
+	return b.steps(b.g.AddCluster(parent, label), ends, v.Steps)
+}
+
+func (b *builder) response(parent *Cluster, label string, ends []end, v *v1.Response) ([]end, error) {
+	if v == nil || len(v.Steps) == 0 {
+		return ends, nil
+	}
+	return b.steps(b.g.AddCluster(parent, label), ends, v.Steps)
+}
+
+// steps adds a node for each step, chained one after the other
+func (b *builder) steps(cluster *Cluster, ends []end, steps v1.StepList) ([]end, error) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		label := []string{step.Name}
+		policy := b.policies[step.Name]
+		if policy != nil {
+			label = append(label, fmt.Sprintf("(%s)", policy.Type()))
+		}
+		if condition := oneLine(step.Condition); condition != "" {
+			label = append(label, fmt.Sprintf("if %s", condition))
+		}
+
+		id := b.g.AddNode(cluster, Box, label...)
+		b.g.connect(ends, id)
+		ends = []end{{from: id}}
+
+		if policy == nil || policy.Type() != "FlowCallout" {
+			continue
+		}
+		flowCallout := &v1.FlowCallout{}
+		if err := policy.Unmarshal(flowCallout); err != nil {
+			return nil, err
+		}
+		if flowCallout.SharedFlowBundle != "" {
+			b.g.AddEdge(id, b.sharedFlow(flowCallout.SharedFlowBundle), "", true)
+		}
+	}
+	return ends, nil
+}
+
+// branchLabel is the label of the edge leading into a flow, route rule or fault rule
+func branchLabel(name string, condition string) string {
+	condition = oneLine(condition)
+	if condition == "" {
+		return name
+	}
+	return fmt.Sprintf("%s: %s", name, condition)
+}
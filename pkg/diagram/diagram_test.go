@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagram
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		dir   string
+		input string
+	}{
+		{"petstore", "apiproxy.yaml"},
+		{"sharedflow", "sharedflowbundle.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			ttDir := filepath.Join("testdata", tt.dir)
+			model, err := bundle.LoadModel(filepath.Join(ttDir, tt.input))
+			require.NoError(t, err)
+
+			graph, err := Build(model)
+			require.NoError(t, err)
+
+			wantMermaid, err := os.ReadFile(filepath.Join(ttDir, "out.mmd"))
+			require.NoError(t, err)
+			require.Equal(t, string(wantMermaid), string(Mermaid(graph)))
+
+			wantDOT, err := os.ReadFile(filepath.Join(ttDir, "out.dot"))
+			require.NoError(t, err)
+			require.Equal(t, string(wantDOT), string(DOT(graph)))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Shape string
+
+const (
+	Box        Shape = "box"
+	Rounded    Shape = "rounded"
+	Diamond    Shape = "diamond"
+	Circle     Shape = "circle"
+	Cylinder   Shape = "cylinder"
+	Subroutine Shape = "subroutine"
+)
+
+// Node is a single box in the diagram, such as a step or a decision
+type Node struct {
+	ID    string
+	Label []string
+	Shape Shape
+}
+
+// Edge connects two nodes, optionally with a label (e.g. a condition)
+type Edge struct {
+	From   string
+	To     string
+	Label  string
+	Dashed bool
+}
+
+// Cluster groups nodes and other clusters, such as an endpoint or a flow
+type Cluster struct {
+	ID       string
+	Label    string
+	Nodes    []*Node
+	Clusters []*Cluster
+}
+
+// Graph is a format independent representation of the diagram
+type Graph struct {
+	Root  *Cluster
+	Edges []*Edge
+
+	nextID int
+}
+
+func NewGraph() *Graph {
+	return &Graph{Root: &Cluster{}}
+}
+
+func (g *Graph) id(prefix string) string {
+	g.nextID++
+	return fmt.Sprintf("%s%d", prefix, g.nextID)
+}
+
+// AddCluster creates a new cluster within the parent cluster
+func (g *Graph) AddCluster(parent *Cluster, label string) *Cluster {
+	cluster := &Cluster{ID: g.id("c"), Label: label}
+	parent.Clusters = append(parent.Clusters, cluster)
+	return cluster
+}
+
+// AddNode creates a new node within the cluster, and returns its ID
+func (g *Graph) AddNode(cluster *Cluster, shape Shape, label ...string) string {
+	node := &Node{ID: g.id("n"), Label: label, Shape: shape}
+	cluster.Nodes = append(cluster.Nodes, node)
+	return node.ID
+}
+
+func (g *Graph) AddEdge(from string, to string, label string, dashed bool) {
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Label: label, Dashed: dashed})
+}
+
+// end is a loose end of the diagram, waiting to be connected to the next node
+type end struct {
+	from  string
+	label string
+}
+
+// connect adds an edge from each of the loose ends to the node
+func (g *Graph) connect(ends []end, to string) {
+	for _, e := range ends {
+		g.AddEdge(e.from, to, e.label, false)
+	}
+}
+
+// oneLine collapses all whitespace (e.g. in multi-line conditions) into single spaces
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
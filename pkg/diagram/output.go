@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagram
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+var mermaidShapes = map[Shape][2]string{
+	Box:        {"[", "]"},
+	Rounded:    {"(", ")"},
+	Diamond:    {"{", "}"},
+	Circle:     {"((", "))"},
+	Cylinder:   {"[(", ")]"},
+	Subroutine: {"[[", "]]"},
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func Mermaid(g *Graph) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("flowchart TD\n")
+	writeMermaidCluster(&buffer, g.Root, 1)
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Dashed {
+			arrow = "-.->"
+		}
+		if edge.Label != "" {
+			_, _ = fmt.Fprintf(&buffer, "  %s %s|%s| %s\n", edge.From, arrow, mermaidText(edge.Label), edge.To)
+		} else {
+			_, _ = fmt.Fprintf(&buffer, "  %s %s %s\n", edge.From, arrow, edge.To)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func writeMermaidCluster(buffer *bytes.Buffer, cluster *Cluster, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, node := range cluster.Nodes {
+		shape := mermaidShapes[node.Shape]
+		_, _ = fmt.Fprintf(buffer, "%s%s%s%s%s\n", indent, node.ID, shape[0], mermaidText(node.Label...), shape[1])
+	}
+	for _, subCluster := range cluster.Clusters {
+		_, _ = fmt.Fprintf(buffer, "%ssubgraph %s[%s]\n", indent, subCluster.ID, mermaidText(subCluster.Label))
+		writeMermaidCluster(buffer, subCluster, depth+1)
+		_, _ = fmt.Fprintf(buffer, "%send\n", indent)
+	}
+}
+
+func mermaidText(lines ...string) string {
+	var escaped []string
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "&", "#amp;")
+		line = strings.ReplaceAll(line, `"`, "#quot;")
+		line = strings.ReplaceAll(line, "<", "#lt;")
+		line = strings.ReplaceAll(line, ">", "#gt;")
+		line = strings.ReplaceAll(line, "|", "#124;")
+		escaped = append(escaped, line)
+	}
+	return `"` + strings.Join(escaped, "<br/>") + `"`
+}
+
+var dotShapes = map[Shape]string{
+	Box:        `shape=box`,
+	Rounded:    `shape=box, style=rounded`,
+	Diamond:    `shape=diamond`,
+	Circle:     `shape=circle`,
+	Cylinder:   `shape=cylinder`,
+	Subroutine: `shape=box, peripheries=2`,
+}
+
+// DOT renders the graph as a Graphviz digraph
+func DOT(g *Graph) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("digraph G {\n")
+	buffer.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	buffer.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	writeDOTCluster(&buffer, g.Root, 1)
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%s", dotText(edge.Label)))
+		}
+		if edge.Dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			_, _ = fmt.Fprintf(&buffer, "  %s -> %s [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+		} else {
+			_, _ = fmt.Fprintf(&buffer, "  %s -> %s;\n", edge.From, edge.To)
+		}
+	}
+	buffer.WriteString("}\n")
+	return buffer.Bytes()
+}
+
+func writeDOTCluster(buffer *bytes.Buffer, cluster *Cluster, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, node := range cluster.Nodes {
+		_, _ = fmt.Fprintf(buffer, "%s%s [label=%s, %s];\n", indent, node.ID, dotText(node.Label...), dotShapes[node.Shape])
+	}
+	for _, subCluster := range cluster.Clusters {
+		_, _ = fmt.Fprintf(buffer, "%ssubgraph cluster_%s {\n", indent, subCluster.ID)
+		_, _ = fmt.Fprintf(buffer, "%s  label=%s;\n", indent, dotText(subCluster.Label))
+		writeDOTCluster(buffer, subCluster, depth+1)
+		_, _ = fmt.Fprintf(buffer, "%s}\n", indent)
+	}
+}
+
+func dotText(lines ...string) string {
+	var escaped []string
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, `\`, `\\`)
+		line = strings.ReplaceAll(line, `"`, `\"`)
+		escaped = append(escaped, line)
+	}
+	return `"` + strings.Join(escaped, `\n`) + `"`
+}
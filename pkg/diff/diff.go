@@ -0,0 +1,384 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/condition"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/beevik/etree"
+	"github.com/go-errors/errors"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+	Moved   Kind = "moved"
+)
+
+// Change is a single semantic difference between two bundles
+type Change struct {
+	Kind Kind   `json:"kind"`
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+func (c *Change) String() string {
+	switch c.Kind {
+	case Added:
+		if c.New != "" {
+			return fmt.Sprintf("+ %s: %q", c.Path, c.New)
+		}
+		return fmt.Sprintf("+ %s", c.Path)
+	case Removed:
+		if c.Old != "" {
+			return fmt.Sprintf("- %s: %q", c.Path, c.Old)
+		}
+		return fmt.Sprintf("- %s", c.Path)
+	case Moved:
+		return fmt.Sprintf("> %s: moved from position %s to %s", c.Path, c.Old, c.New)
+	}
+	return fmt.Sprintf("~ %s: %q -> %q", c.Path, c.Old, c.New)
+}
+
+// bundle groups are keyed by the top level dir of the bundle files
+var groups = map[string]string{
+	"policies":              "Policies",
+	"proxies":               "ProxyEndpoints",
+	"targets":               "TargetEndpoints",
+	"integration-endpoints": "IntegrationEndpoints",
+	"sharedflows":           "SharedFlows",
+	"resources":             "Resources",
+}
+
+// manifest elements that are derived from the rest of the bundle, or change on every import
+var ignoredManifestElements = []string{
+	"CreatedAt", "LastModifiedAt", "ConfigurationVersion", "Spec", "BasePaths", "Basepaths",
+	"Policies", "Resources", "ProxyEndpoints", "TargetEndpoints", "IntegrationEndpoints", "SharedFlows", "subType",
+}
+
+type bundleEntry struct {
+	path    string
+	root    *etree.Element
+	content []byte
+}
+
+// Compare returns the semantic differences between the old and new models
+func Compare(oldModel v1.Model, newModel v1.Model) ([]Change, error) {
+	oldEntries, err := bundleEntries(oldModel)
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := bundleEntries(newModel)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	var keys []string
+	for key := range oldEntries {
+		keys = append(keys, key)
+	}
+	for key := range newEntries {
+		if _, ok := oldEntries[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, compareEntryKeys)
+
+	for _, key := range keys {
+		oldEntry, inOld := oldEntries[key]
+		newEntry, inNew := newEntries[key]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Path: oldEntry.path})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Path: newEntry.path})
+		case oldEntry.root == nil || newEntry.root == nil:
+			if !bytes.Equal(oldEntry.content, newEntry.content) {
+				changes = append(changes, Change{Kind: Changed, Path: newEntry.path, Old: contentHash(oldEntry.content), New: contentHash(newEntry.content)})
+			}
+		default:
+			changes = append(changes, compareElements(newEntry.path, oldEntry.root, newEntry.root)...)
+		}
+	}
+
+	return changes, nil
+}
+
+// compareEntryKeys keeps the manifest first, and the groups in bundle order
+func compareEntryKeys(a string, b string) int {
+	order := []string{"", "Policies", "ProxyEndpoints", "TargetEndpoints", "IntegrationEndpoints", "SharedFlows", "Resources"}
+	aGroup, _, _ := strings.Cut(a, ".")
+	bGroup, _, _ := strings.Cut(b, ".")
+	if a == "manifest" {
+		aGroup = ""
+	}
+	if b == "manifest" {
+		bGroup = ""
+	}
+	if aIndex, bIndex := slices.Index(order, aGroup), slices.Index(order, bGroup); aIndex != bIndex {
+		return aIndex - bIndex
+	}
+	return strings.Compare(a, b)
+}
+
+func contentHash(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))[:19]
+}
+
+func bundleEntries(model v1.Model) (map[string]*bundleEntry, error) {
+	entries := map[string]*bundleEntry{}
+	for _, bundleFile := range model.BundleFiles() {
+		content, err := bundleFile.FileContents()
+		if err != nil {
+			return nil, err
+		}
+
+		filePath := filepath.ToSlash(bundleFile.FilePath())
+		dir, _, found := strings.Cut(filePath, "/")
+		if !found {
+			dir = ""
+		}
+
+		if dir == "resources" {
+			resource := bundleFile.(*v1.Resource)
+			key := fmt.Sprintf("Resources.%s://%s", resource.Type, resource.FileName())
+			entries[key] = &bundleEntry{path: key, content: content}
+			continue
+		}
+
+		doc := etree.NewDocument()
+		if err = doc.ReadFromBytes(content); err != nil {
+			return nil, errors.Errorf("could not parse %s. %s", filePath, err.Error())
+		}
+		root := doc.Root()
+		if root == nil {
+			continue
+		}
+
+		if dir == "" {
+			for _, ignored := range ignoredManifestElements {
+				for _, child := range root.SelectElements(ignored) {
+					root.RemoveChild(child)
+				}
+			}
+			entries["manifest"] = &bundleEntry{path: root.Tag, root: root}
+			continue
+		}
+
+		key := fmt.Sprintf("%s.%s", groups[dir], elementKey(root))
+		entries[key] = &bundleEntry{path: key, root: root}
+	}
+	return entries, nil
+}
+
+// elementKey identifies an element among its siblings, e.g. "Flow(name: get-pets)" or "Step(name: SA-Default)"
+func elementKey(element *etree.Element) string {
+	if name := element.SelectAttrValue("name", ""); name != "" {
+		return fmt.Sprintf("%s(name: %s)", element.Tag, name)
+	}
+	if element.Tag == "Step" {
+		if name := element.SelectElement("Name"); name != nil {
+			return fmt.Sprintf("%s(name: %s)", element.Tag, normalizeText(name.Text()))
+		}
+	}
+	return element.Tag
+}
+
+func childKeys(element *etree.Element) ([]string, map[string]*etree.Element) {
+	var keys []string
+	byKey := map[string]*etree.Element{}
+	counts := map[string]int{}
+	children := element.ChildElements()
+	for _, child := range children {
+		counts[elementKey(child)]++
+	}
+
+	seen := map[string]int{}
+	for _, child := range children {
+		key := elementKey(child)
+		if counts[key] > 1 {
+			key = fmt.Sprintf("%s[%d]", key, seen[key])
+			seen[elementKey(child)]++
+		}
+		keys = append(keys, key)
+		byKey[key] = child
+	}
+	return keys, byKey
+}
+
+// normalizeText ignores insignificant whitespace around text
+func normalizeText(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+func elementText(element *etree.Element) string {
+	var text strings.Builder
+	for _, token := range element.Child {
+		if charData, ok := token.(*etree.CharData); ok {
+			text.WriteString(charData.Data)
+		}
+	}
+	return normalizeText(text.String())
+}
+
+func compareElements(path string, oldElement *etree.Element, newElement *etree.Element) []Change {
+	var changes []Change
+
+	//attributes are compared regardless of their order
+	oldAttrs := map[string]string{}
+	for _, attr := range oldElement.Attr {
+		oldAttrs[attr.FullKey()] = attr.Value
+	}
+	newAttrs := map[string]string{}
+	var attrKeys []string
+	for _, attr := range newElement.Attr {
+		newAttrs[attr.FullKey()] = attr.Value
+		attrKeys = append(attrKeys, attr.FullKey())
+	}
+	for key := range oldAttrs {
+		if _, ok := newAttrs[key]; !ok {
+			attrKeys = append(attrKeys, key)
+		}
+	}
+	slices.Sort(attrKeys)
+
+	for _, key := range attrKeys {
+		oldValue, inOld := oldAttrs[key]
+		newValue, inNew := newAttrs[key]
+		attrPath := fmt.Sprintf("%s.@%s", path, key)
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Path: attrPath, Old: oldValue})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Path: attrPath, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{Kind: Changed, Path: attrPath, Old: oldValue, New: newValue})
+		}
+	}
+
+	if oldText, newText := elementText(oldElement), elementText(newElement); oldText != newText && !sameCondition(newElement.Tag, oldText, newText) {
+		changes = append(changes, Change{Kind: Changed, Path: path, Old: oldText, New: newText})
+	}
+
+	oldKeys, oldChildren := childKeys(oldElement)
+	newKeys, newChildren := childKeys(newElement)
+
+	for _, key := range oldKeys {
+		if _, ok := newChildren[key]; !ok {
+			changes = append(changes, Change{Kind: Removed, Path: fmt.Sprintf("%s.%s", path, key), Old: leafText(oldChildren[key])})
+		}
+	}
+
+	var oldCommon []string
+	for _, key := range oldKeys {
+		if _, ok := newChildren[key]; ok {
+			oldCommon = append(oldCommon, key)
+		}
+	}
+	var newCommon []string
+	for _, key := range newKeys {
+		if _, ok := oldChildren[key]; ok {
+			newCommon = append(newCommon, key)
+		}
+	}
+	inOrder := longestCommonSubsequence(oldCommon, newCommon)
+
+	for _, key := range newKeys {
+		childPath := fmt.Sprintf("%s.%s", path, key)
+		oldChild, ok := oldChildren[key]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Path: childPath, New: leafText(newChildren[key])})
+			continue
+		}
+		if !inOrder[key] {
+			changes = append(changes, Change{
+				Kind: Moved,
+				Path: childPath,
+				Old:  fmt.Sprintf("%d", slices.Index(oldKeys, key)),
+				New:  fmt.Sprintf("%d", slices.Index(newKeys, key)),
+			})
+		}
+		changes = append(changes, compareElements(childPath, oldChild, newChildren[key])...)
+	}
+
+	return changes
+}
+
+// sameCondition ignores formatting differences in conditions, such as "a=1" vs "a = 1"
+func sameCondition(tag string, oldText string, newText string) bool {
+	if tag != "Condition" {
+		return false
+	}
+	oldCondition, err := condition.Parse(oldText)
+	if err != nil {
+		return false
+	}
+	newCondition, err := condition.Parse(newText)
+	if err != nil {
+		return false
+	}
+	return oldCondition.String() == newCondition.String()
+}
+
+// leafText returns the text of elements without children, for showing added or removed values
+func leafText(element *etree.Element) string {
+	if len(element.ChildElements()) > 0 {
+		return ""
+	}
+	return elementText(element)
+}
+
+// longestCommonSubsequence returns the keys that kept their relative order
+func longestCommonSubsequence(a []string, b []string) map[string]bool {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	result := map[string]bool{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			result[a[i]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return result
+}
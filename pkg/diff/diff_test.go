@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldFile string
+		newFile string
+		want    []string
+	}{
+		{
+			"petstore",
+			"old/apiproxy.yaml",
+			"new/apiproxy.yaml",
+			[]string{
+				`~ Policies.SpikeArrest(name: SA-Default).Rate: "30ps" -> "60ps"`,
+				`+ ProxyEndpoints.ProxyEndpoint(name: default).PreFlow(name: PreFlow).Request.Step(name: JS-Hello)`,
+				`> ProxyEndpoints.ProxyEndpoint(name: default).Flows.Flow(name: get-pets): moved from position 0 to 1`,
+				`~ ProxyEndpoints.ProxyEndpoint(name: default).Flows.Flow(name: get-pets).Condition: "(proxy.pathsuffix MatchesPath \"/pets\") and (request.verb = \"GET\")" -> "(proxy.pathsuffix MatchesPath \"/pets/*\") and (request.verb = \"GET\")"`,
+				`~ TargetEndpoints.TargetEndpoint(name: default).HTTPTargetConnection.URL: "https://petstore.example.com/v1" -> "https://petstore.example.com/v2"`,
+				`~ Resources.jsc://hello.js: "sha256:30c9840b4e6d" -> "sha256:34b554a10a4d"`,
+			},
+		},
+		{
+			"petstore",
+			"old/apiproxy.yaml",
+			"old/apiproxy.yaml",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		ttDir := filepath.Join("testdata", tt.name)
+		t.Run(tt.name, func(t *testing.T) {
+			oldModel, err := bundle.LoadModel(filepath.Join(ttDir, tt.oldFile))
+			require.NoError(t, err)
+
+			newModel, err := bundle.LoadModel(filepath.Join(ttDir, tt.newFile))
+			require.NoError(t, err)
+
+			changes, err := Compare(oldModel, newModel)
+			require.NoError(t, err)
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-errors/errors"
+)
+
+func Text(changes []Change) []byte {
+	var buffer bytes.Buffer
+	for _, change := range changes {
+		buffer.WriteString(change.String())
+		buffer.WriteString("\n")
+	}
+	_, _ = fmt.Fprintf(&buffer, "%d change(s)\n", len(changes))
+	return buffer.Bytes()
+}
+
+func JSON(changes []Change) ([]byte, error) {
+	if changes == nil {
+		changes = []Change{}
+	}
+	text, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return append(text, '\n'), nil
+}
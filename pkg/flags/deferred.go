@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/spf13/pflag"
+)
+
+// Deferred sets the values of flags after all the flags are parsed, in the order they were given.
+// This way, flags that change how values are read (e.g. --offline and --profile) can come after
+// the flags that read values (e.g. --values and --set-oas).
+type Deferred struct {
+	entries []deferredEntry
+}
+
+type deferredEntry struct {
+	value *deferredValue
+	input string
+}
+
+// Value wraps the flag value, so that it is only set when calling Apply
+func (d *Deferred) Value(value pflag.Value) pflag.Value {
+	return &deferredValue{Value: value, deferred: d}
+}
+
+// Apply sets the values of the deferred flags in the order they were given.
+// The flagSet is used for naming flags within errors.
+func (d *Deferred) Apply(flagSet *pflag.FlagSet) error {
+	entries := d.entries
+	d.entries = nil
+	for _, entry := range entries {
+		if err := entry.value.Value.Set(entry.input); err != nil {
+			return errors.Errorf(`invalid argument "%s" for "--%s" flag: %s`, entry.input, flagName(flagSet, entry.value), err.Error())
+		}
+	}
+	return nil
+}
+
+func flagName(flagSet *pflag.FlagSet, value pflag.Value) string {
+	name := "?"
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if flag.Value == value {
+			name = flag.Name
+		}
+	})
+	return name
+}
+
+type deferredValue struct {
+	pflag.Value
+	deferred *Deferred
+}
+
+func (v *deferredValue) Set(input string) error {
+	v.deferred.entries = append(v.deferred.entries, deferredEntry{value: v, input: input})
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/values"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDeferred_Apply(t *testing.T) {
+	data := values.Map{}
+	profile := NewProfile("")
+	setValue := NewSetAny(&data)
+	setValueFile := NewProfileValues(&data, &profile)
+
+	var deferred Deferred
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.Var(&profile, "profile", "")
+	flagSet.Var(deferred.Value(&setValue), "set", "")
+	flagSet.Var(deferred.Value(&setValueFile), "values", "")
+
+	// --profile comes after --values, and --set overrides values in the order given
+	err := flagSet.Parse([]string{"--set", "name=first", "--values", "testdata/values/values.yaml", "--set", "target.timeout=60", "--profile", "prod"})
+	require.NoError(t, err)
+	require.Empty(t, data)
+
+	require.NoError(t, deferred.Apply(flagSet))
+	require.Equal(t, values.Map{
+		"name": "orders",
+		"target": values.Map{
+			"url":     "https://prod.example.com",
+			"timeout": int64(60),
+		},
+	}, data)
+
+	// errors name the flag, as pflag does
+	require.NoError(t, flagSet.Parse([]string{"--values", "testdata/values/missing.yaml"}))
+	require.ErrorContains(t, deferred.Apply(flagSet), `invalid argument "testdata/values/missing.yaml" for "--values" flag`)
+}
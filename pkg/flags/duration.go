@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/go-errors/errors"
+	"time"
+)
+
+type Duration time.Duration
+
+func NewDuration(value time.Duration) Duration {
+	return Duration(value)
+}
+
+func (d *Duration) Type() string {
+	return "duration"
+}
+
+func (d *Duration) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *Duration) Set(input string) error {
+	value, err := time.ParseDuration(input)
+	if err != nil {
+		return errors.Errorf("invalid duration %q (e.g. \"30s\" or \"1m\")", input)
+	}
+	*d = Duration(value)
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"strconv"
+)
+
+type Int int
+
+func NewInt(value int) Int {
+	return Int(value)
+}
+
+func (i *Int) Type() string {
+	return "int"
+}
+
+func (i *Int) String() string {
+	return fmt.Sprintf("%d", *i)
+}
+
+func (i *Int) Set(input string) error {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return errors.Errorf("invalid integer %q", input)
+	}
+	*i = Int(value)
+	return nil
+}
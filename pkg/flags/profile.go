@@ -0,0 +1,53 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/go-errors/errors"
+	"path/filepath"
+	"strings"
+)
+
+// Profile selects per-environment overlays for values files (e.g. "values.prod.yaml" for "values.yaml" with "--profile prod").
+// The profile must be set before the values files, commands use Deferred so that --profile can come in any order.
+type Profile struct {
+	Name string
+}
+
+func NewProfile(name string) Profile {
+	return Profile{Name: name}
+}
+
+func (p *Profile) Type() string {
+	return "string"
+}
+
+func (p *Profile) String() string {
+	return p.Name
+}
+
+func (p *Profile) Set(input string) error {
+	if strings.ContainsAny(input, `/\`) {
+		return errors.Errorf("invalid profile name %q", input)
+	}
+	p.Name = input
+	return nil
+}
+
+// OverlayFile returns the path of the overlay for the given values file (e.g. "values.prod.yaml" for "values.yaml")
+func (p *Profile) OverlayFile(filePath string) string {
+	ext := filepath.Ext(filePath)
+	return strings.TrimSuffix(filePath, ext) + "." + p.Name + ext
+}
@@ -15,6 +15,7 @@
 package flags
 
 import (
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/go-errors/errors"
 	"os"
@@ -23,6 +24,9 @@ import (
 
 type SetFile struct {
 	Data *values.Map
+
+	// GitSources collects the files fetched from git repositories, if not nil
+	GitSources *git.Sources
 }
 
 func NewSetFile(data *values.Map) SetFile {
@@ -43,6 +47,11 @@ func (v *SetFile) Set(entry string) error {
 		return errors.Errorf("missing file path in set-file for key=%s", key)
 	}
 
+	filePath, err := git.LocalFile(filePath, v.GitSources)
+	if err != nil {
+		return err
+	}
+
 	fileText, err := os.ReadFile(filePath)
 	if err != nil {
 		return errors.New(err)
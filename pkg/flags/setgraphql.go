@@ -16,6 +16,7 @@ package flags
 
 import (
 	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/parser"
 	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/go-errors/errors"
@@ -24,6 +25,9 @@ import (
 
 type SetGraphQL struct {
 	Data *values.Map
+
+	// GitSources collects the files fetched from git repositories, if not nil
+	GitSources *git.Sources
 }
 
 func NewSetGraphQL(data *values.Map) SetGraphQL {
@@ -44,6 +48,11 @@ func (v *SetGraphQL) Set(entry string) error {
 		return errors.Errorf("missing file path in set-graphql for key=%s", key)
 	}
 
+	filePath, err := git.LocalFile(filePath, v.GitSources)
+	if err != nil {
+		return err
+	}
+
 	parserResult, schemaBytes, err := parser.ParseGraphQLSchema(filePath)
 	if err != nil {
 		return err
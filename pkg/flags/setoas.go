@@ -16,16 +16,21 @@ package flags
 
 import (
 	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/parser"
 	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 type SetOAS struct {
 	Data *values.Map
+
+	// GitSources collects the files fetched from git repositories, if not nil
+	GitSources *git.Sources
 }
 
 func NewSetOAS(data *values.Map) SetOAS {
@@ -46,18 +51,28 @@ func (v *SetOAS) Set(entry string) error {
 		return errors.Errorf("missing file path in set-oas for key=%s", key)
 	}
 
-	specFileMap := make(map[string]any)
+	filePath, err := git.LocalFile(filePath, v.GitSources)
+	if err != nil {
+		return err
+	}
+
 	specFileText, err := os.ReadFile(filePath)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	err = yaml.Unmarshal(specFileText, specFileMap)
+	return v.SetText(key, filePath, specFileText)
+}
+
+// SetText is like Set, for a spec that was already read (e.g. from an fs.FS)
+func (v *SetOAS) SetText(key string, filePath string, specFileText []byte) error {
+	specFileMap := make(map[string]any)
+	err := yaml.Unmarshal(specFileText, specFileMap)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	oas, err := parser.ParseOAS(filePath)
+	oas, err := parser.ParseOASText(specFileText, filepath.Dir(filePath))
 	if err != nil {
 		return errors.New(err)
 	}
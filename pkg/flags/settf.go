@@ -16,6 +16,7 @@ package flags
 
 import (
 	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/go-errors/errors"
@@ -25,6 +26,9 @@ import (
 
 type SetTF struct {
 	Data *values.Map
+
+	// GitSources collects the files fetched from git repositories, if not nil
+	GitSources *git.Sources
 }
 
 func NewSetTF(data *values.Map) SetTF {
@@ -45,6 +49,11 @@ func (v *SetTF) Set(entry string) error {
 		return errors.Errorf("missing file path in set-tf for key=%s", key)
 	}
 
+	filePath, err := git.LocalFile(filePath, v.GitSources)
+	if err != nil {
+		return err
+	}
+
 	tfFileText, err := os.ReadFile(filePath)
 	if err != nil {
 		return errors.New(err)
@@ -0,0 +1,35 @@
+//  Copyright 2026 Google LLC
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package flags
+
+// StringList is a flag that can be repeated, e.g. --header "Accept: application/json" --header "X-Foo: bar"
+type StringList []string
+
+func NewStringList(list []string) StringList {
+	return list
+}
+
+func (l *StringList) Type() string {
+	return "string"
+}
+
+func (l *StringList) String() string {
+	return ""
+}
+
+func (l *StringList) Set(input string) error {
+	*l = append(*l, input)
+	return nil
+}
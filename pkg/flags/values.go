@@ -15,18 +15,29 @@
 package flags
 
 import (
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
 	"os"
 )
 
+// Values deep merges values files into the map, along with the overlay for the profile (if any)
 type Values struct {
-	Data *values.Map
+	Data    *values.Map
+	Sources values.Sources
+	Profile *Profile
+
+	// GitSources collects the files fetched from git repositories, if not nil
+	GitSources *git.Sources
 }
 
 func NewValues(data *values.Map) Values {
-	return Values{Data: data}
+	return Values{Data: data, Sources: values.Sources{}}
+}
+
+func NewProfileValues(data *values.Map, profile *Profile) Values {
+	return Values{Data: data, Sources: values.Sources{}, Profile: profile}
 }
 
 func (v *Values) Type() string {
@@ -37,16 +48,44 @@ func (v *Values) String() string {
 	return ""
 }
 
-func (v *Values) Set(filePath string) error {
+func (v *Values) Set(input string) error {
+	filePath, err := git.LocalFile(input, v.GitSources)
+	if err != nil {
+		return err
+	}
+
+	if err = v.merge(filePath, input); err != nil {
+		return err
+	}
+
+	if v.Profile == nil {
+		return nil
+	}
+
+	if v.Profile.Name == "" {
+		return nil
+	}
+
+	overlayFile := v.Profile.OverlayFile(filePath)
+	if _, err = os.Stat(overlayFile); os.IsNotExist(err) {
+		return nil
+	}
+
+	return v.merge(overlayFile, v.Profile.OverlayFile(input))
+}
+
+// merge deep merges the values file, the source is the name values are attributed to (e.g. the git URI of the file)
+func (v *Values) merge(filePath string, source string) error {
 	yamlText, err := os.ReadFile(filePath)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	err = yaml.Unmarshal(yamlText, v.Data)
+	node := &yaml.Node{}
+	err = yaml.Unmarshal(yamlText, node)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	return nil
+	return v.Data.Merge(node, source, v.Sources)
 }
@@ -82,3 +82,60 @@ target_info:
 		})
 	}
 }
+
+func TestValues_Profile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    any
+	}{
+		{
+			"no profile",
+			"",
+			values.Map{
+				"name": "orders",
+				"target": values.Map{
+					"url":     "https://dev.example.com",
+					"timeout": 30,
+				},
+			},
+		},
+		{
+			"with overlay",
+			"prod",
+			values.Map{
+				"name": "orders",
+				"target": values.Map{
+					"url":     "https://prod.example.com",
+					"timeout": 30,
+				},
+			},
+		},
+		{
+			"without overlay",
+			"test",
+			values.Map{
+				"name": "orders",
+				"target": values.Map{
+					"url":     "https://dev.example.com",
+					"timeout": 30,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := values.Map{}
+			profile := NewProfile("")
+			v := NewProfileValues(&data, &profile)
+
+			err := profile.Set(tt.profile)
+			require.NoError(t, err)
+
+			err = v.Set("testdata/values/values.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, data)
+		})
+	}
+}
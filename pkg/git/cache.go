@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"github.com/go-git/go-git/v5/plumbing"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheDirEnv overrides the directory where fetched templates are cached (default is "apigee-go-gen/git" within the user cache directory)
+const CacheDirEnv = "APIGEE_GO_GEN_CACHE_DIR"
+
+// cache holds the commit each ref resolved to (in "refs"), and the contents of each fetched directory (in "trees").
+// Entries are keyed by a hash of the repository URL and ref, or of the repository URL, commit SHA, and directory.
+type cache struct {
+	dir string
+}
+
+func openCache() (*cache, error) {
+	dir := os.Getenv(CacheDirEnv)
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, errors.Errorf("git: could not find cache directory, set %s. %s", CacheDirEnv, err)
+		}
+		dir = filepath.Join(userCacheDir, "apigee-go-gen", "git")
+	}
+
+	for _, subDir := range []string{"refs", "trees", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, subDir), os.ModePerm); err != nil {
+			return nil, errors.New(err)
+		}
+	}
+	return &cache{dir: dir}, nil
+}
+
+func (c *cache) treeDir(repoURL string, commit string, dir string) string {
+	return filepath.Join(c.dir, "trees", cacheKey(repoURL, commit, dir))
+}
+
+// ref returns the cached commit SHA, and full ref name, the ref last resolved to
+func (c *cache) ref(repoURL string, ref string) (string, plumbing.ReferenceName, bool) {
+	text, err := os.ReadFile(filepath.Join(c.dir, "refs", cacheKey(repoURL, ref)))
+	if err != nil {
+		return "", "", false
+	}
+	commit, refName, _ := strings.Cut(strings.TrimSpace(string(text)), " ")
+	return commit, plumbing.ReferenceName(refName), true
+}
+
+func (c *cache) setRef(repoURL string, ref string, commit string, refName plumbing.ReferenceName) error {
+	refFile := filepath.Join(c.dir, "refs", cacheKey(repoURL, ref))
+	return c.store(refFile, func(tmpFile string) error {
+		return os.WriteFile(tmpFile, []byte(fmt.Sprintf("%s %s\n", commit, refName)), 0644)
+	})
+}
+
+// store writes a cache entry into a temporary location first, and then moves it into place.
+// This way, concurrent fetches never see partially written entries.
+func (c *cache) store(entry string, write func(tmpEntry string) error) error {
+	tmpDir, err := os.MkdirTemp(filepath.Join(c.dir, "tmp"), "entry-*")
+	if err != nil {
+		return errors.New(err)
+	}
+	defer utils.LenientRemoveAll(tmpDir)
+
+	tmpEntry := filepath.Join(tmpDir, "entry")
+	if err = write(tmpEntry); err != nil {
+		return err
+	}
+
+	// a tree without any files is not written at all
+	if _, err = os.Stat(tmpEntry); err != nil {
+		if err = os.MkdirAll(tmpEntry, os.ModePerm); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	if info, err := os.Stat(entry); err == nil && info.IsDir() {
+		// another fetch stored the same directory already
+		return nil
+	}
+	if err = os.Rename(tmpEntry, entry); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+func cacheKey(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,475 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OfflineMode is the type of the --offline flag
+type OfflineMode bool
+
+// Offline makes fetching use only the cache, without accessing any repository.
+// It must be set before fetching, commands use flags.Deferred so that --offline can come after flags that read from git repositories.
+var Offline OfflineMode
+
+func (o *OfflineMode) Type() string {
+	return "boolean"
+}
+
+func (o *OfflineMode) String() string {
+	return ""
+}
+
+func (o *OfflineMode) Set(input string) error {
+	value, err := strconv.ParseBool(input)
+	if err != nil {
+		return errors.New(err)
+	}
+	*o = OfflineMode(value)
+	return nil
+}
+
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// Checkout is a directory fetched from a git repository, copied into a temporary directory
+type Checkout struct {
+	Repo string
+	Ref  string
+
+	// Commit is the SHA the ref resolved to
+	Commit string
+
+	// Dir is the temporary directory, the caller is responsible for removing it
+	Dir string
+
+	// File is the local path of the fetched file, within Dir
+	File string
+}
+
+// Fetch fetches the directory that contains the file referenced by a git-style URI (see ParseURI).
+// The ref is resolved to a commit, and the directory contents are cached by repository, commit and directory.
+// In offline mode, both the ref and the directory contents must already be in the cache.
+func Fetch(uri string) (*Checkout, error) {
+	tree, err := fetchCached(uri, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy out of the cache, so that the caller can modify and remove it
+	checkoutDir, err := os.MkdirTemp("", "apigee-go-gen-")
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	if err = utils.CopyDir(filepath.Join(checkoutDir, filepath.FromSlash(tree.path)), tree.dir); err != nil {
+		utils.LenientRemoveAll(checkoutDir)
+		return nil, errors.New(err)
+	}
+
+	return &Checkout{
+		Repo:   tree.repoURL,
+		Ref:    tree.ref,
+		Commit: tree.commit,
+		Dir:    checkoutDir,
+		File:   filepath.Join(checkoutDir, filepath.FromSlash(tree.resource)),
+	}, nil
+}
+
+// LocalFile returns the local path of the file referenced by a git-style URI, or the value as is if it is not a git URI.
+//
+// Unlike Fetch, the whole repository is fetched, so that relative references within the file
+// (e.g. "$ref: ../common/schemas.yaml") resolve within the same repository.
+// The returned path points into the cache, and must not be modified.
+// The commit the ref resolved to is added to sources (if not nil).
+func LocalFile(uri string, sources *Sources) (string, error) {
+	if !IsGitURI(uri) {
+		return uri, nil
+	}
+
+	tree, err := fetchCached(uri, true)
+	if err != nil {
+		return "", err
+	}
+	sources.Add(uri, tree.ref, tree.commit)
+	return filepath.Join(tree.dir, filepath.FromSlash(tree.resource)), nil
+}
+
+// cachedTree is a directory of a repository at a commit, within the cache
+type cachedTree struct {
+	repoURL string
+	ref     string
+	commit  string
+
+	// dir is the location of the directory within the cache
+	dir string
+
+	// path is the location of the directory within the repository ("." for the whole repository)
+	path string
+
+	// resource is the location of the file within the repository
+	resource string
+}
+
+// fetchCached stores the directory of the file referenced by the URI (or the whole repository) in the cache, unless already there
+func fetchCached(uri string, wholeRepo bool) (*cachedTree, error) {
+	repoURL, ref, resource, err := ParseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	resource = path.Clean(strings.TrimPrefix(resource, "/"))
+	if !filepath.IsLocal(resource) {
+		return nil, errors.Errorf("git: resource path %s must be within the repository", resource)
+	}
+	treePath := path.Dir(resource)
+	if wholeRepo {
+		treePath = "."
+	}
+
+	cache, err := openCache()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, refName, err := resolve(cache, repoURL, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	treeDir := cache.treeDir(repoURL, commit, treePath)
+	if _, err = os.Stat(treeDir); err != nil {
+		if bool(Offline) {
+			return nil, errors.Errorf("git: %s at commit %s is not in the cache (offline)", repoURL, commit)
+		}
+		if commit, treeDir, err = fetchTree(cache, repoURL, refName, commit, treePath); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cachedTree{
+		repoURL:  repoURL,
+		ref:      ref,
+		commit:   commit,
+		dir:      treeDir,
+		path:     treePath,
+		resource: resource,
+	}, nil
+}
+
+// Source is a git URI that was fetched, along with the commit its ref resolved to
+type Source struct {
+	URI    string
+	Ref    string
+	Commit string
+}
+
+// Sources collects the git URIs fetched for a single render, so that the commits can be recorded in the output.
+// A nil *Sources does not collect anything.
+type Sources struct {
+	mutex   sync.Mutex
+	sources map[string]Source
+}
+
+// Add records the commit the ref resolved to, with the password (if any) redacted from the URI
+func (s *Sources) Add(uri string, ref string, commit string) {
+	if s == nil {
+		return
+	}
+	uri = redactURI(uri)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sources == nil {
+		s.sources = map[string]Source{}
+	}
+	s.sources[uri] = Source{URI: uri, Ref: ref, Commit: commit}
+}
+
+// List returns the git URIs collected so far, sorted
+func (s *Sources) List() []Source {
+	result := []Source{}
+	if s == nil {
+		return result
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, uri := range slices.Sorted(maps.Keys(s.sources)) {
+		result = append(result, s.sources[uri])
+	}
+	return result
+}
+
+// Fprint prints the commit each branch or tag resolved to, so that the exact version can be pinned later
+func (s *Sources) Fprint(w io.Writer) {
+	for _, source := range s.List() {
+		if source.Ref == source.Commit {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "Fetched %s (%s is commit %s)\n", source.URI, source.Ref, source.Commit)
+	}
+}
+
+// redactURI hides the password (e.g. an access token) within the URI, if any
+func redactURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.User == nil {
+		return uri
+	}
+	if _, hasPassword := parsed.User.Password(); !hasPassword {
+		return uri
+	}
+	return parsed.Redacted()
+}
+
+// resolve returns the commit SHA for the ref (branch, tag, or commit SHA), along with the full name of the ref if it is a branch or tag
+func resolve(cache *cache, repoURL string, ref string) (string, plumbing.ReferenceName, error) {
+	if bool(Offline) || plumbing.IsHash(ref) {
+		if commit, refName, found := cache.ref(repoURL, ref); found {
+			return commit, refName, nil
+		}
+		if plumbing.IsHash(ref) {
+			return ref, "", nil
+		}
+		return "", "", errors.Errorf("git: ref '%s' of %s is not in the cache (offline)", ref, repoURL)
+	}
+
+	refs, err := listRefs(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	candidates := []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+		plumbing.ReferenceName(ref),
+	}
+	for _, candidate := range candidates {
+		// annotated tags are listed twice, the peeled ref ("^{}") points to the commit
+		hash, found := refs[candidate.String()+"^{}"]
+		if !found {
+			hash, found = refs[candidate.String()]
+		}
+		if found {
+			commit := hash.String()
+			if err = cache.setRef(repoURL, ref, commit, candidate); err != nil {
+				return "", "", err
+			}
+			return commit, candidate, nil
+		}
+	}
+
+	if shaPattern.MatchString(ref) {
+		// an abbreviated commit SHA, resolved after fetching
+		return ref, "", nil
+	}
+
+	return "", "", errors.Errorf("git: could not find ref '%s' in %s", ref, repoURL)
+}
+
+func listRefs(repoURL string) (map[string]plumbing.Hash, error) {
+	refs := map[string]plumbing.Hash{}
+
+	if localPath, isLocal := localRepoPath(repoURL); isLocal {
+		repo, err := gogit.PlainOpen(localPath)
+		if err != nil {
+			return nil, errors.Errorf("git: could not open %s. %s", repoURL, err)
+		}
+		iter, err := repo.References()
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		err = iter.ForEach(func(reference *plumbing.Reference) error {
+			if reference.Type() != plumbing.HashReference {
+				return nil
+			}
+			refs[reference.Name().String()] = reference.Hash()
+			if tag, err := repo.TagObject(reference.Hash()); err == nil {
+				refs[reference.Name().String()+"^{}"] = tag.Target
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		return refs, nil
+	}
+
+	remote := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{repoURL}})
+	references, err := remote.List(&gogit.ListOptions{PeelingOption: gogit.AppendPeeled})
+	if err != nil {
+		return nil, errors.Errorf("git: could not list refs of %s. %s. Use --offline to use cached templates", repoURL, err)
+	}
+	for _, reference := range references {
+		refs[reference.Name().String()] = reference.Hash()
+	}
+	return refs, nil
+}
+
+// fetchTree stores the files of the directory at the commit into the cache.
+// It returns the full commit SHA (the commit may be abbreviated), and the directory within the cache.
+func fetchTree(cache *cache, repoURL string, refName plumbing.ReferenceName, commit string, resourceDir string) (string, string, error) {
+	repo, err := openCommit(repoURL, refName, commit)
+	if err != nil {
+		return "", "", err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return "", "", errors.Errorf("git: could not find commit %s in %s. %s", commit, repoURL, err)
+	}
+	if hash.String() != commit {
+		// record the full SHA for offline use
+		if err = cache.setRef(repoURL, commit, hash.String(), ""); err != nil {
+			return "", "", err
+		}
+	}
+
+	commitObject, err := repo.CommitObject(*hash)
+	if err != nil {
+		return "", "", errors.Errorf("git: could not read commit %s in %s. %s", commit, repoURL, err)
+	}
+	tree, err := commitObject.Tree()
+	if err != nil {
+		return "", "", errors.New(err)
+	}
+	if resourceDir != "." {
+		if tree, err = tree.Tree(resourceDir); err != nil {
+			return "", "", errors.Errorf("git: directory %s not found at commit %s of %s", resourceDir, hash, repoURL)
+		}
+	}
+
+	treeDir := cache.treeDir(repoURL, hash.String(), resourceDir)
+	err = cache.store(treeDir, func(dir string) error {
+		return writeTree(tree, dir)
+	})
+	return hash.String(), treeDir, err
+}
+
+// openCommit opens a repository that contains the commit, fetching it into memory for remote repositories
+func openCommit(repoURL string, refName plumbing.ReferenceName, commit string) (*gogit.Repository, error) {
+	if localPath, isLocal := localRepoPath(repoURL); isLocal {
+		repo, err := gogit.PlainOpen(localPath)
+		if err != nil {
+			return nil, errors.Errorf("git: could not open %s. %s", repoURL, err)
+		}
+		return repo, nil
+	}
+
+	repo, err := gogit.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{repoURL}})
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	// fetch only the commit when possible, or else everything (e.g. for abbreviated SHAs)
+	options := &gogit.FetchOptions{Depth: 1, Tags: gogit.NoTags}
+	if refName != "" {
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", refName, refName))}
+	} else if plumbing.IsHash(commit) {
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:refs/heads/fetched", commit))}
+	} else {
+		options = &gogit.FetchOptions{
+			RefSpecs: []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+		}
+	}
+
+	if err = remote.Fetch(options); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil, errors.Errorf("git: could not fetch commit %s from %s. %s", commit, repoURL, err)
+	}
+	return repo, nil
+}
+
+func writeTree(tree *object.Tree, dir string) error {
+	return tree.Files().ForEach(func(file *object.File) error {
+		filePath := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			return errors.New(err)
+		}
+
+		switch file.Mode {
+		case filemode.Symlink:
+			target, err := file.Contents()
+			if err != nil {
+				return errors.New(err)
+			}
+			// only relative links that stay within the fetched directory are kept
+			// (joining an absolute target would make it look relative, so those are checked first)
+			if path.IsAbs(target) || filepath.IsAbs(target) || !filepath.IsLocal(filepath.Join(path.Dir(file.Name), target)) {
+				return nil
+			}
+			if err = os.Symlink(target, filePath); err != nil {
+				return errors.New(err)
+			}
+			return nil
+		case filemode.Regular, filemode.Deprecated, filemode.Executable:
+		default:
+			// submodules
+			return nil
+		}
+
+		perm := os.FileMode(0644)
+		if file.Mode == filemode.Executable {
+			perm = 0755
+		}
+
+		reader, err := file.Reader()
+		if err != nil {
+			return errors.New(err)
+		}
+		defer reader.Close()
+
+		writer, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+		if err != nil {
+			return errors.New(err)
+		}
+		if _, err = io.Copy(writer, reader); err != nil {
+			_ = writer.Close()
+			return errors.New(err)
+		}
+		if err = writer.Close(); err != nil {
+			return errors.New(err)
+		}
+		return nil
+	})
+}
+
+// localRepoPath returns the path of the repository for file:// URLs
+func localRepoPath(repoURL string) (string, bool) {
+	if !strings.HasPrefix(repoURL, "file://") {
+		return "", false
+	}
+	return filepath.FromSlash(strings.TrimPrefix(repoURL, "file://")), true
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git_test
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	repo := newBareRepo(t)
+	first := repo.commit(t, map[string]string{
+		"templates/hello/apiproxy.yaml": "v1",
+		"templates/hello/_helpers.tmpl": "helpers",
+		"templates/other/apiproxy.yaml": "other",
+	})
+	repo.tag(t, "v1.0.0", first)
+	second := repo.commit(t, map[string]string{"templates/hello/apiproxy.yaml": "v2"})
+
+	tests := []struct {
+		name       string
+		ref        string
+		file       string
+		wantCommit string
+		wantText   string
+		wantErr    string
+	}{
+		{"branch", "main", "templates/hello/apiproxy.yaml", second, "v2", ""},
+		{"tag", "v1.0.0", "templates/hello/apiproxy.yaml", first, "v1", ""},
+		{"commit", first, "templates/hello/apiproxy.yaml", first, "v1", ""},
+		{"abbreviated commit", first[:8], "templates/hello/apiproxy.yaml", first, "v1", ""},
+		{"unknown ref", "nope", "templates/hello/apiproxy.yaml", "", "", "could not find ref 'nope'"},
+		{"unknown directory", "main", "templates/nope/apiproxy.yaml", "", "", "directory templates/nope not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(git.CacheDirEnv, t.TempDir())
+
+			checkout, err := git.Fetch(repo.uri(tt.ref, tt.file))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer os.RemoveAll(checkout.Dir)
+
+			require.Equal(t, tt.wantCommit, checkout.Commit)
+			requireFileText(t, tt.wantText, checkout.File)
+
+			// only the directory of the file is fetched
+			require.FileExists(t, filepath.Join(filepath.Dir(checkout.File), "_helpers.tmpl"))
+			require.NoDirExists(t, filepath.Join(checkout.Dir, "templates", "other"))
+		})
+	}
+}
+
+func TestFetch_Cache(t *testing.T) {
+	t.Setenv(git.CacheDirEnv, t.TempDir())
+	defer func() { git.Offline = false }()
+
+	repo := newBareRepo(t)
+	first := repo.commit(t, map[string]string{"apiproxy.yaml": "v1"})
+
+	checkout, err := git.Fetch(repo.uri("main", "apiproxy.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, first, checkout.Commit)
+
+	// branches are resolved again, unless offline
+	second := repo.commit(t, map[string]string{"apiproxy.yaml": "v2"})
+	checkout, err = git.Fetch(repo.uri("main", "apiproxy.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, second, checkout.Commit)
+	requireFileText(t, "v2", checkout.File)
+
+	// offline, the ref and the contents come from the cache, even if the repository is gone
+	third := repo.commit(t, map[string]string{"apiproxy.yaml": "v3"})
+	require.NoError(t, os.RemoveAll(repo.dir))
+	git.Offline = true
+
+	checkout, err = git.Fetch(repo.uri("main", "apiproxy.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, second, checkout.Commit)
+	requireFileText(t, "v2", checkout.File)
+
+	checkout, err = git.Fetch(repo.uri(first, "apiproxy.yaml"))
+	require.NoError(t, err)
+	requireFileText(t, "v1", checkout.File)
+
+	_, err = git.Fetch(repo.uri(third, "apiproxy.yaml"))
+	require.ErrorContains(t, err, "at commit "+third+" is not in the cache (offline)")
+
+	_, err = git.Fetch(repo.uri("other", "apiproxy.yaml"))
+	require.ErrorContains(t, err, "ref 'other' of "+repo.url()+" is not in the cache (offline)")
+}
+
+func TestFetch_Symlinks(t *testing.T) {
+	t.Setenv(git.CacheDirEnv, t.TempDir())
+
+	repo := newBareRepo(t)
+	repo.symlink(t, "templates/hello/absolute.yaml", "/etc/passwd")
+	repo.symlink(t, "templates/hello/parent.yaml", "../../secrets/values.yaml")
+	repo.symlink(t, "templates/hello/sibling.yaml", "apiproxy.yaml")
+	repo.commit(t, map[string]string{
+		"templates/hello/apiproxy.yaml": "v1",
+		"secrets/values.yaml":           "secret",
+	})
+
+	checkout, err := git.Fetch(repo.uri("main", "templates/hello/apiproxy.yaml"))
+	require.NoError(t, err)
+	defer os.RemoveAll(checkout.Dir)
+
+	// links outside the fetched directory are skipped
+	dir := filepath.Dir(checkout.File)
+	require.NoFileExists(t, filepath.Join(dir, "absolute.yaml"))
+	require.NoFileExists(t, filepath.Join(dir, "parent.yaml"))
+	requireFileText(t, "v1", filepath.Join(dir, "sibling.yaml"))
+}
+
+func TestLocalFile(t *testing.T) {
+	t.Setenv(git.CacheDirEnv, t.TempDir())
+
+	repo := newBareRepo(t)
+	commit := repo.commit(t, map[string]string{
+		"specs/petstore.yaml": "$ref: ../common/schemas.yaml",
+		"common/schemas.yaml": "schemas",
+	})
+
+	var sources git.Sources
+	file, err := git.LocalFile(repo.uri("main", "specs/petstore.yaml"), &sources)
+	require.NoError(t, err)
+	requireFileText(t, "$ref: ../common/schemas.yaml", file)
+
+	// the whole repository is fetched, so that relative references resolve
+	requireFileText(t, "schemas", filepath.Join(filepath.Dir(file), "..", "common", "schemas.yaml"))
+
+	file, err = git.LocalFile(repo.uri(commit, "common/schemas.yaml"), nil)
+	require.NoError(t, err)
+	requireFileText(t, "schemas", file)
+
+	// the commits refs resolved to are recorded, so that they can be written to the output
+	require.Equal(t, []git.Source{{URI: repo.uri("main", "specs/petstore.yaml"), Ref: "main", Commit: commit}}, sources.List())
+
+	// and printed by the commands, so that the exact version can be pinned later
+	var printed strings.Builder
+	sources.Fprint(&printed)
+	require.Equal(t, fmt.Sprintf("Fetched %s (main is commit %s)\n", repo.uri("main", "specs/petstore.yaml"), commit), printed.String())
+
+	// other values are returned as is
+	file, err = git.LocalFile("./specs/petstore.yaml", &sources)
+	require.NoError(t, err)
+	require.Equal(t, "./specs/petstore.yaml", file)
+}
+
+type bareRepo struct {
+	dir  string
+	repo *gogit.Repository
+	when time.Time
+}
+
+// newBareRepo creates a bare repository, with an in-memory worktree for making commits
+func newBareRepo(t *testing.T) *bareRepo {
+	dir := filepath.Join(t.TempDir(), "templates.git")
+	storage := filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault())
+	repo, err := gogit.InitWithOptions(storage, memfs.New(), gogit.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")})
+	require.NoError(t, err)
+	return &bareRepo{dir: dir, repo: repo, when: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (r *bareRepo) commit(t *testing.T, files map[string]string) string {
+	worktree, err := r.repo.Worktree()
+	require.NoError(t, err)
+	for name, text := range files {
+		require.NoError(t, util.WriteFile(worktree.Filesystem, name, []byte(text), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+
+	r.when = r.when.Add(time.Hour)
+	hash, err := worktree.Commit("update", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: r.when},
+	})
+	require.NoError(t, err)
+	return hash.String()
+}
+
+// symlink adds a link to the next commit
+func (r *bareRepo) symlink(t *testing.T, name string, target string) {
+	worktree, err := r.repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Filesystem.MkdirAll(filepath.Dir(name), 0755))
+	require.NoError(t, worktree.Filesystem.Symlink(target, name))
+	_, err = worktree.Add(name)
+	require.NoError(t, err)
+}
+
+func (r *bareRepo) tag(t *testing.T, name string, commit string) {
+	_, err := r.repo.CreateTag(name, plumbing.NewHash(commit), &gogit.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@example.com", When: r.when},
+		Message: name,
+	})
+	require.NoError(t, err)
+}
+
+func (r *bareRepo) url() string {
+	return "file://" + filepath.ToSlash(r.dir)
+}
+
+func (r *bareRepo) uri(ref string, file string) string {
+	return r.url() + "/-/" + ref + "#" + file
+}
+
+func requireFileText(t *testing.T, expected string, file string) {
+	text, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(text))
+}
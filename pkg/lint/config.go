@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// Config enables, disables and changes the severity of rules, e.g.
+//
+//	rules:
+//	  missing-spike-arrest:
+//	    enabled: false
+//	  hard-coded-target-url:
+//	    severity: error
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+type RuleConfig struct {
+	Enabled  *bool  `yaml:"enabled"`
+	Severity string `yaml:"severity"`
+}
+
+func LoadConfig(configFile string) (*Config, error) {
+	text, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	config := &Config{}
+	if err = yaml.Unmarshal(text, config); err != nil {
+		return nil, errors.Errorf("%s: %s", configFile, err.Error())
+	}
+
+	for ruleID, ruleConfig := range config.Rules {
+		if _, ok := registeredRules[ruleID]; !ok {
+			return nil, errors.Errorf(`%s: unknown rule "%s"`, configFile, ruleID)
+		}
+		if ruleConfig.Severity == "" {
+			continue
+		}
+		if _, err = ParseSeverity(ruleConfig.Severity); err != nil {
+			return nil, errors.Errorf(`%s: rule "%s". %s`, configFile, ruleID, err.Error())
+		}
+	}
+
+	return config, nil
+}
+
+// RuleSettings returns whether the rule is enabled, and its severity
+func (c *Config) RuleSettings(rule *Rule) (bool, Severity) {
+	if c == nil {
+		return true, rule.Severity
+	}
+
+	ruleConfig, ok := c.Rules[rule.ID]
+	if !ok {
+		return true, rule.Severity
+	}
+
+	enabled := ruleConfig.Enabled == nil || *ruleConfig.Enabled
+	severity, err := ParseSeverity(ruleConfig.Severity)
+	if err != nil {
+		severity = rule.Severity
+	}
+	return enabled, severity
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"slices"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return severity, nil
+	}
+	return "", errors.Errorf(`severity "%s" is not valid, must be one of %v`, s, []Severity{SeverityError, SeverityWarning, SeverityInfo})
+}
+
+// Finding is a problem reported by a rule
+type Finding struct {
+	RuleID   string              `json:"ruleId"`
+	Severity Severity            `json:"severity"`
+	Message  string              `json:"message"`
+	Location string              `json:"location"`
+	Position *utils.YAMLPosition `json:"position,omitempty"`
+}
+
+func (f *Finding) String() string {
+	if f.Position != nil {
+		return fmt.Sprintf("%s: %s: %s [%s]", f.Position, f.Severity, f.Message, f.RuleID)
+	}
+	return fmt.Sprintf(`%s: %s at "%s" [%s]`, f.Severity, f.Message, f.Location, f.RuleID)
+}
+
+// Rule checks a model, and returns a finding for each problem.
+// The rule ID and severity of the findings are filled in by Lint.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Check       func(model v1.Model) []Finding
+}
+
+var registeredRules = map[string]*Rule{}
+
+// RegisterRule makes a rule available to Lint
+func RegisterRule(rule *Rule) {
+	registeredRules[rule.ID] = rule
+}
+
+// Rules returns all registered rules sorted by ID
+func Rules() []*Rule {
+	var rules []*Rule
+	for _, rule := range registeredRules {
+		rules = append(rules, rule)
+	}
+	slices.SortFunc(rules, func(a, b *Rule) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return rules
+}
+
+// Lint runs all the enabled rules against the model
+func Lint(model v1.Model, config *Config) []Finding {
+	var findings []Finding
+	for _, rule := range Rules() {
+		enabled, severity := config.RuleSettings(rule)
+		if !enabled {
+			continue
+		}
+
+		for _, finding := range rule.Check(model) {
+			finding.RuleID = rule.ID
+			finding.Severity = severity
+			if position, ok := v1.ModelPosition(model, finding.Location); ok {
+				finding.Position = &position
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	slices.SortFunc(findings, compareFindings)
+	return findings
+}
+
+// compareFindings orders findings as they appear in the YAML file, followed by the findings without a position.
+// Findings at the same position are ordered by rule ID, and then by message.
+func compareFindings(a, b Finding) int {
+	if (a.Position == nil) != (b.Position == nil) {
+		if a.Position == nil {
+			return 1
+		}
+		return -1
+	}
+	if a.Position != nil {
+		if c := cmp.Compare(a.Position.Line, b.Position.Line); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Position.Column, b.Position.Column); c != 0 {
+			return c
+		}
+	}
+	return cmp.Or(strings.Compare(a.RuleID, b.RuleID), strings.Compare(a.Message, b.Message))
+}
+
+// CountSeverity returns the number of findings with the given severity
+func CountSeverity(findings []Finding, severity Severity) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
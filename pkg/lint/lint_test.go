@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		want       []string
+	}{
+		{
+			"problems",
+			"",
+			[]string{
+				`testdata/problems/apiproxy.yaml:14:1: warning: API proxy "problems" has no SpikeArrest policy attached [missing-spike-arrest]`,
+				`testdata/problems/apiproxy.yaml:18:5: warning: policy "AM-SetHeaders" has continueOnError enabled [continue-on-error]`,
+				`testdata/problems/apiproxy.yaml:26:5: info: policy "AM-Disabled" is disabled [disabled-policy]`,
+				`testdata/problems/apiproxy.yaml:32:5: warning: proxy endpoint "default" has no FaultRules or DefaultFaultRule [missing-fault-handling]`,
+				`testdata/problems/apiproxy.yaml:47:11: warning: flow "post-pets" is never executed, because flow "catch-all" before it has no condition [unreachable-flow]`,
+				`testdata/problems/apiproxy.yaml:56:5: warning: target endpoint "default" has no FaultRules or DefaultFaultRule [missing-fault-handling]`,
+				`testdata/problems/apiproxy.yaml:58:7: warning: target endpoint "default" uses hard-coded URL "https://example.com" [hard-coded-target-url]`,
+			},
+		},
+		{
+			"problems",
+			"lint-config.yaml",
+			[]string{
+				`testdata/problems/apiproxy.yaml:18:5: warning: policy "AM-SetHeaders" has continueOnError enabled [continue-on-error]`,
+				`testdata/problems/apiproxy.yaml:26:5: info: policy "AM-Disabled" is disabled [disabled-policy]`,
+				`testdata/problems/apiproxy.yaml:32:5: warning: proxy endpoint "default" has no FaultRules or DefaultFaultRule [missing-fault-handling]`,
+				`testdata/problems/apiproxy.yaml:47:11: warning: flow "post-pets" is never executed, because flow "catch-all" before it has no condition [unreachable-flow]`,
+				`testdata/problems/apiproxy.yaml:56:5: warning: target endpoint "default" has no FaultRules or DefaultFaultRule [missing-fault-handling]`,
+				`testdata/problems/apiproxy.yaml:58:7: error: target endpoint "default" uses hard-coded URL "https://example.com" [hard-coded-target-url]`,
+			},
+		},
+		{
+			"clean",
+			"",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		ttDir := filepath.Join("testdata", tt.name)
+		t.Run(tt.name, func(t *testing.T) {
+			var config *Config
+			var err error
+			if tt.configFile != "" {
+				config, err = LoadConfig(filepath.Join(ttDir, tt.configFile))
+				require.NoError(t, err)
+			}
+
+			model, err := bundle.LoadModel(filepath.Join(ttDir, "apiproxy.yaml"))
+			require.NoError(t, err)
+
+			var got []string
+			for _, finding := range Lint(model, config) {
+				got = append(got, finding.String())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareFindings(t *testing.T) {
+	at := func(line int, column int) *utils.YAMLPosition {
+		return &utils.YAMLPosition{File: "apiproxy.yaml", Line: line, Column: column}
+	}
+	findings := []Finding{
+		{RuleID: "b-rule", Message: "unpositioned"},
+		{RuleID: "a-rule", Message: "second", Position: at(2, 1)},
+		{RuleID: "a-rule", Message: "unpositioned"},
+		{RuleID: "b-rule", Message: "first", Position: at(1, 5)},
+		{RuleID: "a-rule", Message: "b", Position: at(1, 5)},
+		{RuleID: "a-rule", Message: "a", Position: at(1, 5)},
+		{RuleID: "c-rule", Message: "first", Position: at(1, 1)},
+	}
+
+	slices.SortFunc(findings, compareFindings)
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.String())
+	}
+	require.Equal(t, []string{
+		`apiproxy.yaml:1:1: : first [c-rule]`,
+		`apiproxy.yaml:1:5: : a [a-rule]`,
+		`apiproxy.yaml:1:5: : b [a-rule]`,
+		`apiproxy.yaml:1:5: : first [b-rule]`,
+		`apiproxy.yaml:2:1: : second [a-rule]`,
+		`: unpositioned at "" [a-rule]`,
+		`: unpositioned at "" [b-rule]`,
+	}, got)
+}
+
+func TestLoadConfig(t *testing.T) {
+	_, err := LoadConfig(filepath.Join("testdata", "invalid-config.yaml"))
+	require.EqualError(t, err, `testdata/invalid-config.yaml: rule "missing-spike-arrest". severity "fatal" is not valid, must be one of [error warning info]`)
+}
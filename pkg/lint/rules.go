@@ -0,0 +1,288 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/condition"
+	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"strings"
+)
+
+func init() {
+	RegisterRule(&Rule{
+		ID:          "missing-fault-handling",
+		Description: "Proxy and target endpoints should have FaultRules or a DefaultFaultRule",
+		Severity:    SeverityWarning,
+		Check:       checkMissingFaultHandling,
+	})
+	RegisterRule(&Rule{
+		ID:          "unreachable-flow",
+		Description: "Flows after a flow without condition are never executed",
+		Severity:    SeverityWarning,
+		Check:       checkUnreachableFlow,
+	})
+	RegisterRule(&Rule{
+		ID:          "unreachable-route-rule",
+		Description: "Route rules after a route rule without condition are never evaluated",
+		Severity:    SeverityWarning,
+		Check:       checkUnreachableRouteRule,
+	})
+	RegisterRule(&Rule{
+		ID:          "continue-on-error",
+		Description: "Policies with continueOnError enabled silently ignore errors",
+		Severity:    SeverityWarning,
+		Check:       checkContinueOnError,
+	})
+	RegisterRule(&Rule{
+		ID:          "disabled-policy",
+		Description: "Disabled policies are never executed",
+		Severity:    SeverityInfo,
+		Check:       checkDisabledPolicy,
+	})
+	RegisterRule(&Rule{
+		ID:          "hard-coded-target-url",
+		Description: "Target URLs should come from a TargetServer or a flow variable, rather than being hard-coded",
+		Severity:    SeverityWarning,
+		Check:       checkHardCodedTargetURL,
+	})
+	RegisterRule(&Rule{
+		ID:          "missing-spike-arrest",
+		Description: "API proxies should protect their targets with a SpikeArrest policy",
+		Severity:    SeverityWarning,
+		Check:       checkMissingSpikeArrest,
+	})
+}
+
+func modelPolicies(model v1.Model) *v1.Policies {
+	switch m := model.(type) {
+	case *v1.APIProxyModel:
+		return &m.Policies
+	case *v1.SharedFlowBundleModel:
+		return &m.Policies
+	}
+	return &v1.Policies{}
+}
+
+func policyPath(index int, policy *v1.Policy) string {
+	return fmt.Sprintf("Root.Policies.%v.%s(name: %s)", index, policy.Type(), policy.Name())
+}
+
+func proxyEndpointPath(index int, proxyEndpoint *v1.ProxyEndpoint) string {
+	return fmt.Sprintf("Root.ProxyEndpoints.%v.ProxyEndpoint(name: %s)", index, proxyEndpoint.Name)
+}
+
+func targetEndpointPath(index int, targetEndpoint *v1.TargetEndpoint) string {
+	return fmt.Sprintf("Root.TargetEndpoints.%v.TargetEndpoint(name: %s)", index, targetEndpoint.Name)
+}
+
+// isCatchAll returns true if the condition always matches
+func isCatchAll(cond string) bool {
+	if strings.TrimSpace(cond) == "" {
+		return true
+	}
+	node, err := condition.Parse(cond)
+	if err != nil {
+		return false
+	}
+	literal, ok := node.(*condition.Literal)
+	return ok && literal.Kind == condition.LiteralBoolean && literal.Value == "true"
+}
+
+func hasFaultHandling(faultRules *v1.FaultRules, defaultFaultRule *v1.DefaultFaultRule) bool {
+	return (faultRules != nil && len(faultRules.List) > 0) || defaultFaultRule != nil
+}
+
+func checkMissingFaultHandling(model v1.Model) []Finding {
+	m, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return nil
+	}
+
+	var findings []Finding
+	for i, proxyEndpoint := range m.ProxyEndpoints.List {
+		if !hasFaultHandling(proxyEndpoint.FaultRules, proxyEndpoint.DefaultFaultRule) {
+			findings = append(findings, Finding{
+				Location: proxyEndpointPath(i, proxyEndpoint),
+				Message:  fmt.Sprintf(`proxy endpoint "%s" has no FaultRules or DefaultFaultRule`, proxyEndpoint.Name),
+			})
+		}
+	}
+	for i, targetEndpoint := range m.TargetEndpoints.List {
+		if !hasFaultHandling(targetEndpoint.FaultRules, targetEndpoint.DefaultFaultRule) {
+			findings = append(findings, Finding{
+				Location: targetEndpointPath(i, targetEndpoint),
+				Message:  fmt.Sprintf(`target endpoint "%s" has no FaultRules or DefaultFaultRule`, targetEndpoint.Name),
+			})
+		}
+	}
+	return findings
+}
+
+func unreachableFlows(flows *v1.Flows, path string) []Finding {
+	if flows == nil {
+		return nil
+	}
+
+	var findings []Finding
+	var catchAll *v1.Flow
+	for i, flow := range flows.List {
+		if catchAll != nil {
+			findings = append(findings, Finding{
+				Location: fmt.Sprintf("%s.Flows.%v.Flow", path, i),
+				Message:  fmt.Sprintf(`flow "%s" is never executed, because flow "%s" before it has no condition`, flow.Name, catchAll.Name),
+			})
+			continue
+		}
+		if isCatchAll(flow.Condition) {
+			catchAll = flow
+		}
+	}
+	return findings
+}
+
+func checkUnreachableFlow(model v1.Model) []Finding {
+	m, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return nil
+	}
+
+	var findings []Finding
+	for i, proxyEndpoint := range m.ProxyEndpoints.List {
+		findings = append(findings, unreachableFlows(proxyEndpoint.Flows, proxyEndpointPath(i, proxyEndpoint))...)
+	}
+	for i, targetEndpoint := range m.TargetEndpoints.List {
+		findings = append(findings, unreachableFlows(&targetEndpoint.Flows, targetEndpointPath(i, targetEndpoint))...)
+	}
+	return findings
+}
+
+func checkUnreachableRouteRule(model v1.Model) []Finding {
+	m, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return nil
+	}
+
+	var findings []Finding
+	for i, proxyEndpoint := range m.ProxyEndpoints.List {
+		if proxyEndpoint.RouteRules == nil {
+			continue
+		}
+		var catchAll *v1.RouteRule
+		for j, routeRule := range *proxyEndpoint.RouteRules {
+			if catchAll != nil {
+				findings = append(findings, Finding{
+					Location: fmt.Sprintf("%s.RouteRules.%v.RouteRule(name: %s)", proxyEndpointPath(i, proxyEndpoint), j, routeRule.Name),
+					Message:  fmt.Sprintf(`route rule "%s" is never evaluated, because route rule "%s" before it has no condition`, routeRule.Name, catchAll.Name),
+				})
+				continue
+			}
+			if isCatchAll(routeRule.Condition) {
+				catchAll = routeRule
+			}
+		}
+	}
+	return findings
+}
+
+func checkContinueOnError(model v1.Model) []Finding {
+	var findings []Finding
+	for i, policy := range modelPolicies(model).List {
+		if strings.EqualFold(policy.Attr("continueOnError"), "true") {
+			findings = append(findings, Finding{
+				Location: policyPath(i, policy),
+				Message:  fmt.Sprintf(`policy "%s" has continueOnError enabled`, policy.Name()),
+			})
+		}
+	}
+	return findings
+}
+
+func checkDisabledPolicy(model v1.Model) []Finding {
+	var findings []Finding
+	for i, policy := range modelPolicies(model).List {
+		if strings.EqualFold(policy.Attr("enabled"), "false") {
+			findings = append(findings, Finding{
+				Location: policyPath(i, policy),
+				Message:  fmt.Sprintf(`policy "%s" is disabled`, policy.Name()),
+			})
+		}
+	}
+	return findings
+}
+
+// isHardCodedURL returns true for URLs that do not use any flow variables
+func isHardCodedURL(url string) bool {
+	url = strings.TrimSpace(url)
+	return url != "" && !strings.Contains(url, "{")
+}
+
+func checkHardCodedTargetURL(model v1.Model) []Finding {
+	var findings []Finding
+	if m, ok := model.(*v1.APIProxyModel); ok {
+		for i, targetEndpoint := range m.TargetEndpoints.List {
+			connection := targetEndpoint.HTTPTargetConnection
+			if connection == nil || !isHardCodedURL(connection.URL) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Location: fmt.Sprintf("%s.HTTPTargetConnection", targetEndpointPath(i, targetEndpoint)),
+				Message:  fmt.Sprintf(`target endpoint "%s" uses hard-coded URL "%s"`, targetEndpoint.Name, connection.URL),
+			})
+		}
+	}
+
+	for i, policy := range modelPolicies(model).List {
+		if policy.Type() != "ServiceCallout" {
+			continue
+		}
+		serviceCallout := &v1.ServiceCallout{}
+		if err := policy.Unmarshal(serviceCallout); err != nil {
+			continue
+		}
+		connection := serviceCallout.HTTPTargetConnection
+		if connection == nil || !isHardCodedURL(connection.URL) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Location: fmt.Sprintf("%s.HTTPTargetConnection", policyPath(i, policy)),
+			Message:  fmt.Sprintf(`policy "%s" uses hard-coded URL "%s"`, policy.Name(), connection.URL),
+		})
+	}
+	return findings
+}
+
+func checkMissingSpikeArrest(model v1.Model) []Finding {
+	m, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return nil
+	}
+
+	policyTypes := map[string]string{}
+	for _, policy := range m.Policies.List {
+		policyTypes[policy.Name()] = policy.Type()
+	}
+
+	for _, location := range v1.StepLocations(m) {
+		if policyTypes[location.Step.Name] == "SpikeArrest" {
+			return nil
+		}
+	}
+
+	return []Finding{{
+		Location: "Root.APIProxy",
+		Message:  fmt.Sprintf(`API proxy "%s" has no SpikeArrest policy attached`, m.Name()),
+	}}
+}
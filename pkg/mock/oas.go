@@ -22,6 +22,7 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/render"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/go-errors/errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -50,6 +51,27 @@ func GenerateMockProxyBundle(input string, output string, cFlags *render.CommonF
 	return render.GenerateBundle(createModelFunc, cFlags, true, "", debug)
 }
 
+// GenerateMockProxyBundleFS is like GenerateMockProxyBundle, but it does not touch the local disk.
+// The spec is read from fsys, and the bundle is returned in memory.
+func GenerateMockProxyBundleFS(fsys fs.FS, input string, cFlags *render.CommonFlags) (*render.Bundle, error) {
+	specFileText, err := fs.ReadFile(fsys, input)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	var oas = flags.NewSetOAS(cFlags.Values)
+	if err = oas.SetText("spec", input, specFileText); err != nil {
+		return nil, err
+	}
+
+	createModelFunc := func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewAPIProxyModelFS(fsys, input)
+	}
+
+	cFlags.TemplateFile = flags.NewString("apiproxy.yaml")
+	return render.GenerateBundleFS(mock_apiproxy_template.FS, createModelFunc, cFlags, true)
+}
+
 func getMockProxyTemplateDir() (string, error) {
 	tmpDir, err := os.MkdirTemp("", "mock_apiproxy_*")
 	if err != nil {
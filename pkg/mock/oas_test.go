@@ -18,6 +18,7 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/render"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/stretchr/testify/require"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -48,3 +49,20 @@ func TestGenerateMockProxyBundle(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateMockProxyBundleFS(t *testing.T) {
+	specsDir := filepath.Join("..", "utils", "testdata", "specs", "oas3")
+	expectedOutputPath := filepath.Join("testdata", "mocks", "petstore", "exp-apiproxy.zip")
+
+	bundle, err := GenerateMockProxyBundleFS(os.DirFS(specsDir), "petstore/oas3.yaml", render.NewCommonFlags())
+	require.NoError(t, err)
+	require.Contains(t, bundle.Files, "apiproxy/resources/oas/openapi.json")
+
+	zipBytes, err := bundle.Zip()
+	require.NoError(t, err)
+
+	outputPath := filepath.Join(t.TempDir(), "apiproxy.zip")
+	require.NoError(t, os.WriteFile(outputPath, zipBytes, os.ModePerm))
+
+	utils.RequireBundleZipEquals(t, expectedOutputPath, outputPath)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/vektah/gqlparser/v2"
 	ast2 "github.com/vektah/gqlparser/v2/ast"
 	"os"
+	"path/filepath"
 )
 
 func ParseOAS(specFile string) (libopenapi.Document, error) {
@@ -34,8 +35,14 @@ func ParseOAS(specFile string) (libopenapi.Document, error) {
 		return nil, errors.New(err)
 	}
 
+	return ParseOASText(specBytes, filepath.Dir(specFile))
+}
+
+// ParseOASText is like ParseOAS, for a spec in memory. File references are relative to basePath.
+func ParseOASText(specBytes []byte, basePath string) (libopenapi.Document, error) {
+	var err error
 	config := datamodel.DocumentConfiguration{
-		BasePath:            ".",
+		BasePath:            basePath,
 		AllowFileReferences: true,
 	}
 
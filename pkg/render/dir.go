@@ -0,0 +1,301 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/Masterminds/sprig/v3"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/go-errors/errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// DirIgnoreFile lists the paths within the template directory that are not rendered, using the same patterns as .gitignore
+const DirIgnoreFile = ".apigee-go-gen-ignore"
+
+// dirFile is a file within the template directory, along with the path it renders to
+type dirFile struct {
+	source string
+	output string
+}
+
+// RenderDir renders each file within the template directory (cFlags.TemplateFile) into the same path within the output directory.
+//
+// Files and directories with a name starting with "_" are partials (e.g. "_helpers.tmpl"), and are not rendered on their own.
+// File and directory names may contain template actions (e.g. "{{ .Values.name }}.tf"). Files with a name that renders empty are skipped.
+// Paths matching the patterns in the DirIgnoreFile of the template directory are skipped.
+func RenderDir(cFlags *CommonFlags, dryRun bool) error {
+	templateDir := string(cFlags.TemplateFile)
+	if git.IsGitURI(templateDir) {
+		return errors.Errorf("template directories from git are not supported, clone the repository first")
+	}
+
+	info, err := os.Stat(templateDir)
+	if err != nil {
+		return errors.New(err)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("%s is not a directory", templateDir)
+	}
+
+	if err = validateValuesInDir(utils.OSFS, templateDir, *cFlags.Values); err != nil {
+		return err
+	}
+
+	context := &TemplateContext{
+		Values: *cFlags.Values,
+	}
+
+	files, err := listDirFiles(templateDir, context, bool(cFlags.Strict))
+	if err != nil {
+		return err
+	}
+
+	//the helpers at the top of the template directory are available to all files
+	var rootHelpers []string
+	for _, helperFileName := range []string{"_helpers.tpl", "_helpers.tmpl"} {
+		helperFilePath := filepath.Join(templateDir, helperFileName)
+		if _, err = os.Stat(helperFilePath); err == nil {
+			rootHelpers = append(rootHelpers, helperFilePath)
+		}
+	}
+
+	for _, file := range files {
+		fileFlags := *cFlags
+		fileFlags.TemplateFile = flags.String(filepath.Join(templateDir, file.source))
+		fileFlags.TemplateFileAlias = flags.String(file.source)
+		fileFlags.OutputFile = flags.String(filepath.Join(string(cFlags.OutputFile), file.output))
+		fileFlags.SourceMap = nil
+		fileFlags.IncludeList = slices.Clone(cFlags.IncludeList)
+		if filepath.Dir(file.source) != "." {
+			fileFlags.IncludeList = append(fileFlags.IncludeList, rootHelpers...)
+		}
+
+		if dryRun {
+			fmt.Printf("==> %s <==\n", filepath.ToSlash(file.output))
+		}
+
+		if err = RenderGeneric(context, &fileFlags, dryRun); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			//keep the permissions of the source file (e.g. for scripts)
+			sourceInfo, err := os.Stat(string(fileFlags.TemplateFile))
+			if err != nil {
+				return errors.New(err)
+			}
+			if err = os.Chmod(string(fileFlags.OutputFile), sourceInfo.Mode().Perm()); err != nil {
+				return errors.New(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// listDirFiles finds the files to render within the template directory, and the path each file renders to
+func listDirFiles(templateDir string, context any, strict bool) ([]dirFile, error) {
+	ignore, err := loadDirIgnore(filepath.Join(templateDir, DirIgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []dirFile
+	outputs := map[string]string{}
+	err = filepath.WalkDir(templateDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filePath == templateDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(templateDir, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if skipDirEntry(relPath, entry) || ignore.matches(relPath, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		output, err := renderDirPath(relPath, context, strict)
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			return nil
+		}
+		if other, found := outputs[output]; found {
+			return errors.Errorf("both %s and %s render to %s", other, relPath, output)
+		}
+		outputs[output] = relPath
+
+		files = append(files, dirFile{source: filepath.FromSlash(relPath), output: filepath.FromSlash(output)})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return files, nil
+}
+
+// skipDirEntry checks for partials, the ignore file, values schemas, and the .git directory
+func skipDirEntry(relPath string, entry fs.DirEntry) bool {
+	name := entry.Name()
+	switch {
+	case strings.HasPrefix(name, "_"):
+		return true
+	case name == ".git" && entry.IsDir():
+		return true
+	case relPath == DirIgnoreFile:
+		return true
+	case slices.Contains(ValuesSchemaFileNames, relPath):
+		return true
+	}
+	return false
+}
+
+// renderDirPath renders the template actions within each segment of the path.
+// An empty string is returned if any segment renders empty.
+func renderDirPath(relPath string, context any, strict bool) (string, error) {
+	if !strings.Contains(relPath, "{{") {
+		return relPath, nil
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(relPath, "/") {
+		if !strings.Contains(segment, "{{") {
+			segments = append(segments, segment)
+			continue
+		}
+
+		tmpl, err := template.New(relPath).Funcs(sprig.FuncMap()).Parse(segment)
+		if err != nil {
+			return "", errors.New(err)
+		}
+		if strict {
+			tmpl.Option("missingkey=error")
+		}
+
+		rendered := bytes.Buffer{}
+		if err = tmpl.Execute(&rendered, context); err != nil {
+			return "", errors.New(err)
+		}
+
+		name := strings.TrimSpace(rendered.String())
+		if strict && strings.Contains(name, noValue) {
+			return "", errors.Errorf("%s: file name contains %q", relPath, noValue)
+		}
+		if name == "" {
+			return "", nil
+		}
+		if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return "", errors.Errorf("%s: file name must not render to %q", relPath, name)
+		}
+		segments = append(segments, name)
+	}
+
+	return path.Join(segments...), nil
+}
+
+type dirIgnorePattern struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+type dirIgnore []dirIgnorePattern
+
+// loadDirIgnore reads the ignore file, if there is one
+func loadDirIgnore(ignoreFile string) (dirIgnore, error) {
+	file, err := os.Open(ignoreFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.New(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var ignore dirIgnore
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := dirIgnorePattern{}
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		//patterns without a slash match at any depth, others are relative to the template directory
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if !doublestar.ValidatePattern(line) {
+			return nil, errors.Errorf("%s: invalid pattern %q", ignoreFile, scanner.Text())
+		}
+		pattern.pattern = line
+		ignore = append(ignore, pattern)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.New(err)
+	}
+
+	return ignore, nil
+}
+
+// matches checks whether the path is ignored, the last matching pattern wins
+func (d dirIgnore) matches(relPath string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range d {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if matched, _ := doublestar.Match(pattern.pattern, relPath); matched {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderDir(t *testing.T) {
+	testDir := filepath.Join("testdata", "render-dir")
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(filepath.Join(testDir, "template"))
+	cFlags.OutputFile = flags.String(outputDir)
+	cFlags.Values.Set("name", "orders")
+	cFlags.Values.Set("ci", true)
+
+	err := RenderDir(cFlags, false)
+	require.NoError(t, err)
+
+	expected, err := utils.ReadBundleDir(filepath.Join(testDir, "exp"))
+	require.NoError(t, err)
+	actual, err := utils.ReadBundleDir(outputDir)
+	require.NoError(t, err)
+
+	toText := func(files map[string][]byte) map[string]string {
+		result := map[string]string{}
+		for name, contents := range files {
+			result[name] = string(contents)
+		}
+		return result
+	}
+	require.Equal(t, toText(expected), toText(actual))
+
+	info, err := os.Stat(filepath.Join(outputDir, "scripts", "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestRenderDir_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			"not-a-dir",
+			nil,
+			"is not a directory",
+		},
+		{
+			"same-output",
+			map[string]string{"a.txt": "a", "{{ \"a.txt\" }}": "b"},
+			"render to a.txt",
+		},
+		{
+			"parent-path",
+			map[string]string{"{{ \"..\" }}": "a"},
+			`file name must not render to ".."`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			templateDir := filepath.Join(dir, "template")
+			if tt.files == nil {
+				require.NoError(t, os.WriteFile(templateDir, []byte{}, os.ModePerm))
+			}
+			for name, contents := range tt.files {
+				require.NoError(t, os.MkdirAll(templateDir, os.ModePerm))
+				require.NoError(t, os.WriteFile(filepath.Join(templateDir, name), []byte(contents), os.ModePerm))
+			}
+
+			cFlags := NewCommonFlags()
+			cFlags.TemplateFile = flags.String(templateDir)
+			cFlags.OutputFile = flags.String(filepath.Join(dir, "out"))
+
+			err := RenderDir(cFlags, false)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDirIgnore_Matches(t *testing.T) {
+	ignoreFile := filepath.Join(t.TempDir(), DirIgnoreFile)
+	require.NoError(t, os.WriteFile(ignoreFile, []byte("# comment\n*.bak\n!keep.bak\nbuild/\n/docs/*.md\n"), os.ModePerm))
+
+	ignore, err := loadDirIgnore(ignoreFile)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"draft.bak", false, true},
+		{"sub/draft.bak", false, true},
+		{"keep.bak", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"docs/index.md", false, true},
+		{"sub/docs/index.md", false, false},
+		{"README.md", false, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, ignore.matches(tt.path, tt.isDir), tt.path)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing/fstest"
+)
+
+// templateFiles is where templates read files from (the template itself, helpers, included files, and files to copy),
+// and where helper functions (e.g. os_writefile) write files to, relative to the output directory
+type templateFiles struct {
+	fsys fs.FS
+
+	// writeFile writes a file relative to the output directory, it is nil for dry runs
+	writeFile func(name string, data []byte) error
+
+	// readFile reads a file relative to the output directory
+	readFile func(name string) ([]byte, error)
+}
+
+// outputDirFiles reads templates from the local disk, and writes files next to the output file
+func outputDirFiles(outputFile string, dryRun bool) *templateFiles {
+	outputDir := filepath.Dir(outputFile)
+	files := &templateFiles{
+		fsys: utils.OSFS,
+		readFile: func(name string) ([]byte, error) {
+			return os.ReadFile(filepath.Join(outputDir, name))
+		},
+	}
+
+	if !dryRun {
+		files.writeFile = func(name string, data []byte) error {
+			filePath := filepath.Join(outputDir, name)
+			if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+				return errors.New(err)
+			}
+			if err := os.WriteFile(filePath, data, os.ModePerm); err != nil {
+				return errors.New(err)
+			}
+			return nil
+		}
+	}
+	return files
+}
+
+// memoryFiles reads templates from fsys, and keeps written files in memory.
+// Files are written into outputDir within the returned overlay, so that they can be read back along with the files in fsys.
+func memoryFiles(fsys fs.FS, outputDir string) (*templateFiles, *overlayFS) {
+	overlay := &overlayFS{base: fsys, files: fstest.MapFS{}}
+	files := &templateFiles{
+		fsys: overlay,
+		readFile: func(name string) ([]byte, error) {
+			return fs.ReadFile(overlay, path.Join(outputDir, name))
+		},
+		writeFile: func(name string, data []byte) error {
+			overlay.files[path.Join(outputDir, name)] = &fstest.MapFile{Data: data, Mode: 0644}
+			return nil
+		},
+	}
+	return files, overlay
+}
+
+// overlayFS is a read-only fs.FS with files written in memory on top
+type overlayFS struct {
+	base  fs.FS
+	files fstest.MapFS
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	if _, found := o.files[name]; found {
+		return o.files.Open(name)
+	}
+	return o.base.Open(name)
+}
@@ -16,12 +16,15 @@ package render
 
 import (
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/values"
+	"time"
 )
 
 func NewCommonFlags() *CommonFlags {
 	return &CommonFlags{
-		Values: &values.Map{},
+		Values:     &values.Map{},
+		GitSources: &git.Sources{},
 	}
 }
 
@@ -30,5 +33,27 @@ type CommonFlags struct {
 	TemplateFileAlias flags.String
 	OutputFile        flags.String
 	IncludeList       flags.IncludeList
+	Strict            flags.Bool
 	Values            *values.Map
+
+	// SHA256Manifest writes the SHA-256 manifest of the bundle files next to the bundle output (e.g. "apiproxy.zip.sha256")
+	SHA256Manifest flags.Bool
+
+	// Sandbox removes the template functions that read the host environment (os_getenv, os_getenvs, and sprig's env, expandenv and getHostByName),
+	// and fmt_printf, which writes to the standard output of the host.
+	// It is meant for templates from untrusted sources, e.g. posted to the serve command.
+	Sandbox bool
+
+	// MaxOutputSize fails rendering once the rendered template (or an included template) is above this many bytes, zero means no limit
+	MaxOutputSize int64
+
+	// Deadline fails rendering once it has passed, the zero time means no deadline
+	Deadline time.Time
+
+	// GitSources collects the git URIs fetched for this render (the template, includes and values), along with the commit each ref resolved to.
+	// The commits are recorded in the SHA-256 manifest, and the commands print them.
+	GitSources *git.Sources
+
+	// SourceMap is filled in with the template location of each rendered line, if not nil
+	SourceMap *SourceMap
 }
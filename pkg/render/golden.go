@@ -0,0 +1,278 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const TemplateTestsDir = "tests"
+const TemplateTestFile = "test.yaml"
+const ExpectedYAMLFile = "expected.yaml"
+const ExpectedBundleDir = "expected"
+
+// TemplateTest is a golden file test case for a template. Each test case is a directory within the "tests" directory next to the template.
+//
+// The inputs are read from the "test.yaml" file, using the same keys as a workspace bundle (except for name, template and output).
+// Relative paths are relative to the test case directory.
+// The expected output is either the API proxy (or shared flow) model as YAML in "expected.yaml", or the bundle tree in the "expected" directory.
+type TemplateTest struct {
+	WorkspaceBundle `yaml:",inline"`
+
+	Dir string `yaml:"-"`
+}
+
+// TemplateTestResult is the outcome of running a single test case
+type TemplateTestResult struct {
+	Name     string
+	Dir      string
+	Duration time.Duration
+	Updated  bool
+	Err      error
+}
+
+// LoadTemplateTests finds the test cases for the given template, sorted by name
+func LoadTemplateTests(templateFile string) ([]*TemplateTest, error) {
+	if git.IsGitURI(templateFile) {
+		return nil, errors.Errorf("tests are not supported for templates from git, clone the repository first")
+	}
+
+	testsDir := filepath.Join(filepath.Dir(templateFile), TemplateTestsDir)
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	var tests []*TemplateTest
+	for _, entry := range entries {
+		testFile := filepath.Join(testsDir, entry.Name(), TemplateTestFile)
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err = os.Stat(testFile); err != nil {
+			continue
+		}
+
+		test, err := loadTemplateTest(testFile)
+		if err != nil {
+			return nil, err
+		}
+		test.Name = entry.Name()
+		tests = append(tests, test)
+	}
+
+	if len(tests) == 0 {
+		return nil, errors.Errorf("no test cases found in %s", testsDir)
+	}
+
+	return tests, nil
+}
+
+func loadTemplateTest(testFile string) (*TemplateTest, error) {
+	text, err := os.ReadFile(testFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	test := &TemplateTest{Dir: filepath.Dir(testFile)}
+	decoder := yaml.NewDecoder(bytes.NewReader(text))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(test); err != nil && err != io.EOF {
+		return nil, errors.Errorf("%s: %s", testFile, err.Error())
+	}
+
+	switch {
+	case test.Name != "":
+		return nil, errors.Errorf("%s: name is not allowed, the name of the test case is the name of its directory", testFile)
+	case test.Template != "":
+		return nil, errors.Errorf("%s: template is not allowed, the template is given to the test command", testFile)
+	case test.Output != "":
+		return nil, errors.Errorf("%s: output is not allowed, the expected output is in %s or %s", testFile, ExpectedYAMLFile, ExpectedBundleDir)
+	}
+
+	if test.Type == "" {
+		test.Type = "apiproxy"
+	}
+	if test.Type != "apiproxy" && test.Type != "sharedflow" {
+		return nil, errors.Errorf("%s: type must be either apiproxy or sharedflow", testFile)
+	}
+
+	return test, nil
+}
+
+// SelectTemplateTests returns the test cases with the given names, or all test cases if no names are given
+func SelectTemplateTests(tests []*TemplateTest, names []string) ([]*TemplateTest, error) {
+	if len(names) == 0 {
+		return tests, nil
+	}
+
+	var selected []*TemplateTest
+	for _, name := range names {
+		index := slices.IndexFunc(tests, func(test *TemplateTest) bool { return test.Name == name })
+		if index < 0 {
+			return nil, errors.Errorf("test case %q not found", name)
+		}
+		selected = append(selected, tests[index])
+	}
+	return selected, nil
+}
+
+// RunTemplateTests renders the template for each test case, and compares it with the expected output.
+// When update is true, the expected output is written instead.
+// The progress function (if any) is called as soon as each test case is done.
+func RunTemplateTests(templateFile string, tests []*TemplateTest, update bool, progress func(result TemplateTestResult)) []TemplateTestResult {
+	var results []TemplateTestResult
+	for _, test := range tests {
+		result := test.run(templateFile, update)
+		if progress != nil {
+			progress(result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (t *TemplateTest) run(templateFile string, update bool) TemplateTestResult {
+	start := time.Now()
+	result := TemplateTestResult{Name: t.Name, Dir: t.Dir}
+
+	model, err := t.render(templateFile)
+	if err == nil {
+		if update {
+			err = t.update(model)
+			result.Updated = err == nil
+		} else {
+			err = t.compare(model)
+		}
+	}
+
+	result.Err = err
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (t *TemplateTest) render(templateFile string) (v1.Model, error) {
+	absTemplateFile, err := filepath.Abs(templateFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	bundle := t.WorkspaceBundle
+	bundle.Template = absTemplateFile
+	workspace := &Workspace{Dir: t.Dir}
+
+	cFlags, err := workspace.CommonFlags(&bundle)
+	if err != nil {
+		return nil, err
+	}
+	cFlags.TemplateFileAlias = flags.String(templateFile)
+
+	createModelFunc := func(input string) (v1.Model, error) {
+		if bundle.Type == "sharedflow" {
+			return v1.NewSharedFlowBundleModel(input)
+		}
+		return v1.NewAPIProxyModel(input)
+	}
+
+	validate := bundle.Validate == nil || *bundle.Validate
+	return GenerateModel(createModelFunc, cFlags, validate)
+}
+
+// expectsYAML checks whether the expected output is the model as YAML (rather than the bundle tree)
+func (t *TemplateTest) expectsYAML() bool {
+	_, err := os.Stat(filepath.Join(t.Dir, ExpectedYAMLFile))
+	return err == nil
+}
+
+func (t *TemplateTest) update(model v1.Model) error {
+	if t.expectsYAML() {
+		yamlText, err := model.YAML()
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(t.Dir, ExpectedYAMLFile), yamlText, os.ModePerm); err != nil {
+			return errors.New(err)
+		}
+		return nil
+	}
+
+	expectedDir := filepath.Join(t.Dir, ExpectedBundleDir)
+	if err := os.RemoveAll(expectedDir); err != nil {
+		return errors.New(err)
+	}
+	return v1.Model2BundleDir(model, expectedDir)
+}
+
+func (t *TemplateTest) compare(model v1.Model) error {
+	var diffs []string
+	if t.expectsYAML() {
+		expectedText, err := os.ReadFile(filepath.Join(t.Dir, ExpectedYAMLFile))
+		if err != nil {
+			return errors.New(err)
+		}
+		actualText, err := model.YAML()
+		if err != nil {
+			return err
+		}
+
+		normalizedExpected, err := utils.NormalizeYAML(expectedText)
+		if err != nil {
+			return errors.Errorf("%s: %s", ExpectedYAMLFile, err.Error())
+		}
+		normalizedActual, err := utils.NormalizeYAML(actualText)
+		if err != nil {
+			return err
+		}
+		if diff := utils.LineDiff(normalizedExpected, normalizedActual); diff != "" {
+			diffs = append(diffs, ExpectedYAMLFile+": "+diff)
+		}
+	} else {
+		expectedDir := filepath.Join(t.Dir, ExpectedBundleDir)
+		if _, err := os.Stat(expectedDir); err != nil {
+			return errors.Errorf("no expected output found, add %s or %s (or use the update flag to create it)", ExpectedYAMLFile, ExpectedBundleDir)
+		}
+		expected, err := utils.ReadBundleDir(expectedDir)
+		if err != nil {
+			return err
+		}
+		actual, err := v1.Model2BundleFiles(model)
+		if err != nil {
+			return err
+		}
+		if diffs, err = utils.BundleDiff(expected, actual); err != nil {
+			return err
+		}
+	}
+
+	if len(diffs) == 0 {
+		return nil
+	}
+	multiError := utils.MultiError{}
+	for _, diff := range diffs {
+		multiError.Errors = append(multiError.Errors, errors.New(diff))
+	}
+	return multiError
+}
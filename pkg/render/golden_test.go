@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTemplateTests(t *testing.T) {
+	templateFile := filepath.Join("testdata", "template-tests", "apiproxy.yaml")
+
+	tests, err := LoadTemplateTests(templateFile)
+	require.NoError(t, err)
+
+	results := RunTemplateTests(templateFile, tests, false, nil)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.NoError(t, result.Err, result.Name)
+		require.False(t, result.Updated)
+	}
+}
+
+func TestRunTemplateTests_Update(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, utils.CopyDir(dir, filepath.Join("testdata", "template-tests")))
+	templateFile := filepath.Join(dir, "apiproxy.yaml")
+
+	replaceInFile := func(file string, old string, new string) {
+		text, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Contains(t, string(text), old)
+		require.NoError(t, os.WriteFile(file, []byte(strings.ReplaceAll(string(text), old, new)), os.ModePerm))
+	}
+
+	replaceInFile(filepath.Join(dir, "tests", "values", "expected.yaml"), "BasePath: /v1/hello", "BasePath: /v2/hello")
+	replaceInFile(filepath.Join(dir, "tests", "oas", "expected", "apiproxy", "targets", "default.xml"), "https://petstore.example.com", "https://old.example.com")
+	require.NoError(t, os.Remove(filepath.Join(dir, "tests", "oas", "expected", "apiproxy", "policies", "AM-SetTarget.xml")))
+
+	tests, err := LoadTemplateTests(templateFile)
+	require.NoError(t, err)
+
+	results := RunTemplateTests(templateFile, tests, false, nil)
+	require.Len(t, results, 2)
+	require.Equal(t, "oas", results[0].Name)
+	require.EqualError(t, results[0].Err, `apiproxy/policies/AM-SetTarget.xml: unexpected file
+apiproxy/targets/default.xml: line 5 differs
+  expected:         URL: https://old.example.com
+  actual:           URL: https://petstore.example.com`)
+	require.Equal(t, "values", results[1].Name)
+	require.ErrorContains(t, results[1].Err, "expected.yaml: line ")
+	require.ErrorContains(t, results[1].Err, "BasePath: /v2/hello")
+
+	results = RunTemplateTests(templateFile, tests, true, nil)
+	for _, result := range results {
+		require.NoError(t, result.Err, result.Name)
+		require.True(t, result.Updated)
+	}
+
+	results = RunTemplateTests(templateFile, tests, false, nil)
+	for _, result := range results {
+		require.NoError(t, result.Err, result.Name)
+	}
+}
+
+func TestLoadTemplateTests(t *testing.T) {
+	tests := []struct {
+		name     string
+		testFile string
+		wantErr  string
+	}{
+		{"template", "template: other.yaml", "template is not allowed, the template is given to the test command"},
+		{"output", "output: out.zip", "output is not allowed, the expected output is in expected.yaml or expected"},
+		{"type", "type: proxy", "type must be either apiproxy or sharedflow"},
+		{"unknown", "set-yaml: []", "field set-yaml not found in type render.TemplateTest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			testDir := filepath.Join(dir, TemplateTestsDir, tt.name)
+			require.NoError(t, os.MkdirAll(testDir, os.ModePerm))
+			require.NoError(t, os.WriteFile(filepath.Join(testDir, TemplateTestFile), []byte(tt.testFile), os.ModePerm))
+
+			_, err := LoadTemplateTests(filepath.Join(dir, "apiproxy.yaml"))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := LoadTemplateTests(filepath.Join(t.TempDir(), "apiproxy.yaml"))
+	require.ErrorContains(t, err, "no such file or directory")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/utils"
 	"github.com/apigee/apigee-go-gen/pkg/utils/mcp"
 	"github.com/go-errors/errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,7 +28,7 @@ import (
 
 type HelperFunc func(args ...any) string
 
-func getOSCopyFileFunc(templateFile string, outputFile string, dryRun bool) HelperFunc {
+func getOSCopyFileFunc(files *templateFiles, templateFile string) HelperFunc {
 	_osCopyFileFunc := func(args ...any) string {
 		defer recoverPanic()
 
@@ -52,30 +53,18 @@ func getOSCopyFileFunc(templateFile string, outputFile string, dryRun bool) Help
 			panic("os_copyfile src must not use ..")
 		}
 
-		dstDir := filepath.Dir(filepath.Join(filepath.Dir(outputFile), dst))
+		srcPath := utils.FSJoin(files.fsys, utils.FSDir(files.fsys, templateFile), src)
 
-		if !dryRun {
-			err := os.MkdirAll(dstDir, os.ModePerm)
+		if files.writeFile != nil {
+			srcFileContent, err := fs.ReadFile(files.fsys, srcPath)
 			if err != nil {
 				panic(err)
 			}
-		}
-
-		srcPath := filepath.Join(filepath.Dir(templateFile), src)
-		dstPath := filepath.Join(filepath.Dir(outputFile), dst)
 
-		if !dryRun {
-			srcFileContent, err := os.ReadFile(srcPath)
+			err = files.writeFile(dst, srcFileContent)
 			if err != nil {
 				panic(err)
 			}
-
-			err = os.WriteFile(dstPath, srcFileContent, os.ModePerm)
-			if err != nil {
-				panic(err)
-			}
-		} else {
-			//fmt.Printf(`os_copyfile("%s", "%s")\n`, dstPath, srcPath)
 		}
 
 		return dst
@@ -84,80 +73,54 @@ func getOSCopyFileFunc(templateFile string, outputFile string, dryRun bool) Help
 	return _osCopyFileFunc
 }
 
-func getRemoveOASExtensions(templateFile string, outputFile string, dryRun bool) HelperFunc {
-	_removeOASExtensionsFunc := func(args ...any) string {
+func getRemoveOASExtensions(files *templateFiles) HelperFunc {
+	return getTransformOASFileFunc("remove_oas_extensions", files, utils.RemoveExtensionsText)
+}
+
+func getRemoveOASSchemaExtensions(files *templateFiles) HelperFunc {
+	return getTransformOASFileFunc("remove_oas_schema_extensions", files, utils.RemoveSchemaExtensionsText)
+}
+
+// getTransformOASFileFunc returns a helper function that transforms an OAS file in place, relative to the output file
+func getTransformOASFileFunc(funcName string, files *templateFiles, transform func(text []byte, ext string) ([]byte, error)) HelperFunc {
+	_transformOASFileFunc := func(args ...any) string {
 		defer recoverPanic()
 
 		if len(args) < 1 {
-			panic("remove_oas_extensions function requires one argument")
+			panic(fmt.Sprintf("%s function requires one argument", funcName))
 		}
 
 		//both destination and source are the same
-		dst := args[0].(string)
-		src := args[0].(string)
+		file := args[0].(string)
 
-		if filepath.IsAbs(src) {
-			panic("remove_oas_extensions src must not be absolute")
+		if filepath.IsAbs(file) {
+			panic(fmt.Sprintf("%s src must not be absolute", funcName))
 		}
-		if strings.Index(src, "..") >= 0 {
-			panic("remove_oas_extensions src must not use ..")
+		if strings.Index(file, "..") >= 0 {
+			panic(fmt.Sprintf("%s src must not use ..", funcName))
 		}
 
-		//both destination and source are relative to the output file
-		dstPath := filepath.Join(filepath.Dir(outputFile), dst)
-		srcPath := filepath.Join(filepath.Dir(outputFile), src)
+		if files.writeFile != nil {
+			text, err := files.readFile(file)
+			if err != nil {
+				panic(errors.New(err))
+			}
 
-		if !dryRun {
-			err := utils.RemoveExtensions(srcPath, dstPath)
+			text, err = transform(text, filepath.Ext(file))
 			if err != nil {
 				panic(err)
 			}
-		} else {
-			//fmt.Printf(`remove_oas_extensions("%s", "%s")\n`, dstPath, srcPath)
-		}
-
-		return dst
-	}
-
-	return _removeOASExtensionsFunc
-}
-
-func getRemoveOASSchemaExtensions(templateFile string, outputFile string, dryRun bool) HelperFunc {
-	_removeOASExtensionsFunc := func(args ...any) string {
-		defer recoverPanic()
-
-		if len(args) < 1 {
-			panic("remove_oas_schema_extensions function requires one argument")
-		}
-
-		//both destination and source are the same
-		dst := args[0].(string)
-		src := args[0].(string)
 
-		if filepath.IsAbs(src) {
-			panic("remove_oas_schema_extensions src must not be absolute")
-		}
-		if strings.Index(src, "..") >= 0 {
-			panic("remove_oas_schema_extensions src must not use ..")
-		}
-
-		//both destination and source are relative to the output file
-		dstPath := filepath.Join(filepath.Dir(outputFile), dst)
-		srcPath := filepath.Join(filepath.Dir(outputFile), src)
-
-		if !dryRun {
-			err := utils.RemoveSchemaExtensions(srcPath, dstPath)
+			err = files.writeFile(file, text)
 			if err != nil {
 				panic(err)
 			}
-		} else {
-			//fmt.Printf(`remove_oas_schema_extensions("%s", "%s")\n`, dstPath, srcPath)
 		}
 
-		return dst
+		return file
 	}
 
-	return _removeOASExtensionsFunc
+	return _transformOASFileFunc
 }
 
 func convertOAS3ToMCPValues(args ...any) map[string]any {
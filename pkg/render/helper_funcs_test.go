@@ -53,7 +53,7 @@ func Test_getOSCopyFileFunc(t *testing.T) {
 			outputFile := filepath.Join(testDataDir, tt.outputFile)
 
 			//create instance of the copy function
-			copyFunc := getOSCopyFileFunc(templateFile, outputFile, false)
+			copyFunc := getOSCopyFileFunc(outputDirFiles(outputFile, false), templateFile)
 
 			//invoke the actual copy function
 			dstRes := copyFunc(tt.dst, tt.src)
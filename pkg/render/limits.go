@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// LimitError is returned when rendering goes above CommonFlags.MaxOutputSize, or is still running at CommonFlags.Deadline
+type LimitError struct {
+	// Timeout is true when the deadline passed, otherwise the output was too large
+	Timeout bool
+
+	message string
+}
+
+func (e *LimitError) Error() string {
+	return e.message
+}
+
+// renderLimits bounds the output and duration of a render. A nil *renderLimits has no limits.
+type renderLimits struct {
+	maxSize  int64
+	deadline time.Time
+}
+
+func newRenderLimits(cFlags *CommonFlags) *renderLimits {
+	if cFlags.MaxOutputSize <= 0 && cFlags.Deadline.IsZero() {
+		return nil
+	}
+	return &renderLimits{maxSize: cFlags.MaxOutputSize, deadline: cFlags.Deadline}
+}
+
+// check fails once the deadline passed, or if size is above the maximum size
+func (l *renderLimits) check(size int64) error {
+	if l == nil {
+		return nil
+	}
+	if !l.deadline.IsZero() && time.Now().After(l.deadline) {
+		return &LimitError{Timeout: true, message: "rendering did not complete in time"}
+	}
+	if l.maxSize > 0 && size > l.maxSize {
+		return &LimitError{message: fmt.Sprintf("rendered output is larger than %d bytes", l.maxSize)}
+	}
+	return nil
+}
+
+// writer returns a writer into buffer that checks the limits before each write.
+// Templates write their output as they go, so this stops runaway loops in the middle of them.
+func (l *renderLimits) writer(buffer *bytes.Buffer) io.Writer {
+	if l == nil {
+		return buffer
+	}
+	return &limitedWriter{buffer: buffer, limits: l}
+}
+
+// wrapFuncs limits the sprig functions that create lists and strings of arbitrary size, before anything is written.
+// Their results are bounded by the maximum output size.
+func (l *renderLimits) wrapFuncs(funcs map[string]any) {
+	if l == nil || l.maxSize <= 0 {
+		return
+	}
+
+	until := funcs["until"].(func(int) []int)
+	funcs["until"] = func(count int) ([]int, error) {
+		if err := l.check(int64(max(count, -count))); err != nil {
+			return nil, err
+		}
+		return until(count), nil
+	}
+
+	untilStep := funcs["untilStep"].(func(int, int, int) []int)
+	funcs["untilStep"] = func(start, stop, step int) ([]int, error) {
+		if step != 0 {
+			if err := l.check(int64(max((stop-start)/step, 0))); err != nil {
+				return nil, err
+			}
+		}
+		return untilStep(start, stop, step), nil
+	}
+
+	funcs["repeat"] = func(count int, str string) (string, error) {
+		if err := l.check(int64(count) * int64(len(str))); err != nil {
+			return "", err
+		}
+		return strings.Repeat(str, count), nil
+	}
+}
+
+type limitedWriter struct {
+	buffer *bytes.Buffer
+	limits *renderLimits
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.limits.check(int64(w.buffer.Len() + len(p))); err != nil {
+		return 0, err
+	}
+	return w.buffer.Write(p)
+}
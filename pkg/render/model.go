@@ -21,29 +21,72 @@ import (
 	"github.com/apigee/apigee-go-gen/pkg/flags"
 	"github.com/apigee/apigee-go-gen/pkg/git"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
 	"github.com/go-errors/errors"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sync"
 )
 
 func GenerateBundle(createModelFunc func(string) (v1.Model, error), cFlags *CommonFlags, validate bool, dryRun string, debug bool) error {
-	var err error
+	_, err := GenerateBundleWarnings(createModelFunc, cFlags, validate, dryRun, debug)
+	return err
+}
 
+// GenerateBundleWarnings is like GenerateBundle, and returns the validation warnings (see v1.ModelWarnings), annotated with their location in the template
+func GenerateBundleWarnings(createModelFunc func(string) (v1.Model, error), cFlags *CommonFlags, validate bool, dryRun string, debug bool) ([]error, error) {
 	bundleOutputFile := cFlags.OutputFile
 
-	if git.IsGitURI(string(cFlags.TemplateFile)) {
-		var templateFileFromGit string
-		var templateDirFromGit string
-		if templateFileFromGit, templateDirFromGit, err = git.FetchFile(string(cFlags.TemplateFile)); err != nil {
-			return err
+	var warnings []error
+	err := generateModel(createModelFunc, cFlags, debug, func(model v1.Model, annotate func(error) error) error {
+		var err error
+		warnings, err = createBundle(model, string(bundleOutputFile), validate, dryRun, bool(cFlags.SHA256Manifest), gitSourceComments(cFlags.GitSources), annotate)
+		return err
+	})
+	return warnings, err
+}
+
+// GenerateModel is like GenerateBundle, but it returns the model instead of writing the bundle (validation warnings are not returned)
+func GenerateModel(createModelFunc func(string) (v1.Model, error), cFlags *CommonFlags, validate bool) (v1.Model, error) {
+	var result v1.Model
+	err := generateModel(createModelFunc, cFlags, false, func(model v1.Model, annotate func(error) error) error {
+		if validate {
+			if _, err := validateModel(model, annotate); err != nil {
+				return err
+			}
 		}
-		defer utils.LenientRemoveAll(templateDirFromGit)
-		cFlags.TemplateFile = flags.String(templateFileFromGit)
-		fileRelative, _ := filepath.Rel(templateDirFromGit, templateFileFromGit)
-		cFlags.TemplateFileAlias = flags.String(fileRelative)
+		result = model
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	templateDir := filepath.Dir(string(cFlags.TemplateFile))
+	return result, nil
+}
+
+// generateModel renders the template, creates the model from it, and passes it to the use function
+// (before the rendered files are removed), along with a function that annotates validation errors with their location in the template.
+// The template is rendered the same way as GenerateBundleFS (see renderModel), with the files written by the template
+// (and the rendered template itself) going into a copy of the template directory.
+// In debug mode, the rendered template is printed, and the use function is not called.
+func generateModel(createModelFunc func(string) (v1.Model, error), cFlags *CommonFlags, debug bool, use func(model v1.Model, annotate func(error) error) error) error {
+	var err error
+	renderFlags := *cFlags
+
+	if git.IsGitURI(string(renderFlags.TemplateFile)) {
+		var checkout *git.Checkout
+		if checkout, err = git.Fetch(string(renderFlags.TemplateFile)); err != nil {
+			return err
+		}
+		defer utils.LenientRemoveAll(checkout.Dir)
+		renderFlags.GitSources.Add(string(renderFlags.TemplateFile), checkout.Ref, checkout.Commit)
+		renderFlags.TemplateFile = flags.String(checkout.File)
+		fileRelative, _ := filepath.Rel(checkout.Dir, checkout.File)
+		renderFlags.TemplateFileAlias = flags.String(fileRelative)
+	}
 
 	var tmpDir string
 	//copy the template directory into temporary location for rendering into
@@ -52,97 +95,245 @@ func GenerateBundle(createModelFunc func(string) (v1.Model, error), cFlags *Comm
 	}
 	defer utils.LenientRemoveAll(tmpDir)
 
-	if err = utils.CopyDir(tmpDir, templateDir); err != nil {
+	if err = utils.CopyDir(tmpDir, filepath.Dir(string(renderFlags.TemplateFile))); err != nil {
 		return errors.New(err)
 	}
 
-	var tempRenderedFile *os.File
-	if tempRenderedFile, err = os.CreateTemp(tmpDir, fmt.Sprintf("rendered-*-template.yaml")); err != nil {
-		return errors.New(err)
+	files := outputDirFiles(filepath.Join(tmpDir, renderedTemplateFile), false)
+	model, annotate, err := renderModel(files, tmpDir, func(_ fs.FS, input string) (v1.Model, error) {
+		return createModelFunc(input)
+	}, &renderFlags, debug, "rendered template appears to not be valid YAML. Use --debug=true flag to inspect rendered output")
+	if err != nil || debug {
+		return err
 	}
 
-	defer utils.MustClose(tempRenderedFile)
+	return use(model, annotate)
+}
 
-	// render the template to a temporary location
-	cFlags.OutputFile = flags.String(tempRenderedFile.Name())
-	if err = RenderGenericTemplateLocal(cFlags, false); err != nil {
-		return err
+// renderModel renders the template, and creates the model from the rendered template, which is written into outputDir
+// (next to the files written by the template). It returns the model, along with a function that annotates validation errors
+// with their location in the template. Both GenerateBundle and GenerateBundleFS render through it, with different templateFiles.
+// In debug mode, the rendered template is printed, and no model is created.
+func renderModel(files *templateFiles, outputDir string, createModelFunc func(fs.FS, string) (v1.Model, error), cFlags *CommonFlags, debug bool, invalidYAMLMessage string) (v1.Model, func(error) error, error) {
+	fsys := files.fsys
+	templateFile := string(cFlags.TemplateFile)
+
+	if err := validateValuesInDir(fsys, utils.FSDir(fsys, templateFile), *cFlags.Values); err != nil {
+		return nil, nil, err
 	}
 
-	rendered, err := os.ReadFile(tempRenderedFile.Name())
+	context := &TemplateContext{
+		Values: *cFlags.Values,
+	}
+
+	rendered, err := renderGeneric(files, context, cFlags)
 	if err != nil {
-		return errors.New(err)
+		return nil, nil, err
 	}
 
 	if debug {
 		fmt.Println(string(rendered))
-		return nil
+		return nil, nil, nil
 	}
 
-	rendered, err = ResolveYAML(rendered, tempRenderedFile.Name())
-	if err != nil {
-		return utils.MultiError{
-			Errors: []error{
-				err,
-				errors.New("rendered template appears to not be valid YAML. Use --debug=true flag to inspect rendered output")}}
+	sourceMap := lazySourceMap(cFlags, func(sourceMapFlags *CommonFlags) error {
+		_, err := renderGeneric(files, context, sourceMapFlags)
+		return err
+	})
+
+	// the rendered template is written as is, so that line numbers in errors match the --debug output
+	if err = files.writeFile(renderedTemplateFile, rendered); err != nil {
+		return nil, nil, err
 	}
+	renderedFilePath := utils.FSJoin(fsys, outputDir, renderedTemplateFile)
 
-	err = os.WriteFile(tempRenderedFile.Name(), rendered, os.ModePerm)
-	if err != nil {
-		return errors.New(err)
+	if _, err = utils.FileText2YAMLFS(fsys, bytes.NewReader(rendered), renderedFilePath); err != nil {
+		return nil, nil, utils.MultiError{
+			Errors: []error{
+				sourceMap().annotateYAMLError(err),
+				errors.New(invalidYAMLMessage)}}
 	}
 
 	// create apiproxy from rendered template
-	model, err := createModelFunc(tempRenderedFile.Name())
+	model, err := createModelFunc(fsys, renderedFilePath)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	templateName := string(cFlags.TemplateFileAlias)
+	if templateName == "" {
+		templateName = templateFile
+	}
+	renderedFile := fmt.Sprintf("%s (rendered)", templateName)
+	if positions := v1.ModelPositions(model); positions != nil {
+		positions.File = renderedFile
 	}
-	return CreateBundle(model, string(bundleOutputFile), validate, dryRun)
+
+	annotate := func(err error) error {
+		return sourceMap().annotatePositionErrors(err, renderedFile)
+	}
+	return model, annotate, nil
 }
 
-func CreateBundle(model v1.Model, output string, validate bool, dryRun string) (err error) {
+// lazySourceMap returns the source map of the rendered template, if it was requested within cFlags.
+// Otherwise, the template is rendered again to make the source map the first time it is needed,
+// so that templates without errors are not slowed down by source maps.
+func lazySourceMap(cFlags *CommonFlags, render func(sourceMapFlags *CommonFlags) error) func() SourceMap {
+	return sync.OnceValue(func() SourceMap {
+		if cFlags.SourceMap != nil {
+			return *cFlags.SourceMap
+		}
+
+		var sourceMap SourceMap
+		sourceMapFlags := *cFlags
+		sourceMapFlags.SourceMap = &sourceMap
+		if err := render(&sourceMapFlags); err != nil {
+			return nil
+		}
+		return sourceMap
+	})
+}
+
+// Bundle is an API proxy or shared flow bundle generated in memory
+type Bundle struct {
+	Model v1.Model
+
+	// Files maps the slash-separated path of each bundle file (e.g. "apiproxy/policies/AM-Example.xml") to its contents
+	Files map[string][]byte
+
+	// Warnings are the validation warnings, annotated with their location in the template
+	Warnings []error
+}
+
+// Zip returns the bundle as zip bytes
+func (b *Bundle) Zip() ([]byte, error) {
+	return zip.ZipFiles(b.Files)
+}
+
+// Manifest returns the SHA-256 manifest of the bundle files (see v1.BundleManifest)
+func (b *Bundle) Manifest() []byte {
+	return v1.BundleManifest(b.Files)
+}
+
+// renderedTemplateFile is the name of the rendered template, within the directory the template writes files to
+const renderedTemplateFile = "rendered-template.yaml"
+
+// GenerateBundleFS is like GenerateBundle, but it does not touch the local disk.
+// The template file, include list, values schema, and the files used by the template (e.g. os_copyfile sources and resources)
+// are read from fsys, using slash-separated paths. Files written by the template (e.g. os_writefile) are kept in memory.
+// The values must be already set in cFlags.Values, and cFlags.OutputFile is ignored.
+func GenerateBundleFS(fsys fs.FS, createModelFunc func(fs.FS, string) (v1.Model, error), cFlags *CommonFlags, validate bool) (*Bundle, error) {
+	renderFlags := *cFlags
+	renderFlags.TemplateFile = flags.String(path.Clean(string(cFlags.TemplateFile)))
+	templateDir := path.Dir(string(renderFlags.TemplateFile))
+
+	// render the template in memory, next to the main template (same as GenerateBundle)
+	files, _ := memoryFiles(fsys, templateDir)
+	model, annotate, err := renderModel(files, templateDir, createModelFunc, &renderFlags, false, "rendered template appears to not be valid YAML")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	bundle := &Bundle{Model: model}
+	if validate {
+		if bundle.Warnings, err = validateModel(model, annotate); err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Files, err = v1.Model2BundleFiles(model); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func CreateBundle(model v1.Model, output string, validate bool, dryRun string) (err error) {
+	_, err = CreateBundleManifest(model, output, validate, dryRun, false)
+	return err
+}
+
+// CreateBundleManifest is like CreateBundle, and if manifest is true, it also writes the SHA-256 manifest of the bundle files next to the output.
+// It returns the validation warnings (see v1.ModelWarnings).
+func CreateBundleManifest(model v1.Model, output string, validate bool, dryRun string, manifest bool) ([]error, error) {
+	return createBundle(model, output, validate, dryRun, manifest, nil, func(err error) error { return err })
+}
+
+// createBundle is like CreateBundleManifest, and passes validation errors and warnings through the annotate function.
+// The comments are written to the manifest (if any).
+func createBundle(model v1.Model, output string, validate bool, dryRun string, manifest bool, comments []string, annotate func(error) error) (warnings []error, err error) {
 	if dryRun == "xml" {
 		xmlText, err := model.XML()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		fmt.Println(string(xmlText))
 	} else if dryRun == "yaml" {
 		yamlText, err := model.YAML()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		fmt.Println(string(yamlText))
 	}
 
 	if validate {
-		err = model.Validate()
-		if err != nil {
-			return err
+		if warnings, err = validateModel(model, annotate); err != nil {
+			return nil, err
 		}
 	}
 
 	if dryRun != "" {
-		return nil
+		return warnings, nil
 	}
 
 	err = v1.Model2Bundle(model, output)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if manifest {
+		err = v1.Model2BundleManifest(model, output+v1.ManifestExtension, comments...)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return warnings, nil
 }
 
-func ResolveYAML(text []byte, filePath string) ([]byte, error) {
-	popd := utils.PushDir(filepath.Dir(filePath))
-	defer popd()
+// validateModel passes the validation errors and warnings through the annotate function, and returns the warnings
+func validateModel(model v1.Model, annotate func(error) error) ([]error, error) {
+	if err := model.Validate(); err != nil {
+		return nil, annotate(err)
+	}
+
+	var warnings []error
+	for _, warning := range v1.ModelWarnings(model) {
+		warnings = append(warnings, annotate(warning))
+	}
+	return warnings, nil
+}
 
-	yaml, err := utils.Text2YAML(bytes.NewReader(text))
+// PrintWarnings prints the validation warnings to stderr
+func PrintWarnings(warnings []error) {
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %s\n", warning.Error())
+	}
+}
+
+// gitSourceComments records the commit each git ref resolved to within the manifest, for reproducibility
+// (e.g. "git https://github.com/my-org/templates/blob/main/apiproxy.yaml main 0123abcd...")
+func gitSourceComments(sources *git.Sources) []string {
+	comments := []string{}
+	for _, source := range sources.List() {
+		comments = append(comments, fmt.Sprintf("git %s %s %s", source.URI, source.Ref, source.Commit))
+	}
+	return comments
+}
+
+// ResolveYAML resolves the JSON $refs within the YAML text, relative to the directory of the file it came from
+func ResolveYAML(text []byte, filePath string) ([]byte, error) {
+	yaml, err := utils.FileText2YAML(bytes.NewReader(text), filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -19,10 +19,15 @@ import (
 	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
 	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
 	"github.com/stretchr/testify/require"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func TestGenerateBundle(t *testing.T) {
@@ -72,3 +77,260 @@ func TestGenerateBundle(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateBundle_Concurrent(t *testing.T) {
+	// run with -race, bundles are generated without changing the working directory, so this is safe
+	const bundles = 8
+
+	templateDir := func(i int) string {
+		dir := filepath.Join(t.TempDir(), fmt.Sprintf("bundle-%d", i))
+		files := map[string]string{
+			"apiproxy.yaml": `APIProxy:
+  .revision: 1
+  .name: {{ .Values.name }}
+Policies:
+  $ref: ./policies.yaml#/
+ProxyEndpoints: []
+TargetEndpoints: []
+Resources:
+  - Resource:
+      Type: jsc
+      Path: ./scripts/script.js
+`,
+			"policies.yaml": fmt.Sprintf(`- Javascript:
+    .name: JS-Bundle
+    ResourceURL: jsc://script.js
+    DisplayName: bundle-%d
+`, i),
+			"values.yaml":       fmt.Sprintf("name: bundle-%d\n", i),
+			"scripts/script.js": fmt.Sprintf("print('bundle-%d');\n", i),
+		}
+		for name, contents := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+		}
+
+		// use relative paths, so that they would resolve differently if the working directory changed
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		dir, err = filepath.Rel(wd, dir)
+		require.NoError(t, err)
+		return dir
+	}
+
+	dirs := make([]string, bundles)
+	for i := 0; i < bundles; i++ {
+		dirs[i] = templateDir(i)
+	}
+
+	outputDir := t.TempDir()
+	errs := make([]error, bundles)
+
+	var wg sync.WaitGroup
+	for i := 0; i < bundles; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			cFlags := NewCommonFlags()
+			cFlags.TemplateFile = flags.String(filepath.Join(dirs[i], "apiproxy.yaml"))
+			cFlags.OutputFile = flags.String(filepath.Join(outputDir, fmt.Sprintf("apiproxy-%d.zip", i)))
+
+			values := flags.NewValues(cFlags.Values)
+			if errs[i] = values.Set(filepath.Join(dirs[i], "values.yaml")); errs[i] != nil {
+				return
+			}
+
+			createModelFunc := func(input string) (v1.Model, error) {
+				return v1.NewAPIProxyModel(input)
+			}
+			errs[i] = GenerateBundle(createModelFunc, cFlags, false, "", false)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < bundles; i++ {
+		require.NoError(t, errs[i])
+
+		bundle, err := zip.BytesFS(utils.MustReadFileBytes(filepath.Join(outputDir, fmt.Sprintf("apiproxy-%d.zip", i))))
+		require.NoError(t, err)
+
+		script, err := fs.ReadFile(bundle, "apiproxy/resources/jsc/script.js")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("print('bundle-%d');\n", i), string(script))
+
+		policy, err := fs.ReadFile(bundle, "apiproxy/policies/JS-Bundle.xml")
+		require.NoError(t, err)
+		require.Contains(t, string(policy), fmt.Sprintf("<DisplayName>bundle-%d</DisplayName>", i))
+
+		proxy, err := fs.ReadFile(bundle, fmt.Sprintf("apiproxy/bundle-%d.xml", i))
+		require.NoError(t, err)
+		require.Contains(t, string(proxy), fmt.Sprintf(`name="bundle-%d"`, i))
+	}
+}
+
+func TestGenerateBundle_Reproducible(t *testing.T) {
+	templateDir := filepath.Join("testdata", "render", "policies")
+	outputDir := t.TempDir()
+
+	generate := func(outputFile string) {
+		cFlags := NewCommonFlags()
+		cFlags.TemplateFile = flags.String(filepath.Join(templateDir, "apiproxy.yaml"))
+		cFlags.OutputFile = flags.String(outputFile)
+		cFlags.SHA256Manifest = true
+		values := flags.NewValues(cFlags.Values)
+		require.NoError(t, values.Set(filepath.Join(templateDir, "values.yaml")))
+
+		err := GenerateBundle(func(input string) (v1.Model, error) {
+			return v1.NewAPIProxyModel(input)
+		}, cFlags, false, "", false)
+		require.NoError(t, err)
+	}
+
+	firstZip := filepath.Join(outputDir, "first", "apiproxy.zip")
+	secondZip := filepath.Join(outputDir, "second", "apiproxy.zip")
+	generate(firstZip)
+	generate(secondZip)
+
+	// identical input produces identical zip bytes and manifest
+	require.Equal(t, utils.MustReadFileBytes(firstZip), utils.MustReadFileBytes(secondZip))
+	manifest := utils.MustReadFileBytes(firstZip + v1.ManifestExtension)
+	require.Equal(t, manifest, utils.MustReadFileBytes(secondZip+v1.ManifestExtension))
+	require.Regexp(t, `(?m)^[0-9a-f]{64}  apiproxy/policies/`, string(manifest))
+}
+
+func TestGenerateBundleFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/example/apiproxy.yaml": {Data: []byte(`APIProxy:
+  .revision: 1
+  .name: {{ .Values.name }}
+Policies: []
+ProxyEndpoints: []
+TargetEndpoints: []
+Resources:
+  #{{ os_writefile "./config.json" (include "config" .) }}
+  - Resource:
+      Type: properties
+      Path: ./config.json
+  #{{ os_copyfile "./script.js" "./files/script.js" }}
+  - Resource:
+      Type: jsc
+      Path: ./script.js
+`)},
+		"templates/example/_helpers.tmpl":      {Data: []byte(`{{- define "config" -}}{"name": "{{ .Values.name }}"}{{- end -}}`)},
+		"templates/example/files/script.js":    {Data: []byte(`print("hello");`)},
+		"templates/example/values.schema.yaml": {Data: []byte("type: object\nrequired: [name]\n")},
+	}
+
+	createModelFunc := func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewAPIProxyModelFS(fsys, input)
+	}
+
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String("./templates/example/apiproxy.yaml")
+
+	_, err := GenerateBundleFS(fsys, createModelFunc, cFlags, true)
+	require.ErrorContains(t, err, "values do not match the schema in templates/example/values.schema.yaml")
+
+	cFlags.Values.Set("name", "hello-world")
+	bundle, err := GenerateBundleFS(fsys, createModelFunc, cFlags, true)
+	require.NoError(t, err)
+
+	require.Equal(t, "hello-world", bundle.Model.Name())
+	require.Equal(t, `{"name": "hello-world"}`, string(bundle.Files["apiproxy/resources/properties/config.json"]))
+	require.Equal(t, `print("hello");`, string(bundle.Files["apiproxy/resources/jsc/script.js"]))
+	require.Contains(t, bundle.Files, "apiproxy/hello-world.xml")
+
+	// nothing is written back into the template file system
+	require.Len(t, fsys, 4)
+}
+
+func TestGenerateBundleFS_SameAsGenerateBundle(t *testing.T) {
+	templateDir := filepath.Join("testdata", "render", "policies")
+	outputDir := t.TempDir()
+
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(filepath.Join(templateDir, "apiproxy.yaml"))
+	cFlags.OutputFile = flags.String(filepath.Join(outputDir, "exp-apiproxy.zip"))
+	values := flags.NewValues(cFlags.Values)
+	require.NoError(t, values.Set(filepath.Join(templateDir, "values.yaml")))
+
+	err := GenerateBundle(func(input string) (v1.Model, error) {
+		return v1.NewAPIProxyModel(input)
+	}, cFlags, false, "", false)
+	require.NoError(t, err)
+
+	cFlags.TemplateFile = "apiproxy.yaml"
+	bundle, err := GenerateBundleFS(os.DirFS(templateDir), func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewAPIProxyModelFS(fsys, input)
+	}, cFlags, false)
+	require.NoError(t, err)
+
+	zipBytes, err := bundle.Zip()
+	require.NoError(t, err)
+	outputFile := filepath.Join(outputDir, "out-apiproxy.zip")
+	require.NoError(t, os.WriteFile(outputFile, zipBytes, os.ModePerm))
+
+	utils.RequireBundleZipEquals(t, filepath.Join(outputDir, "exp-apiproxy.zip"), outputFile)
+}
+
+func TestGenerateBundleWarnings(t *testing.T) {
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "apiproxy.yaml"), []byte(`APIProxy:
+  .revision: 1
+  .name: warnings
+Policies:
+  - AssignMessage:
+      .name: AM-Hello
+  - AssignMessage:
+      .name: AM-Unused
+ProxyEndpoints:
+  - ProxyEndpoint:
+      .name: default
+      PreFlow:
+        .name: PreFlow
+        Request:
+          - Step:
+              Name: AM-Hello
+      HTTPProxyConnection:
+        BasePath: /v1
+TargetEndpoints: []
+`), os.ModePerm))
+
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(filepath.Join(templateDir, "apiproxy.yaml"))
+	outputFile := filepath.Join(t.TempDir(), "apiproxy.zip")
+	cFlags.OutputFile = flags.String(outputFile)
+
+	// the warnings are returned to the caller (instead of printed), annotated with their location in the template
+	warnings, err := GenerateBundleWarnings(func(input string) (v1.Model, error) {
+		return v1.NewAPIProxyModel(input)
+	}, cFlags, true, "", false)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorContains(t, warnings[0], `policy "AM-Unused"`)
+	require.ErrorContains(t, warnings[0], "from "+filepath.Join(templateDir, "apiproxy.yaml")+":7")
+	require.FileExists(t, outputFile)
+}
+
+func TestGenerateBundle_Debug(t *testing.T) {
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "apiproxy.yaml"), []byte("APIProxy: [\n"), os.ModePerm))
+
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(filepath.Join(templateDir, "apiproxy.yaml"))
+	outputFile := filepath.Join(t.TempDir(), "apiproxy.zip")
+	cFlags.OutputFile = flags.String(outputFile)
+
+	createModelFunc := func(input string) (v1.Model, error) {
+		return v1.NewAPIProxyModel(input)
+	}
+
+	// the rendered template is printed as is, without resolving it, or creating the bundle
+	err := GenerateBundle(createModelFunc, cFlags, true, "", true)
+	require.NoError(t, err)
+	require.NoFileExists(t, outputFile)
+
+	err = GenerateBundle(createModelFunc, cFlags, true, "", false)
+	require.ErrorContains(t, err, "rendered template appears to not be valid YAML")
+}
@@ -19,9 +19,13 @@ import (
 	"fmt"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/values"
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/go-errors/errors"
 	"github.com/gosimple/slug"
+	"io/fs"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -45,14 +49,7 @@ func RenderGeneric(context any, cFlags *CommonFlags, dryRun bool) error {
 		}
 	}
 
-	//create the template
-	tmpl, err := CreateTemplate(string(cFlags.TemplateFile), string(cFlags.TemplateFileAlias), cFlags.IncludeList, string(cFlags.OutputFile), dryRun)
-	if err != nil {
-		return err
-	}
-
-	//render the template
-	rendered, err := RenderTemplate(tmpl, context)
+	rendered, err := renderGeneric(outputDirFiles(string(cFlags.OutputFile), dryRun), context, cFlags)
 	if err != nil {
 		return err
 	}
@@ -75,51 +72,135 @@ func RenderGeneric(context any, cFlags *CommonFlags, dryRun bool) error {
 	return nil
 }
 
+// renderGeneric renders the template with files read from, and written to the given templateFiles
+func renderGeneric(files *templateFiles, context any, cFlags *CommonFlags) ([]byte, error) {
+	var mapper *sourceMapper
+	if cFlags.SourceMap != nil {
+		mapper = newSourceMapper()
+	}
+
+	limits := newRenderLimits(cFlags)
+
+	//create the template
+	tmpl, err := createTemplate(files, string(cFlags.TemplateFile), string(cFlags.TemplateFileAlias), cFlags.IncludeList, bool(cFlags.Strict), cFlags.Sandbox, cFlags.GitSources, mapper, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	//render the template
+	rendered, sourceMap, err := renderTemplate(tmpl, context, mapper, limits)
+	if err != nil {
+		return nil, err
+	}
+	if cFlags.SourceMap != nil {
+		*cFlags.SourceMap = sourceMap
+	}
+
+	if cFlags.Strict {
+		if err = checkNoValue(tmpl.Name(), rendered); err != nil {
+			return nil, err
+		}
+	}
+
+	return rendered, nil
+}
+
 func RenderTemplate(tmpl *template.Template, context any) ([]byte, error) {
+	rendered, _, err := renderTemplate(tmpl, context, nil, nil)
+	return rendered, err
+}
+
+// renderTemplate is like RenderTemplate, and returns the source map of the rendered output (the template must have been created with the same source mapper, nil disables source maps).
+// It stops rendering once it goes above the limits
+func renderTemplate(tmpl *template.Template, context any, mapper *sourceMapper, limits *renderLimits) ([]byte, SourceMap, error) {
 	renderedBytes := bytes.Buffer{}
-	err := tmpl.Execute(&renderedBytes, context)
+	err := tmpl.Execute(limits.writer(&renderedBytes), context)
 	if err != nil {
-		return nil, errors.New(err)
+		var limitError *LimitError
+		if errors.As(err, &limitError) {
+			return nil, nil, limitError
+		}
+		return nil, nil, errors.New(err)
+	}
+
+	var sourceMap SourceMap
+	rendered := renderedBytes.Bytes()
+	if mapper != nil {
+		var text string
+		text, sourceMap = mapper.finish(string(rendered))
+		rendered = []byte(text)
 	}
 
 	//remove empty lines with just #
 	regex := regexp.MustCompile(`(?ms)^\s*#\s*$[\r\n]*`)
-	replaced := regex.ReplaceAll(renderedBytes.Bytes(), []byte{})
+	replaced := regex.ReplaceAll(rendered, []byte{})
 
 	//remove lines with just # // a comment
 	regex = regexp.MustCompile(`(?m)^\s*#\s*//.+$[\r\n]*`)
 	replaced = regex.ReplaceAll(replaced, []byte{})
-	return replaced, nil
+
+	if sourceMap != nil {
+		sourceMap = sourceMap.realign(string(rendered), string(replaced))
+	}
+	return replaced, sourceMap, nil
 }
 
 func CreateTemplate(templateFile string, templateFileAlias string, includeList []string, outputFile string, dryRun bool) (*template.Template, error) {
+	return CreateTemplateOptions(templateFile, templateFileAlias, includeList, outputFile, dryRun, TemplateOptions{})
+}
+
+// TemplateOptions are the settings of CreateTemplateOptions, the zero value is the same as CreateTemplate
+type TemplateOptions struct {
+	// Strict fails on missing keys and missing named templates (see CommonFlags.Strict)
+	Strict bool
+}
+
+// CreateTemplateOptions is like CreateTemplate, with the given options
+func CreateTemplateOptions(templateFile string, templateFileAlias string, includeList []string, outputFile string, dryRun bool, options TemplateOptions) (*template.Template, error) {
+	return createTemplate(outputDirFiles(outputFile, dryRun), templateFile, templateFileAlias, includeList, options.Strict, false, nil, nil, nil)
+}
+
+// hostEnvFuncs are the sprig functions that read the host environment, they are removed when rendering in a sandbox
+var hostEnvFuncs = []string{"env", "expandenv", "getHostByName"}
+
+func createTemplate(files *templateFiles, templateFile string, templateFileAlias string, includeList []string, strict bool, sandbox bool, gitSources *git.Sources, mapper *sourceMapper, limits *renderLimits) (*template.Template, error) {
 	var err error
 	var includeMatches []string
 
-	if _, err = os.Stat(templateFile); err != nil {
+	fsys := files.fsys
+	if _, err = fs.Stat(fsys, templateFile); err != nil {
 		return nil, errors.New(err)
 	}
 
 	// add helper files if present
-	templateDir := filepath.Dir(templateFile)
+	templateDir := utils.FSDir(fsys, templateFile)
 
+	includeList = slices.Clone(includeList)
 	helpersFileNames := []string{"_helpers.tpl", "_helpers.tmpl"}
 	for _, helperFileName := range helpersFileNames {
-		helperFilePath := filepath.Join(templateDir, helperFileName)
-		if _, err = os.Stat(helperFilePath); err == nil {
+		helperFilePath := utils.FSJoin(fsys, templateDir, helperFileName)
+		if _, err = fs.Stat(fsys, helperFilePath); err == nil {
 			includeList = append(includeList, helperFilePath)
 		}
 	}
 
-	//add the main template itself
-	includeList = append(includeList, templateFile)
-
-	if includeMatches, err = ExpandInclude(includeList); err != nil {
+	if includeMatches, err = expandInclude(fsys, includeList, gitSources); err != nil {
 		return nil, err
 	}
 
+	//add the main template itself (not as a pattern, its name may contain template actions when rendering a directory)
+	includeMatches = append(includeMatches, templateFile)
+
 	helperFuncs := map[string]any{}
 
+	sprigFuncs := sprig.FuncMap()
+	if sandbox {
+		for _, name := range hostEnvFuncs {
+			delete(sprigFuncs, name)
+		}
+	}
+	limits.wrapFuncs(sprigFuncs)
+
 	blankFunc := func(args ...any) string {
 		return ""
 	}
@@ -196,16 +277,12 @@ func CreateTemplate(templateFile string, templateFileAlias string, includeList [
 			panic("os_writefile path must not use ..")
 		}
 
-		filePath := filepath.Join(filepath.Dir(outputFile), fileName)
 		data := args[1].(string)
-		var err error
-		if !dryRun {
-			err = os.WriteFile(filePath, []byte(data), os.ModePerm)
+		if files.writeFile != nil {
+			err := files.writeFile(fileName, []byte(data))
 			if err != nil {
 				panic(err)
 			}
-		} else {
-			//fmt.Printf(`os_writefile(%s, []byte{%v})\n`, filePath, len(data))
 		}
 		return fileName
 	}
@@ -233,17 +310,19 @@ func CreateTemplate(templateFile string, templateFileAlias string, includeList [
 		var err error
 		var templateBytes []byte
 		var templateName string
+		var sourceName string
 
 		parentTemplateIndex := slices.IndexFunc(includeStack, func(elem string) bool {
 			return strings.Index(elem, "file:") == 0
 		})
 
 		parentTemplateFile := strings.TrimPrefix(includeStack[parentTemplateIndex], "file:")
-		targetTemplateFile := filepath.Join(filepath.Dir(parentTemplateFile), arg0)
+		targetTemplateFile := utils.FSJoin(fsys, utils.FSDir(fsys, parentTemplateFile), arg0)
 
-		if templateBytes, err = os.ReadFile(targetTemplateFile); err == nil {
+		if templateBytes, err = fs.ReadFile(fsys, targetTemplateFile); err == nil {
 			// file was found, use its contents as template
 			templateName = arg0
+			sourceName = includeSourceName(templateFile, templateFileAlias, targetTemplateFile)
 			includeStack = slices.Insert(includeStack, 0, fmt.Sprintf("file:%s", targetTemplateFile))
 
 		} else {
@@ -257,19 +336,28 @@ func CreateTemplate(templateFile string, templateFileAlias string, includeList [
 
 		tpl, err := template.New(templateName).
 			Funcs(helperFuncs).
-			Funcs(sprig.FuncMap()).
+			Funcs(sprigFuncs).
 			Parse(templateText)
 		if err != nil {
 			panic(err)
 		}
 
 		if len(includeMatches) > 0 {
-			tpl, err = tpl.ParseFiles(includeMatches...)
+			tpl, err = parseFiles(fsys, tpl, includeMatches)
 			if err != nil {
 				panic(err)
 			}
 		}
 
+		if strict {
+			if strings.HasPrefix(includeStack[0], "template:") && tpl.Lookup(arg0) == nil {
+				panic(errors.Errorf(`include "%s": there is no file "%s" or named template "%s"`, arg0, targetTemplateFile, arg0))
+			}
+			tpl.Option("missingkey=error")
+			instrumentStrict(tpl)
+		}
+		mapper.instrument(tpl, map[string]string{templateName: sourceName})
+
 		var arg any
 		if len(args) > 1 {
 			//actual argument
@@ -279,34 +367,39 @@ func CreateTemplate(templateFile string, templateFileAlias string, includeList [
 			arg = map[string]any{}
 		}
 		tplOut := bytes.Buffer{}
-		err = tpl.Execute(&tplOut, arg)
+		sourceStart := mapper.beginInclude()
+		err = tpl.Execute(limits.writer(&tplOut), arg)
 		if err != nil {
 			panic(err)
 		}
 
 		includeStack = slices.Delete(includeStack, 0, 1)
 
-		return tplOut.String()
+		return mapper.endInclude(sourceStart, tplOut.String())
 	}
 
 	helperFuncs["include"] = includeFunc
-	helperFuncs["fmt_printf"] = fmtPrintfFunc
 	helperFuncs["os_writefile"] = osWritefileFunc
 	helperFuncs["url_parse"] = urlParseFunc
-	helperFuncs["os_getenv"] = osGetEnvFunc
-	helperFuncs["os_getenvs"] = osGetEnvs
-	helperFuncs["os_copyfile"] = getOSCopyFileFunc(templateFile, outputFile, dryRun)
-	helperFuncs["remove_oas_extensions"] = getRemoveOASExtensions(templateFile, outputFile, dryRun)
-	helperFuncs["remove_oas_schema_extensions"] = getRemoveOASSchemaExtensions(templateFile, outputFile, dryRun)
+	if !sandbox {
+		helperFuncs["fmt_printf"] = fmtPrintfFunc
+		helperFuncs["os_getenv"] = osGetEnvFunc
+		helperFuncs["os_getenvs"] = osGetEnvs
+	}
+	helperFuncs["os_copyfile"] = getOSCopyFileFunc(files, templateFile)
+	helperFuncs["remove_oas_extensions"] = getRemoveOASExtensions(files)
+	helperFuncs["remove_oas_schema_extensions"] = getRemoveOASSchemaExtensions(files)
 	helperFuncs["blank"] = blankFunc
 	helperFuncs["deref"] = derefFunc
 	helperFuncs["slug_make"] = slugMakeFunc
 	helperFuncs["oas3_to_mcp"] = convertOAS3ToMCPValues
 	helperFuncs["yaml_to_json"] = convertYAMLTextToJSON
 	helperFuncs["json_to_yaml"] = convertJSONToYAML
+	helperFuncs[strictValueFunc] = strictValue
+	helperFuncs[sourceMarkerFunc] = mapper.marker
 
 	var templateText []byte
-	if templateText, err = os.ReadFile(templateFile); err != nil {
+	if templateText, err = fs.ReadFile(fsys, templateFile); err != nil {
 		return nil, errors.New(err)
 	}
 
@@ -316,31 +409,81 @@ func CreateTemplate(templateFile string, templateFileAlias string, includeList [
 
 	tmpl, err := template.New(templateFileAlias).
 		Funcs(helperFuncs).
-		Funcs(sprig.FuncMap()).
+		Funcs(sprigFuncs).
 		Parse(string(templateText))
 	if err != nil {
 		return nil, errors.New(err)
 	}
 
 	if len(includeMatches) > 0 {
-		tmpl, err = tmpl.ParseFiles(includeMatches...)
+		tmpl, err = parseFiles(fsys, tmpl, includeMatches)
 		if err != nil {
 			return nil, errors.New(err)
 		}
 	}
 
+	if strict {
+		tmpl.Option("missingkey=error")
+		instrumentStrict(tmpl)
+	}
+	mapper.instrument(tmpl, nil)
+
 	return tmpl, nil
 }
 
+// parseFiles is like template.ParseFiles, but the files are read from fsys
+func parseFiles(fsys fs.FS, t *template.Template, files []string) (*template.Template, error) {
+	for _, file := range files {
+		text, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		name := filepath.Base(file)
+		tmpl := t
+		if name != t.Name() {
+			tmpl = t.New(name)
+		}
+		if _, err = tmpl.Parse(string(text)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// includeSourceName returns the name of an included file as shown in source maps, relative to the main template
+func includeSourceName(templateFile string, templateFileAlias string, includedFile string) string {
+	relPath, err := filepath.Rel(filepath.Dir(templateFile), includedFile)
+	if err != nil {
+		return includedFile
+	}
+	return filepath.Join(filepath.Dir(templateFileAlias), relPath)
+}
+
 func ExpandInclude(includeTpl flags.IncludeList) ([]string, error) {
+	return expandInclude(utils.OSFS, includeTpl, nil)
+}
+
+func expandInclude(fsys fs.FS, includeTpl []string, gitSources *git.Sources) ([]string, error) {
 	// expand the included templates
 	allMatches := []string{}
 	for _, includePattern := range includeTpl {
+		if fsys == utils.OSFS {
+			// globs in git URIs match files within the fetched repository
+			localPattern, err := git.LocalFile(includePattern, gitSources)
+			if err != nil {
+				return nil, err
+			}
+			includePattern = localPattern
+		}
 		if runtime.GOOS == "windows" {
 			includePattern = filepath.ToSlash(includePattern)
 		}
 		basePath, pattern := doublestar.SplitPattern(includePattern)
-		fSys := os.DirFS(basePath)
+		fSys, err := utils.FSSub(fsys, basePath)
+		if err != nil {
+			return nil, errors.New(err)
+		}
 		matches, err := doublestar.Glob(fSys, pattern)
 		if err != nil {
 			return nil, errors.New(err)
@@ -351,7 +494,7 @@ func ExpandInclude(includeTpl flags.IncludeList) ([]string, error) {
 		}
 
 		for _, match := range matches {
-			newMatch := filepath.Join(basePath, match)
+			newMatch := utils.FSJoin(fsys, basePath, match)
 			allMatches = append(allMatches, newMatch)
 		}
 
@@ -359,3 +502,13 @@ func ExpandInclude(includeTpl flags.IncludeList) ([]string, error) {
 
 	return allMatches, nil
 }
+
+// PrintValues prints the merged values, along with where each value came from
+func PrintValues(data *values.Map, sources values.Sources) error {
+	text, err := values.Print(*data, sources)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(text))
+	return nil
+}
@@ -68,6 +68,14 @@ func TestRenderGenericTemplateLocal(t *testing.T) {
 			"data=./data.json",
 			nil,
 		},
+		{
+			"values-schema",
+			"input.yaml",
+			"values.yaml",
+			"",
+			"",
+			nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.dir, func(t *testing.T) {
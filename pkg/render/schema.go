@@ -0,0 +1,301 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/values"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValuesSchemaFileNames are the schema files looked up next to the main template (same as _helpers.tmpl)
+var ValuesSchemaFileNames = []string{"values.schema.json", "values.schema.yaml", "values.schema.yml"}
+
+// SetFlagExtension lets the schema name the flag that provides a value (e.g. "x-set-flag: set-oas")
+const SetFlagExtension = "x-set-flag"
+
+// FindValuesSchema returns the path to the values schema next to the template, or empty string if there is none
+func FindValuesSchema(templateFile string) string {
+	return findValuesSchemaInDir(utils.OSFS, filepath.Dir(templateFile))
+}
+
+func findValuesSchemaInDir(fsys fs.FS, templateDir string) string {
+	for _, schemaFileName := range ValuesSchemaFileNames {
+		schemaFilePath := utils.FSJoin(fsys, templateDir, schemaFileName)
+		if _, err := fs.Stat(fsys, schemaFilePath); err == nil {
+			return schemaFilePath
+		}
+	}
+	return ""
+}
+
+// LoadValuesSchema reads a JSON Schema from a JSON or YAML file.
+// Local references to "#/$defs/..." or "#/definitions/..." are resolved.
+func LoadValuesSchema(schemaFile string) (*openapi3.Schema, error) {
+	return loadValuesSchema(utils.OSFS, schemaFile)
+}
+
+func loadValuesSchema(fsys fs.FS, schemaFile string) (*openapi3.Schema, error) {
+	text, err := fs.ReadFile(fsys, schemaFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	if filepath.Ext(schemaFile) != ".json" {
+		var data any
+		if err = yaml.Unmarshal(text, &data); err != nil {
+			return nil, errors.Errorf("%s: %s", schemaFile, err.Error())
+		}
+		if text, err = json.Marshal(data); err != nil {
+			return nil, errors.Errorf("%s: %s", schemaFile, err.Error())
+		}
+	}
+
+	schema := &openapi3.Schema{}
+	if err = json.Unmarshal(text, schema); err != nil {
+		return nil, errors.Errorf("%s: %s", schemaFile, err.Error())
+	}
+
+	definitions := map[string]*openapi3.Schema{}
+	for _, defsKey := range []string{"$defs", "definitions"} {
+		raw := struct {
+			Defs map[string]*openapi3.Schema `json:"defs"`
+		}{}
+		rawDefs, found := schema.Extensions[defsKey]
+		if !found {
+			continue
+		}
+		defsText, _ := json.Marshal(map[string]any{"defs": rawDefs})
+		if err = json.Unmarshal(defsText, &raw); err != nil {
+			return nil, errors.Errorf("%s: %s: %s", schemaFile, defsKey, err.Error())
+		}
+		for name, definition := range raw.Defs {
+			definitions[fmt.Sprintf("#/%s/%s", defsKey, name)] = definition
+		}
+	}
+
+	visited := map[*openapi3.Schema]bool{}
+	if err = resolveSchemaRefs(schema, definitions, visited); err != nil {
+		return nil, errors.Errorf("%s: %s", schemaFile, err.Error())
+	}
+	for _, definition := range definitions {
+		if err = resolveSchemaRefs(definition, definitions, visited); err != nil {
+			return nil, errors.Errorf("%s: %s", schemaFile, err.Error())
+		}
+	}
+
+	return schema, nil
+}
+
+func resolveSchemaRefs(schema *openapi3.Schema, definitions map[string]*openapi3.Schema, visited map[*openapi3.Schema]bool) error {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+
+	var schemaRefs []*openapi3.SchemaRef
+	for _, property := range schema.Properties {
+		schemaRefs = append(schemaRefs, property)
+	}
+	schemaRefs = append(schemaRefs, schema.Items, schema.AdditionalProperties.Schema, schema.Not)
+	schemaRefs = append(schemaRefs, schema.AllOf...)
+	schemaRefs = append(schemaRefs, schema.AnyOf...)
+	schemaRefs = append(schemaRefs, schema.OneOf...)
+
+	for _, schemaRef := range schemaRefs {
+		if schemaRef == nil {
+			continue
+		}
+		if schemaRef.Ref != "" {
+			definition, found := definitions[schemaRef.Ref]
+			if !found {
+				return errors.Errorf(`cannot resolve $ref "%s". Only local "#/$defs/..." references are supported`, schemaRef.Ref)
+			}
+			schemaRef.Value = definition
+			continue
+		}
+		if err := resolveSchemaRefs(schemaRef.Value, definitions, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateValues checks the values against the schema, returning one error per missing or mistyped key
+func ValidateValues(schema *openapi3.Schema, data values.Map) error {
+	err := schema.VisitJSON(jsonValue(data), openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+
+	var schemaErrors []error
+	if multiError, ok := err.(openapi3.MultiError); ok {
+		schemaErrors = flattenSchemaErrors(multiError)
+	} else {
+		schemaErrors = []error{err}
+	}
+
+	var errs []error
+	for _, schemaErr := range schemaErrors {
+		errs = append(errs, valuesError(schemaErr))
+	}
+
+	//keep the output stable, VisitJSON walks maps in random order
+	slices.SortStableFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return utils.MultiError{Errors: errs}
+}
+
+// ValidateTemplateValues checks the values against the schema next to the template, if there is one
+func ValidateTemplateValues(templateFile string, data values.Map) error {
+	return validateValuesInDir(utils.OSFS, filepath.Dir(templateFile), data)
+}
+
+func validateValuesInDir(fsys fs.FS, templateDir string, data values.Map) error {
+	schemaFile := findValuesSchemaInDir(fsys, templateDir)
+	if schemaFile == "" {
+		return nil
+	}
+
+	schema, err := loadValuesSchema(fsys, schemaFile)
+	if err != nil {
+		return err
+	}
+
+	if err = ValidateValues(schema, data); err != nil {
+		return utils.MultiError{Errors: []error{
+			err,
+			errors.Errorf("values do not match the schema in %s", schemaFile)}}
+	}
+	return nil
+}
+
+func flattenSchemaErrors(multiError openapi3.MultiError) []error {
+	var result []error
+	for _, err := range multiError {
+		if nested, ok := err.(openapi3.MultiError); ok {
+			result = append(result, flattenSchemaErrors(nested)...)
+			continue
+		}
+		result = append(result, err)
+	}
+	return result
+}
+
+func valuesError(err error) error {
+	schemaError, ok := err.(*openapi3.SchemaError)
+	if !ok {
+		return errors.Errorf("values: %s", err.Error())
+	}
+
+	jsonPointer := schemaError.JSONPointer()
+	key := valuesKey(jsonPointer)
+
+	if schemaError.SchemaField == "required" && len(jsonPointer) > 0 {
+		var propertySchema *openapi3.Schema
+		if propertyRef := schemaError.Schema.Properties[jsonPointer[len(jsonPointer)-1]]; propertyRef != nil {
+			propertySchema = propertyRef.Value
+		}
+		return errors.Errorf(`values: missing key "%s". %s`, key, setFlagHint(key, propertySchema))
+	}
+
+	if key == "" {
+		return errors.Errorf("values: %s", schemaError.Reason)
+	}
+	return errors.Errorf(`values: key "%s" %s. %s`, key, schemaError.Reason, setFlagHint(key, schemaError.Schema))
+}
+
+// setFlagHint suggests the flag that can provide a value, based on the type of value the schema expects
+func setFlagHint(key string, schema *openapi3.Schema) string {
+	flag := "set"
+	if schema != nil {
+		if name, ok := schema.Extensions[SetFlagExtension].(string); ok && name != "" {
+			flag = strings.TrimPrefix(name, "--")
+		} else if schema.Type.Is(openapi3.TypeString) {
+			flag = "set-string"
+		} else if schema.Type.Is(openapi3.TypeObject) || schema.Type.Is(openapi3.TypeArray) {
+			flag = "set-json"
+		}
+	}
+	return fmt.Sprintf("Use --%s %s=..., or --values with a file that has it", flag, key)
+}
+
+func valuesKey(jsonPointer []string) string {
+	var key strings.Builder
+	for _, part := range jsonPointer {
+		if _, err := strconv.Atoi(part); err == nil {
+			key.WriteString(fmt.Sprintf("[%s]", part))
+			continue
+		}
+		if key.Len() > 0 {
+			key.WriteString(".")
+		}
+		key.WriteString(part)
+	}
+	return key.String()
+}
+
+// jsonValue converts the values into the plain JSON types the schema validator expects
+func jsonValue(value any) any {
+	switch typed := value.(type) {
+	case nil, string, bool, float64:
+		return typed
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	case values.Map:
+		return jsonValue(map[string]any(typed))
+	case values.Slice:
+		return jsonValue([]any(typed))
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for key, item := range typed {
+			result[key] = jsonValue(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(typed))
+		for i, item := range typed {
+			result[i] = jsonValue(item)
+		}
+		return result
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint())
+	case reflect.Float32:
+		return reflected.Float()
+	case reflect.String:
+		return reflected.String()
+	case reflect.Bool:
+		return reflected.Bool()
+	}
+
+	//complex values (e.g. a parsed GraphQL schema or gRPC proto) can only be checked for being an object
+	return map[string]any{}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/values"
+	"github.com/stretchr/testify/require"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplateValues(t *testing.T) {
+	templateFile := path.Join("testdata", "render", "values-schema", "input.yaml")
+
+	tests := []struct {
+		name    string
+		values  values.Map
+		wantErr []string
+	}{
+		{
+			"valid",
+			values.Map{"name": "orders", "base_path": "/v1/orders", "target": values.Map{"url": "https://example.com", "timeout": 30}},
+			nil,
+		},
+		{
+			"missing keys",
+			values.Map{"name": "orders", "target": values.Map{}},
+			[]string{
+				`values: missing key "base_path". Use --set-string base_path=..., or --values with a file that has it`,
+				`values: missing key "target.url". Use --set-string target.url=..., or --values with a file that has it`,
+			},
+		},
+		{
+			"mistyped keys",
+			values.Map{"name": "orders", "base_path": "v1", "target": values.Map{"url": "https://example.com", "timeout": "30s"}, "spec": "petstore.yaml"},
+			[]string{
+				`values: key "base_path" string doesn't match the regular expression "^/". Use --set-string base_path=..., or --values with a file that has it`,
+				`values: key "spec" value must be an object. Use --set-oas spec=..., or --values with a file that has it`,
+				`values: key "target.timeout" value must be an integer. Use --set target.timeout=..., or --values with a file that has it`,
+			},
+		},
+		{
+			"list items",
+			values.Map{"name": "orders", "base_path": "/v1", "target": values.Map{"url": "https://example.com"}, "servers": []any{values.Map{"url": "a"}, values.Map{}}},
+			[]string{
+				`values: missing key "servers[1].url". Use --set servers[1].url=..., or --values with a file that has it`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateValues(templateFile, tt.values)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			wantErr := append(tt.wantErr, "values do not match the schema in "+path.Join("testdata", "render", "values-schema", "values.schema.yaml"))
+			require.EqualError(t, err, strings.Join(wantErr, "\n"))
+		})
+	}
+}
+
+func TestValidateTemplateValues_NoSchema(t *testing.T) {
+	templateFile := path.Join("testdata", "render", "using-files", "input.yaml")
+	err := ValidateTemplateValues(templateFile, values.Map{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,410 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+const sourceMarkerFunc = "_source_marker"
+const sourceMarkerDelim = '\x00'
+
+// SourceLocation is the template file and line that produced a line of rendered output
+type SourceLocation struct {
+	File         string
+	Line         int
+	IncludedFrom *SourceLocation
+}
+
+func (l *SourceLocation) String() string {
+	text := fmt.Sprintf("%s:%d", l.File, l.Line)
+	var from []string
+	for parent := l.IncludedFrom; parent != nil; parent = parent.IncludedFrom {
+		from = append(from, fmt.Sprintf("%s:%d", parent.File, parent.Line))
+	}
+	if len(from) > 0 {
+		text = fmt.Sprintf("%s (included from %s)", text, strings.Join(from, ", included from "))
+	}
+	return text
+}
+
+// includedFrom returns a copy of the location, with the given location at the end of the include chain
+func (l *SourceLocation) includedFrom(from *SourceLocation) *SourceLocation {
+	result := *l
+	if result.IncludedFrom == nil {
+		result.IncludedFrom = from
+	} else {
+		result.IncludedFrom = result.IncludedFrom.includedFrom(from)
+	}
+	return &result
+}
+
+// SourceMap holds the location of each line of rendered output (the first line is at index 0)
+type SourceMap []*SourceLocation
+
+// Lookup returns the template location for the given line of rendered output (starting at 1), or nil if not known
+func (m SourceMap) Lookup(line int) *SourceLocation {
+	if line < 1 || line > len(m) {
+		return nil
+	}
+	return m[line-1]
+}
+
+// SourceError is an error on a line of rendered output, along with the template location that produced the line
+type SourceError struct {
+	Source *SourceLocation
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s, from %s", e.Err.Error(), e.Source)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+var yamlErrorLineRegex = regexp.MustCompile(`\bline (\d+)\b`)
+
+// annotateYAMLError adds the template location to a YAML parse error (e.g. "yaml: line 12: did not find expected key")
+func (m SourceMap) annotateYAMLError(err error) error {
+	match := yamlErrorLineRegex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(match[1])
+	if source := m.Lookup(line); source != nil {
+		return &SourceError{Source: source, Err: err}
+	}
+	return err
+}
+
+// annotatePositionErrors adds the template location to the errors positioned within the rendered file
+func (m SourceMap) annotatePositionErrors(err error, renderedFile string) error {
+	switch e := err.(type) {
+	case utils.MultiError:
+		result := utils.MultiError{}
+		for _, subErr := range e.Errors {
+			result.Errors = append(result.Errors, m.annotatePositionErrors(subErr, renderedFile))
+		}
+		return result
+	case *v1.PositionError:
+		if e.Position.File != renderedFile {
+			return err
+		}
+		if source := m.Lookup(e.Position.Line); source != nil {
+			return &SourceError{Source: source, Err: err}
+		}
+	}
+	return err
+}
+
+// sourceMapper records which template file and line produced each line of rendered output.
+//
+// Each text and action node of the templates is preceded by a marker action, which writes a marker into the output.
+// The markers are removed after rendering, and used to build the SourceMap. The output of the include helper is
+// returned without markers (so that it can be safely passed to other functions). Instead, each include records its own
+// SourceMap, which is matched line by line to the output of the action that called it.
+type sourceMapper struct {
+	locations   []sourceNode
+	occurrences []*sourceOccurrence
+	includes    []includeRecord
+	open        map[int]*sourceOccurrence
+	depth       int
+}
+
+type sourceNode struct {
+	location SourceLocation
+	isText   bool
+}
+
+// sourceOccurrence is a marker written to the output, it may be written many times from within a range loop
+type sourceOccurrence struct {
+	node         sourceNode
+	includeStart int
+	includeEnd   int
+}
+
+type includeRecord struct {
+	lines     []string
+	sourceMap SourceMap
+}
+
+func newSourceMapper() *sourceMapper {
+	return &sourceMapper{open: map[int]*sourceOccurrence{}}
+}
+
+// instrument adds markers to all the templates. The names map replaces template names with the name to show,
+// and templates mapped to an empty name are skipped.
+func (m *sourceMapper) instrument(tmpl *template.Template, names map[string]string) {
+	if m == nil {
+		return
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		fileName := t.Tree.ParseName
+		if name, found := names[fileName]; found {
+			if name == "" {
+				continue
+			}
+			fileName = name
+		}
+		m.instrumentList(t.Tree, t.Tree.Root, fileName)
+	}
+}
+
+func (m *sourceMapper) instrumentList(tree *parse.Tree, list *parse.ListNode, fileName string) {
+	if list == nil {
+		return
+	}
+
+	var nodes []parse.Node
+	for _, node := range list.Nodes {
+		if n, ok := node.(*parse.ActionNode); ok && isSourceMarker(n) {
+			nodes = append(nodes, node)
+			continue
+		}
+
+		// the marker is created before instrumenting the inner lists, as the location of a node includes its text
+		nodes = append(nodes, m.markerNode(tree, node, fileName))
+		nodes = append(nodes, node)
+
+		switch n := node.(type) {
+		case *parse.IfNode:
+			m.instrumentList(tree, n.List, fileName)
+			m.instrumentList(tree, n.ElseList, fileName)
+		case *parse.RangeNode:
+			m.instrumentList(tree, n.List, fileName)
+			m.instrumentList(tree, n.ElseList, fileName)
+		case *parse.WithNode:
+			m.instrumentList(tree, n.List, fileName)
+			m.instrumentList(tree, n.ElseList, fileName)
+		}
+	}
+	list.Nodes = nodes
+}
+
+func (m *sourceMapper) markerNode(tree *parse.Tree, node parse.Node, fileName string) *parse.ActionNode {
+	location, _ := tree.ErrorContext(node)
+	_, isText := node.(*parse.TextNode)
+
+	m.locations = append(m.locations, sourceNode{
+		location: SourceLocation{File: fileName, Line: locationLine(location)},
+		isText:   isText,
+	})
+
+	id := strconv.Itoa(len(m.locations) - 1)
+	pos := node.Position()
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      pos,
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      pos,
+				Args: []parse.Node{
+					parse.NewIdentifier(sourceMarkerFunc).SetTree(tree).SetPos(pos),
+					&parse.StringNode{NodeType: parse.NodeString, Pos: pos, Quoted: strconv.Quote(id), Text: id},
+				},
+			}},
+		},
+	}
+}
+
+func isSourceMarker(n *parse.ActionNode) bool {
+	if len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) == 0 {
+		return false
+	}
+	identifier, ok := n.Pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	return ok && identifier.Ident == sourceMarkerFunc
+}
+
+// locationLine extracts the line from a "name:line:col" location
+func locationLine(location string) int {
+	parts := strings.Split(location, ":")
+	if len(parts) < 3 {
+		return 0
+	}
+	line, _ := strconv.Atoi(parts[len(parts)-2])
+	return line
+}
+
+// marker is the template function called by the marker actions
+func (m *sourceMapper) marker(id string) string {
+	index, err := strconv.Atoi(id)
+	if err != nil || index < 0 || index >= len(m.locations) {
+		return ""
+	}
+
+	m.close()
+	occurrence := &sourceOccurrence{node: m.locations[index], includeStart: len(m.includes)}
+	m.open[m.depth] = occurrence
+	m.occurrences = append(m.occurrences, occurrence)
+
+	return fmt.Sprintf("%c%d%c", sourceMarkerDelim, len(m.occurrences)-1, sourceMarkerDelim)
+}
+
+// close ends the latest occurrence at the current depth, so that later includes are not attributed to it
+func (m *sourceMapper) close() {
+	if occurrence, found := m.open[m.depth]; found {
+		occurrence.includeEnd = len(m.includes)
+		delete(m.open, m.depth)
+	}
+}
+
+func (m *sourceMapper) beginInclude() int {
+	if m == nil {
+		return 0
+	}
+	m.depth++
+	return len(m.includes)
+}
+
+// endInclude removes the markers from the output of an include, and records its source map
+func (m *sourceMapper) endInclude(start int, output string) string {
+	if m == nil {
+		return output
+	}
+
+	text, sourceMap := m.finish(output)
+	m.includes = append(m.includes[:start], includeRecord{lines: strings.Split(text, "\n"), sourceMap: sourceMap})
+	m.depth--
+	return text
+}
+
+// finish removes the markers from the output at the current depth, and returns the source map for it
+func (m *sourceMapper) finish(output string) (string, SourceMap) {
+	m.close()
+
+	var text strings.Builder
+	var sourceMap SourceMap
+	var current *sourceOccurrence
+	var actionLines []int
+	var actionOccurrences []*sourceOccurrence
+	newLines := 0
+	assigned := false
+
+	assign := func() {
+		var location *SourceLocation
+		if current != nil {
+			source := current.node.location
+			if current.node.isText {
+				source.Line += newLines
+			} else {
+				actionLines = append(actionLines, len(sourceMap))
+				actionOccurrences = append(actionOccurrences, current)
+			}
+			location = &source
+		}
+		sourceMap = append(sourceMap, location)
+		assigned = true
+	}
+
+	for i := 0; i < len(output); i++ {
+		c := output[i]
+		switch {
+		case c == sourceMarkerDelim:
+			end := strings.IndexByte(output[i+1:], sourceMarkerDelim)
+			if end < 0 {
+				text.WriteString(output[i:])
+				i = len(output)
+				continue
+			}
+			if index, err := strconv.Atoi(output[i+1 : i+1+end]); err == nil && index < len(m.occurrences) {
+				current = m.occurrences[index]
+				newLines = 0
+			}
+			i += end + 1
+			continue
+		case c == '\n':
+			if !assigned {
+				assign()
+			}
+			assigned = false
+			newLines++
+		case !assigned && c != ' ' && c != '\t' && c != '\r':
+			assign()
+		}
+		text.WriteByte(c)
+	}
+	if !assigned {
+		assign()
+	}
+
+	result := text.String()
+	m.resolveIncludes(strings.Split(result, "\n"), sourceMap, actionLines, actionOccurrences)
+	return result, sourceMap
+}
+
+// resolveIncludes matches the lines produced by actions with the lines produced by the includes they called
+func (m *sourceMapper) resolveIncludes(lines []string, sourceMap SourceMap, actionLines []int, actionOccurrences []*sourceOccurrence) {
+	var occurrence *sourceOccurrence
+	var includeLines []string
+	var includeLocations []*SourceLocation
+	next := 0
+
+	for i, line := range actionLines {
+		if actionOccurrences[i] != occurrence {
+			occurrence = actionOccurrences[i]
+			includeLines, includeLocations, next = nil, nil, 0
+			for _, record := range m.includes[occurrence.includeStart:max(occurrence.includeStart, occurrence.includeEnd)] {
+				includeLines = append(includeLines, record.lines...)
+				for j := range record.lines {
+					includeLocations = append(includeLocations, record.sourceMap.Lookup(j+1))
+				}
+			}
+		}
+
+		content := strings.TrimSpace(lines[line])
+		if content == "" {
+			continue
+		}
+		for j := next; j < len(includeLines); j++ {
+			if strings.TrimSpace(includeLines[j]) != content || includeLocations[j] == nil {
+				continue
+			}
+			sourceMap[line] = includeLocations[j].includedFrom(sourceMap[line])
+			next = j + 1
+			break
+		}
+	}
+}
+
+// realign updates the source map after lines have been removed from the output
+func (m SourceMap) realign(before string, after string) SourceMap {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+
+	result := make(SourceMap, len(afterLines))
+	j := 0
+	for i, line := range beforeLines {
+		if j < len(afterLines) && line == afterLines[j] {
+			result[j] = m.Lookup(i + 1)
+			j++
+		}
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderGenericTemplateLocal_SourceMap(t *testing.T) {
+	testDir := filepath.Join("testdata", "render", "source-map")
+
+	var sourceMap SourceMap
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(filepath.Join(testDir, "apiproxy.yaml"))
+	cFlags.TemplateFileAlias = "apiproxy.yaml"
+	cFlags.OutputFile = flags.String(filepath.Join(t.TempDir(), "apiproxy.yaml"))
+	cFlags.SourceMap = &sourceMap
+	cFlags.Values.Set("header", "example")
+
+	err := RenderGenericTemplateLocal(cFlags, false)
+	require.NoError(t, err)
+
+	rendered := string(utils.MustReadFileBytes(string(cFlags.OutputFile)))
+	lines := strings.Split(rendered, "\n")
+	require.Len(t, sourceMap, len(lines))
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{".name: source-map", "apiproxy.yaml:16"},
+		{"AssignMessage:", "policies/assign-message.yaml:14 (included from policies/policies.yaml:14, included from apiproxy.yaml:18)"},
+		{"DisplayName: AM-SetHeader", "_helpers.tmpl:18 (included from policies/assign-message.yaml:16, included from policies/policies.yaml:14, included from apiproxy.yaml:18)"},
+		{"-Data: example", "policies/assign-message.yaml:21 (included from policies/policies.yaml:14, included from apiproxy.yaml:18)"},
+		{"BasePath: /source-map", "apiproxy.yaml:28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			index := -1
+			for i, line := range lines {
+				if strings.TrimSpace(line) == tt.line {
+					index = i
+					break
+				}
+			}
+			require.GreaterOrEqual(t, index, 0, "line not found in rendered output")
+			require.NotNil(t, sourceMap.Lookup(index+1))
+			require.Equal(t, tt.want, sourceMap.Lookup(index+1).String())
+		})
+	}
+}
+
+func TestGenerateBundle_SourceMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{
+			"invalid-yaml",
+			"broken",
+			"yaml: line 42: did not find expected ',' or ']', from policies/assign-message.yaml:21 (included from policies/policies.yaml:14, included from apiproxy.yaml:18)",
+		},
+		{
+			"validation-error",
+			"invalid",
+			`apiproxy.yaml (rendered):43:7: unknown node "UnknownElement" found at "Root.Policies.0.AssignMessage(name: AM-SetHeader)", from policies/assign-message.yaml:23 (included from policies/policies.yaml:14, included from apiproxy.yaml:18)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cFlags := NewCommonFlags()
+			cFlags.TemplateFile = flags.String(filepath.Join("testdata", "render", "source-map", "apiproxy.yaml"))
+			cFlags.TemplateFileAlias = "apiproxy.yaml"
+			cFlags.OutputFile = flags.String(filepath.Join(t.TempDir(), "apiproxy.zip"))
+			cFlags.Values.Set(tt.value, true)
+
+			createModelFunc := func(input string) (v1.Model, error) {
+				return v1.NewAPIProxyModel(input)
+			}
+
+			err := GenerateBundle(createModelFunc, cFlags, true, "", false)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLazySourceMap(t *testing.T) {
+	renders := 0
+	render := func(sourceMapFlags *CommonFlags) error {
+		renders++
+		*sourceMapFlags.SourceMap = SourceMap{{File: "apiproxy.yaml", Line: 1}}
+		return nil
+	}
+
+	cFlags := NewCommonFlags()
+	sourceMap := lazySourceMap(cFlags, render)
+	require.Equal(t, 0, renders, "source map must not be rendered until needed")
+	require.Equal(t, "apiproxy.yaml:1", sourceMap().Lookup(1).String())
+	require.Equal(t, "apiproxy.yaml:1", sourceMap().Lookup(1).String())
+	require.Equal(t, 1, renders)
+	require.Nil(t, cFlags.SourceMap)
+
+	requested := SourceMap{{File: "sharedflow.yaml", Line: 2}}
+	cFlags.SourceMap = &requested
+	sourceMap = lazySourceMap(cFlags, render)
+	require.Equal(t, "sharedflow.yaml:2", sourceMap().Lookup(1).String())
+	require.Equal(t, 1, renders, "requested source map must be used as is")
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"strconv"
+	"text/template"
+	"text/template/parse"
+)
+
+const strictValueFunc = "_strict_value"
+const noValue = "<no value>"
+
+// strictValue fails on values that text/template would otherwise render as "<no value>"
+func strictValue(action string, value any) (any, error) {
+	if value == nil {
+		return nil, errors.Errorf("%s has no value", action)
+	}
+	return value, nil
+}
+
+// instrumentStrict pipes the output of each action (e.g. {{ $.Values.name }}) through strictValue,
+// so that values that would render as "<no value>" fail with the template name and line where they are used
+func instrumentStrict(tmpl *template.Template) {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		instrumentNode(t.Tree, t.Tree.Root)
+	}
+}
+
+func instrumentNode(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			instrumentNode(tree, child)
+		}
+	case *parse.IfNode:
+		instrumentNode(tree, n.List)
+		instrumentNode(tree, n.ElseList)
+	case *parse.RangeNode:
+		instrumentNode(tree, n.List)
+		instrumentNode(tree, n.ElseList)
+	case *parse.WithNode:
+		instrumentNode(tree, n.List)
+		instrumentNode(tree, n.ElseList)
+	case *parse.ActionNode:
+		//variable declarations do not produce output
+		if len(n.Pipe.Decl) > 0 || isInstrumented(n) {
+			return
+		}
+
+		//text/template adds the template name and line to the error, so only the action itself is needed
+		_, context := tree.ErrorContext(n)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args: []parse.Node{
+				parse.NewIdentifier(strictValueFunc).SetTree(tree).SetPos(n.Pos),
+				&parse.StringNode{NodeType: parse.NodeString, Pos: n.Pos, Quoted: strconv.Quote(context), Text: context},
+			},
+		})
+	}
+}
+
+func isInstrumented(n *parse.ActionNode) bool {
+	cmds := n.Pipe.Cmds
+	if len(cmds) == 0 || len(cmds[len(cmds)-1].Args) == 0 {
+		return false
+	}
+	identifier, ok := cmds[len(cmds)-1].Args[0].(*parse.IdentifierNode)
+	return ok && identifier.Ident == strictValueFunc
+}
+
+// checkNoValue reports any "<no value>" left in the rendered output, along with the output line
+func checkNoValue(templateName string, rendered []byte) error {
+	var errs []error
+	for i, line := range bytes.Split(rendered, []byte("\n")) {
+		if bytes.Contains(line, []byte(noValue)) {
+			errs = append(errs, errors.Errorf(`%s (rendered):%d: output contains "%s": %s`, templateName, i+1, noValue, bytes.TrimSpace(line)))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return utils.MultiError{Errors: errs}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/globals"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRenderGenericTemplateLocal_Strict(t *testing.T) {
+	tests := []struct {
+		templateFile string
+		strict       bool
+		values       map[string]any
+		wantErr      string
+	}{
+		{
+			"missing-key.yaml",
+			true,
+			map[string]any{"target_url": "https://example.com"},
+			`map has no entry for key "taget_url"`,
+		},
+		{
+			"missing-key.yaml",
+			false,
+			map[string]any{"target_url": "https://example.com"},
+			"",
+		},
+		{
+			"no-value.yaml",
+			true,
+			map[string]any{"name": "orders", "target_url": nil},
+			`template: testdata/render/strict/no-value.yaml:16:10: executing "testdata/render/strict/no-value.yaml" at <_strict_value "{{$.Values.target_url}}">: error calling _strict_value: {{$.Values.target_url}} has no value`,
+		},
+		{
+			"missing-include.yaml",
+			true,
+			map[string]any{},
+			`include "target_url": there is no file "testdata/render/strict/target_url" or named template "target_url"`,
+		},
+		{
+			"valid.yaml",
+			true,
+			map[string]any{"target_url": "https://example.com"},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		name := tt.templateFile
+		if tt.strict {
+			name += "-strict"
+		}
+		t.Run(name, func(t *testing.T) {
+			outputFile := path.Join(t.TempDir(), tt.templateFile)
+
+			cFlags := NewCommonFlags()
+			cFlags.TemplateFile = flags.String(path.Join("testdata", "render", "strict", tt.templateFile))
+			cFlags.OutputFile = flags.String(outputFile)
+			cFlags.Strict = flags.Bool(tt.strict)
+			for key, value := range tt.values {
+				(*cFlags.Values)[key] = value
+			}
+
+			err := RenderGenericTemplateLocal(cFlags, false)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				require.NoFileExists(t, outputFile)
+				return
+			}
+			require.NoError(t, err)
+			require.FileExists(t, outputFile)
+		})
+	}
+}
+
+func TestCheckNoValue(t *testing.T) {
+	err := checkNoValue("apiproxy.yaml", []byte("name: orders\nurl: <no value>\n"))
+	require.EqualError(t, err, `apiproxy.yaml (rendered):2: output contains "<no value>": url: <no value>`)
+
+	err = checkNoValue("apiproxy.yaml", []byte("name: orders\n"))
+	require.NoError(t, err)
+}
+
+func TestMain(m *testing.M) {
+	//helper functions check this when recovering from panics, it is normally set by the root command
+	showStack := flags.NewBool(false)
+	globals.ShowStack = &showStack
+	os.Exit(m.Run())
+}
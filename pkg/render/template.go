@@ -23,21 +23,26 @@ import (
 func RenderGenericTemplate(cFlags *CommonFlags, dryRun bool) error {
 	var err error
 	if git.IsGitURI(string(cFlags.TemplateFile)) {
-		var templateFileFromGit string
-		var templateDirFromGit string
-		if templateFileFromGit, templateDirFromGit, err = git.FetchFile(string(cFlags.TemplateFile)); err != nil {
+		var checkout *git.Checkout
+		if checkout, err = git.Fetch(string(cFlags.TemplateFile)); err != nil {
 			return err
 		}
-		defer utils.LenientRemoveAll(templateDirFromGit)
-		cFlags.TemplateFile = flags.String(templateFileFromGit)
+		defer utils.LenientRemoveAll(checkout.Dir)
+		cFlags.GitSources.Add(string(cFlags.TemplateFile), checkout.Ref, checkout.Commit)
+		cFlags.TemplateFile = flags.String(checkout.File)
 	}
 
 	return RenderGenericTemplateLocal(cFlags, dryRun)
 }
 
+// TemplateContext is the data available to generic templates
+type TemplateContext struct {
+	Values map[string]any
+}
+
 func RenderGenericTemplateLocal(cFlags *CommonFlags, dryRun bool) error {
-	type TemplateContext struct {
-		Values map[string]any
+	if err := ValidateTemplateValues(string(cFlags.TemplateFile), *cFlags.Values); err != nil {
+		return err
 	}
 
 	context := &TemplateContext{
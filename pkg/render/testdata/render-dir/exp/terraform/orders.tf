@@ -0,0 +1,3 @@
+resource "apigee_proxy" "orders" {
+  owner = "team-orders"
+}
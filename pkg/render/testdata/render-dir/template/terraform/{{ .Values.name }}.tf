@@ -0,0 +1,3 @@
+resource "apigee_proxy" "{{ .Values.name }}" {
+  owner = "{{ template "owner" . }}"
+}
@@ -0,0 +1,292 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultWorkspaceFile = "apigee-go-gen.yaml"
+
+// WorkspaceBundle is a single API proxy or shared flow to render, along with the same inputs as the render commands
+type WorkspaceBundle struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`
+	Template   string   `yaml:"template"`
+	Include    []string `yaml:"include"`
+	Output     string   `yaml:"output"`
+	Validate   *bool    `yaml:"validate"`
+	Strict     bool     `yaml:"strict"`
+	Profile    string   `yaml:"profile"`
+	Values     []string `yaml:"values"`
+	Set        []string `yaml:"set"`
+	SetString  []string `yaml:"set-string"`
+	SetJSON    []string `yaml:"set-json"`
+	SetFile    []string `yaml:"set-file"`
+	SetOAS     []string `yaml:"set-oas"`
+	SetGRPC    []string `yaml:"set-grpc"`
+	SetGraphQL []string `yaml:"set-graphql"`
+	SetTF      []string `yaml:"set-tf"`
+}
+
+// Workspace lists many bundles to be rendered in one run.
+// Relative paths within the workspace file are relative to the directory of the workspace file.
+type Workspace struct {
+	Profile string             `yaml:"profile"`
+	Values  []string           `yaml:"values"`
+	Bundles []*WorkspaceBundle `yaml:"bundles"`
+
+	Dir string `yaml:"-"`
+}
+
+// WorkspaceResult is the outcome of rendering a single bundle
+type WorkspaceResult struct {
+	Name     string
+	Output   string
+	Duration time.Duration
+	Err      error
+
+	// Warnings are the validation warnings of the bundle (see GenerateBundleWarnings)
+	Warnings []error
+
+	// GitSources are the git URIs fetched for the bundle, along with the commit each ref resolved to
+	GitSources *git.Sources
+}
+
+func LoadWorkspace(file string) (*Workspace, error) {
+	text, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	workspace := &Workspace{}
+	decoder := yaml.NewDecoder(bytes.NewReader(text))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(workspace); err != nil {
+		return nil, errors.Errorf("%s: %s", file, err.Error())
+	}
+
+	// paths are made absolute, so that bundles rendered in parallel do not depend on the working directory
+	if workspace.Dir, err = filepath.Abs(filepath.Dir(file)); err != nil {
+		return nil, errors.New(err)
+	}
+	if err = workspace.validate(); err != nil {
+		return nil, errors.Errorf("%s: %s", file, err.Error())
+	}
+
+	return workspace, nil
+}
+
+func (w *Workspace) validate() error {
+	if len(w.Bundles) == 0 {
+		return errors.New("no bundles found")
+	}
+
+	names := map[string]bool{}
+	for i, bundle := range w.Bundles {
+		if bundle.Name == "" {
+			return errors.Errorf("bundles.%d: missing name", i)
+		}
+		if names[bundle.Name] {
+			return errors.Errorf("bundles.%d: duplicate name %q", i, bundle.Name)
+		}
+		names[bundle.Name] = true
+
+		if bundle.Type == "" {
+			bundle.Type = "apiproxy"
+		}
+		if bundle.Type != "apiproxy" && bundle.Type != "sharedflow" {
+			return errors.Errorf("bundles.%d (%s): type must be either apiproxy or sharedflow", i, bundle.Name)
+		}
+		if bundle.Template == "" {
+			return errors.Errorf("bundles.%d (%s): missing template", i, bundle.Name)
+		}
+		if bundle.Output == "" {
+			return errors.Errorf("bundles.%d (%s): missing output", i, bundle.Name)
+		}
+	}
+	return nil
+}
+
+// Select returns the bundles with the given names, or all bundles if no names are given
+func (w *Workspace) Select(names []string) ([]*WorkspaceBundle, error) {
+	if len(names) == 0 {
+		return w.Bundles, nil
+	}
+
+	var selected []*WorkspaceBundle
+	for _, name := range names {
+		index := slices.IndexFunc(w.Bundles, func(bundle *WorkspaceBundle) bool { return bundle.Name == name })
+		if index < 0 {
+			return nil, errors.Errorf("bundle %q not found in workspace", name)
+		}
+		selected = append(selected, w.Bundles[index])
+	}
+	return selected, nil
+}
+
+// path makes a path from the workspace file absolute
+func (w *Workspace) path(path string) string {
+	if path == "" || filepath.IsAbs(path) || git.IsGitURI(path) {
+		return path
+	}
+	return filepath.Join(w.Dir, path)
+}
+
+// keyPath makes the path within a key=path entry (e.g. from set-oas) absolute
+func (w *Workspace) keyPath(entry string) string {
+	key, path, found := strings.Cut(entry, "=")
+	if !found {
+		return entry
+	}
+	return fmt.Sprintf("%s=%s", key, w.path(path))
+}
+
+// CommonFlags builds the same flags the render commands would get from the command line.
+// Values files (and their overlays for the bundle's profile, or the workspace profile) are applied first, followed by the set, set-string, set-json, set-file, set-oas, set-grpc, set-graphql and set-tf entries.
+func (w *Workspace) CommonFlags(bundle *WorkspaceBundle) (*CommonFlags, error) {
+	cFlags := NewCommonFlags()
+	cFlags.TemplateFile = flags.String(w.path(bundle.Template))
+	cFlags.OutputFile = flags.String(w.path(bundle.Output))
+	cFlags.Strict = flags.Bool(bundle.Strict)
+	for _, include := range bundle.Include {
+		cFlags.IncludeList = append(cFlags.IncludeList, w.path(include))
+	}
+
+	profile := flags.NewProfile(w.Profile)
+	if bundle.Profile != "" {
+		profile = flags.NewProfile(bundle.Profile)
+	}
+
+	setValueFile := flags.NewProfileValues(cFlags.Values, &profile)
+	setValue := flags.NewSetAny(cFlags.Values)
+	setValueStr := flags.NewSetString(cFlags.Values)
+	setJSON := flags.NewSetJSON(cFlags.Values)
+	setFile := flags.NewSetFile(cFlags.Values)
+	setOAS := flags.NewSetOAS(cFlags.Values)
+	setGRPC := flags.NewSetGRPC(cFlags.Values)
+	setGraphQL := flags.NewSetGraphQL(cFlags.Values)
+	setTF := flags.NewSetTF(cFlags.Values)
+
+	// each bundle collects its own git sources, so that its manifest only records what it fetched
+	setValueFile.GitSources = cFlags.GitSources
+	setFile.GitSources = cFlags.GitSources
+	setOAS.GitSources = cFlags.GitSources
+	setGRPC.GitSources = cFlags.GitSources
+	setGraphQL.GitSources = cFlags.GitSources
+	setTF.GitSources = cFlags.GitSources
+
+	type setter struct {
+		name    string
+		set     func(string) error
+		entries []string
+		path    func(string) string
+	}
+
+	identity := func(entry string) string { return entry }
+	setters := []setter{
+		{"values", setValueFile.Set, append(slices.Clone(w.Values), bundle.Values...), w.path},
+		{"set", setValue.Set, bundle.Set, identity},
+		{"set-string", setValueStr.Set, bundle.SetString, identity},
+		{"set-json", setJSON.Set, bundle.SetJSON, identity},
+		{"set-file", setFile.Set, bundle.SetFile, w.keyPath},
+		{"set-oas", setOAS.Set, bundle.SetOAS, w.keyPath},
+		{"set-grpc", setGRPC.Set, bundle.SetGRPC, w.keyPath},
+		{"set-graphql", setGraphQL.Set, bundle.SetGraphQL, w.keyPath},
+		{"set-tf", setTF.Set, bundle.SetTF, w.keyPath},
+	}
+
+	for _, s := range setters {
+		for _, entry := range s.entries {
+			if err := s.set(s.path(entry)); err != nil {
+				return nil, errors.Errorf("%s %q: %s", s.name, entry, err.Error())
+			}
+		}
+	}
+
+	return cFlags, nil
+}
+
+// RenderWorkspace renders the bundles using up to the given number of parallel workers.
+// The progress function (if any) is called as soon as each bundle is done, and the results are returned in the same order as the bundles.
+func RenderWorkspace(w *Workspace, bundles []*WorkspaceBundle, parallel int, progress func(result WorkspaceResult)) []WorkspaceResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]WorkspaceResult, len(bundles))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var progressMutex sync.Mutex
+
+	for worker := 0; worker < parallel; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = w.renderBundle(bundles[i])
+				if progress != nil {
+					progressMutex.Lock()
+					progress(results[i])
+					progressMutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range bundles {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (w *Workspace) renderBundle(bundle *WorkspaceBundle) WorkspaceResult {
+	start := time.Now()
+	result := WorkspaceResult{Name: bundle.Name, Output: w.path(bundle.Output)}
+
+	cFlags, err := w.CommonFlags(bundle)
+
+	if err == nil {
+		result.GitSources = cFlags.GitSources
+		createModelFunc := func(input string) (v1.Model, error) {
+			if bundle.Type == "sharedflow" {
+				return v1.NewSharedFlowBundleModel(input)
+			}
+			return v1.NewAPIProxyModel(input)
+		}
+
+		validate := bundle.Validate == nil || *bundle.Validate
+		result.Warnings, err = GenerateBundleWarnings(createModelFunc, cFlags, validate, "", false)
+	}
+
+	result.Err = err
+	result.Duration = time.Since(start)
+	return result
+}
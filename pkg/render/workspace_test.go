@@ -0,0 +1,114 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderWorkspace(t *testing.T) {
+	testDir := path.Join("testdata", "render-workspace")
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"orders", ""},
+		{"payments", ""},
+		{"security", ""},
+		{"broken", "rendered template appears to not be valid YAML"},
+	}
+
+	for _, tt := range tests {
+		err := os.RemoveAll(path.Join(testDir, "out-"+tt.name+".zip"))
+		require.NoError(t, err)
+	}
+
+	workspace, err := LoadWorkspace(path.Join(testDir, DefaultWorkspaceFile))
+	require.NoError(t, err)
+	require.True(t, filepath.IsAbs(workspace.Dir))
+
+	bundles, err := workspace.Select(nil)
+	require.NoError(t, err)
+
+	results := RenderWorkspace(workspace, bundles, 4, nil)
+	require.Len(t, results, len(tests))
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results[i]
+			require.Equal(t, tt.name, result.Name)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, result.Err, tt.wantErr)
+				require.NoFileExists(t, result.Output)
+				return
+			}
+
+			require.NoError(t, result.Err)
+			utils.RequireBundleZipEquals(t, path.Join(testDir, "exp-"+tt.name+".zip"), result.Output)
+		})
+	}
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{
+			"unknown-field",
+			"bundles:\n  - name: a\n    templat: t.yaml\n    output: out\n",
+			"field templat not found",
+		},
+		{
+			"duplicate-name",
+			"bundles:\n  - name: a\n    template: t.yaml\n    output: out\n  - name: a\n    template: t.yaml\n    output: out\n",
+			`bundles.1: duplicate name "a"`,
+		},
+		{
+			"invalid-type",
+			"bundles:\n  - name: a\n    type: proxy\n    template: t.yaml\n    output: out\n",
+			"bundles.0 (a): type must be either apiproxy or sharedflow",
+		},
+		{
+			"missing-output",
+			"bundles:\n  - name: a\n    template: t.yaml\n",
+			"bundles.0 (a): missing output",
+		},
+		{
+			"no-bundles",
+			"values: []\n",
+			"no bundles found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := path.Join(t.TempDir(), DefaultWorkspaceFile)
+			err := os.WriteFile(file, []byte(tt.text), os.ModePerm)
+			require.NoError(t, err)
+
+			_, err = LoadWorkspace(file)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"net/http"
+)
+
+// ErrorResponse is the body of failed requests. It lists the errors the same way the CLI prints a utils.MultiError.
+type ErrorResponse struct {
+	Errors []ErrorEntry `json:"errors"`
+}
+
+type ErrorEntry struct {
+	Message string `json:"message"`
+
+	// Position is the location of the error within the YAML file, if known
+	Position *utils.YAMLPosition `json:"position,omitempty"`
+}
+
+// NewErrorResponse flattens a utils.MultiError into one entry per error
+func NewErrorResponse(err error) ErrorResponse {
+	var errs []error
+	var multiError utils.MultiError
+	if errors.As(err, &multiError) {
+		errs = multiError.Errors
+	} else {
+		errs = []error{err}
+	}
+
+	response := ErrorResponse{Errors: []ErrorEntry{}}
+	for _, e := range errs {
+		entry := ErrorEntry{Message: e.Error()}
+		var positionError *v1.PositionError
+		if errors.As(e, &positionError) {
+			position := positionError.Position
+			entry.Position = &position
+		}
+		response.Errors = append(response.Errors, entry)
+	}
+	return response
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body, marshalErr := json.MarshalIndent(NewErrorResponse(err), "", "  ")
+	if marshalErr != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/apiproxy"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/mock"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/sharedflow"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"testing/fstest"
+)
+
+// RenderRequest is the body of the render requests
+type RenderRequest struct {
+	// Files maps the slash-separated path of each file (templates, helpers, included files, specs, resources) to its contents
+	Files map[string]string `json:"files"`
+
+	// Template is the path of the main template within Files
+	Template string `json:"template"`
+
+	// Include lists additional templates (or globs) within Files, same as --include
+	Include []string `json:"include,omitempty"`
+
+	// Values are deep merged into the template values, same as --values
+	Values json.RawMessage `json:"values,omitempty"`
+
+	// Specs maps a values key to the path of an OpenAPI Description within Files, same as --set-oas key=path
+	Specs map[string]string `json:"specs,omitempty"`
+
+	// Strict fails on missing keys and "<no value>" output, same as --strict
+	Strict bool `json:"strict,omitempty"`
+}
+
+// OverlayRequest is the body of the oas-overlay request
+type OverlayRequest struct {
+	Files   map[string]string `json:"files"`
+	Spec    string            `json:"spec"`
+	Overlay string            `json:"overlay"`
+}
+
+// ResolveRefsRequest is the body of the resolve-refs request
+type ResolveRefsRequest struct {
+	Files       map[string]string `json:"files"`
+	Input       string            `json:"input"`
+	AllowCycles bool              `json:"allowCycles,omitempty"`
+}
+
+// MockRequest is the body of the mock request
+type MockRequest struct {
+	Files  map[string]string `json:"files"`
+	Spec   string            `json:"spec"`
+	Values json.RawMessage   `json:"values,omitempty"`
+}
+
+func renderAPIProxy(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return renderBundle(ctx, r, body, func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewAPIProxyModelFS(fsys, input)
+	})
+}
+
+func renderSharedFlow(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return renderBundle(ctx, r, body, func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewSharedFlowBundleModelFS(fsys, input)
+	})
+}
+
+func renderBundle(ctx context.Context, r *http.Request, body []byte, createModelFunc func(fs.FS, string) (v1.Model, error)) (*response, error) {
+	validate, err := queryBool(r, "validate", true)
+	if err != nil {
+		return nil, err
+	}
+	format, err := queryEnum(r, "format", "zip", "yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	request := RenderRequest{}
+	if err = decodeJSON(body, &request); err != nil {
+		return nil, err
+	}
+	if err = requireFile(request.Files, "template", request.Template); err != nil {
+		return nil, err
+	}
+
+	fsys, err := filesFS(request.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	cFlags := newRenderFlags(ctx)
+	cFlags.TemplateFile = flags.String(request.Template)
+	cFlags.IncludeList = request.Include
+	cFlags.Strict = flags.Bool(request.Strict)
+	if err = mergeValues(cFlags, request.Values); err != nil {
+		return nil, err
+	}
+
+	for key, specFile := range request.Specs {
+		if err = requireFile(request.Files, "specs", specFile); err != nil {
+			return nil, err
+		}
+		specText, err := resolveSpec(fsys, specFile)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkContext(ctx); err != nil {
+			return nil, err
+		}
+		oas := flags.NewSetOAS(cFlags.Values)
+		if err = oas.SetText(key, specFile, specText); err != nil {
+			return nil, err
+		}
+	}
+
+	bundle, err := render.GenerateBundleFS(fsys, createModelFunc, cFlags, validate)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundleResponse(ctx, bundle, format)
+}
+
+func mockOAS(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	format, err := queryEnum(r, "format", "zip", "yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	request := MockRequest{}
+	if err = decodeJSON(body, &request); err != nil {
+		return nil, err
+	}
+	if err = requireFile(request.Files, "spec", request.Spec); err != nil {
+		return nil, err
+	}
+
+	fsys, err := filesFS(request.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	specText, err := resolveSpec(fsys, request.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	cFlags := newRenderFlags(ctx)
+	if err = mergeValues(cFlags, request.Values); err != nil {
+		return nil, err
+	}
+
+	specFS := fstest.MapFS{request.Spec: {Data: specText}}
+	bundle, err := mock.GenerateMockProxyBundleFS(specFS, request.Spec, cFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundleResponse(ctx, bundle, format)
+}
+
+func apiProxyToYAML(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return bundleToYAML(ctx, r, body, "apiproxy.yaml", apiproxy.BundleZip2YAML)
+}
+
+func sharedFlowToYAML(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return bundleToYAML(ctx, r, body, "sharedflow.yaml", sharedflow.BundleZip2YAML)
+}
+
+// bundleToYAML converts the posted bundle zip. The response is the YAML document, or a zip with the YAML document and resource files.
+func bundleToYAML(ctx context.Context, r *http.Request, body []byte, yamlFile string, convert func([]byte) ([]byte, map[string][]byte, error)) (*response, error) {
+	format, err := queryEnum(r, "format", "yaml", "zip")
+	if err != nil {
+		return nil, err
+	}
+
+	docBytes, resources, err := convert(body)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if format == "yaml" {
+		if len(resources) > 0 {
+			return nil, errors.Errorf("bundle contains %d resource file(s), use format=zip to get them along with the YAML document", len(resources))
+		}
+		return yamlResponse(docBytes), nil
+	}
+
+	files := map[string][]byte{yamlFile: docBytes}
+	for fileName, contents := range resources {
+		files[fileName] = contents
+	}
+
+	zipBytes, err := zip.ZipFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	return zipResponse(zipBytes), nil
+}
+
+func yamlToAPIProxy(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return yamlToBundle(ctx, r, body, "apiproxy.yaml", func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewAPIProxyModelFS(fsys, input)
+	})
+}
+
+func yamlToSharedFlow(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	return yamlToBundle(ctx, r, body, "sharedflow.yaml", func(fsys fs.FS, input string) (v1.Model, error) {
+		return v1.NewSharedFlowBundleModelFS(fsys, input)
+	})
+}
+
+// yamlToBundle creates a bundle zip from the posted YAML document.
+// To include resource files, post a zip with the YAML document (named by the "input" query param) and the resource files instead.
+func yamlToBundle(ctx context.Context, r *http.Request, body []byte, defaultInput string, createModelFunc func(fs.FS, string) (v1.Model, error)) (*response, error) {
+	validate, err := queryBool(r, "validate", true)
+	if err != nil {
+		return nil, err
+	}
+
+	input := r.URL.Query().Get("input")
+	if input == "" {
+		input = defaultInput
+	}
+	if !fs.ValidPath(input) {
+		return nil, badRequest(`invalid input "%s", it must be a relative slash-separated path without ".." elements`, input)
+	}
+
+	var fsys fs.FS
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == contentTypeZip {
+		if fsys, err = zip.BytesFS(body); err != nil {
+			return nil, err
+		}
+	} else {
+		fsys = fstest.MapFS{input: {Data: body}}
+	}
+
+	model, err := createModelFunc(fsys, input)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if validate {
+		if err = model.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err = checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	zipBytes, err := v1.Model2BundleZipBytes(model)
+	if err != nil {
+		return nil, err
+	}
+	return zipResponse(zipBytes), nil
+}
+
+func oasOverlay(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	request := OverlayRequest{}
+	err := decodeJSON(body, &request)
+	if err != nil {
+		return nil, err
+	}
+	if err = requireFile(request.Files, "spec", request.Spec); err != nil {
+		return nil, err
+	}
+	if err = requireFile(request.Files, "overlay", request.Overlay); err != nil {
+		return nil, err
+	}
+
+	ext := path.Ext(request.Spec)
+	outputText, err := utils.OASOverlayText([]byte(request.Files[request.Overlay]), []byte(request.Files[request.Spec]), request.Overlay, request.Spec, ext)
+	if err != nil {
+		return nil, err
+	}
+	return textResponse(outputText, ext), nil
+}
+
+func resolveRefs(ctx context.Context, r *http.Request, body []byte) (*response, error) {
+	request := ResolveRefsRequest{}
+	err := decodeJSON(body, &request)
+	if err != nil {
+		return nil, err
+	}
+	if err = requireFile(request.Files, "input", request.Input); err != nil {
+		return nil, err
+	}
+
+	fsys, err := filesFS(request.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := path.Ext(request.Input)
+	outputText, err := utils.ResolveDollarRefsText(fsys, []byte(request.Files[request.Input]), request.Input, request.AllowCycles, ext)
+	if err != nil {
+		return nil, err
+	}
+	return textResponse(outputText, ext), nil
+}
+
+// mergeValues deep merges the posted JSON values into the template values
+func mergeValues(cFlags *render.CommonFlags, values json.RawMessage) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	// JSON is valid YAML
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(values, &node); err != nil {
+		return badRequest(`invalid "values". %s`, err.Error())
+	}
+	if err := cFlags.Values.Merge(&node, "values", nil); err != nil {
+		return &badRequestError{Err: err}
+	}
+	return nil
+}
+
+// resolveSpec resolves the external JSON $refs of the spec within the posted files.
+// This way, the OpenAPI parser never follows file references outside the request.
+func resolveSpec(fsys fs.FS, specFile string) ([]byte, error) {
+	specText, err := fs.ReadFile(fsys, specFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return utils.ResolveDollarRefsText(fsys, specText, specFile, false, path.Ext(specFile))
+}
+
+// bundleResponse returns the bundle zip, or the bundle YAML document, unless the request has timed out after building the bundle
+func bundleResponse(ctx context.Context, bundle *render.Bundle, format string) (*response, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if format == "yaml" {
+		yamlText, err := bundle.Model.YAML()
+		if err != nil {
+			return nil, err
+		}
+		return yamlResponse(yamlText), nil
+	}
+
+	zipBytes, err := bundle.Zip()
+	if err != nil {
+		return nil, err
+	}
+	return zipResponse(zipBytes), nil
+}
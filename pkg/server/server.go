@@ -0,0 +1,291 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/go-errors/errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"testing/fstest"
+	"time"
+)
+
+// DefaultMaxRequestSize is the default limit for the size of request bodies (10 MiB)
+const DefaultMaxRequestSize = 10 << 20
+
+// DefaultMaxOutputSize is the default limit for the size of rendered templates and response bodies (50 MiB)
+const DefaultMaxOutputSize = 50 << 20
+
+// DefaultTimeout is the default time limit for processing each request
+const DefaultTimeout = 30 * time.Second
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeYAML = "application/yaml"
+	contentTypeZip  = "application/zip"
+)
+
+// Options configures the HTTP handler
+type Options struct {
+	// MaxRequestSize is the limit for the size of request bodies in bytes, requests above it are rejected
+	MaxRequestSize int64
+
+	// MaxOutputSize is the limit for the size of rendered templates and response bodies in bytes
+	MaxOutputSize int64
+
+	// Timeout is the time limit for processing each request, rendering is stopped once it passes
+	Timeout time.Duration
+}
+
+// NewHandler returns an HTTP handler that exposes render, transform and mock generation as a JSON API.
+// Each request is processed in memory, from the files posted with it, without using the working directory or temp dirs.
+func NewHandler(options Options) http.Handler {
+	if options.MaxRequestSize <= 0 {
+		options.MaxRequestSize = DefaultMaxRequestSize
+	}
+	if options.MaxOutputSize <= 0 {
+		options.MaxOutputSize = DefaultMaxOutputSize
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	s := &server{options: options}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/render/apiproxy", s.handle(renderAPIProxy))
+	mux.HandleFunc("POST /v1/render/sharedflow", s.handle(renderSharedFlow))
+	mux.HandleFunc("POST /v1/transform/apiproxy-to-yaml", s.handle(apiProxyToYAML))
+	mux.HandleFunc("POST /v1/transform/sharedflow-to-yaml", s.handle(sharedFlowToYAML))
+	mux.HandleFunc("POST /v1/transform/yaml-to-apiproxy", s.handle(yamlToAPIProxy))
+	mux.HandleFunc("POST /v1/transform/yaml-to-sharedflow", s.handle(yamlToSharedFlow))
+	mux.HandleFunc("POST /v1/transform/oas-overlay", s.handle(oasOverlay))
+	mux.HandleFunc("POST /v1/transform/resolve-refs", s.handle(resolveRefs))
+	mux.HandleFunc("POST /v1/mock/oas", s.handle(mockOAS))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, errors.Errorf("%s %s not found", r.Method, r.URL.Path))
+	})
+	return mux
+}
+
+type server struct {
+	options Options
+}
+
+// response is the body returned by an operation
+type response struct {
+	contentType string
+	body        []byte
+}
+
+// handlerFunc is an operation, it checks ctx between its stages (see checkContext), so that it stops once the request has timed out
+type handlerFunc func(ctx context.Context, r *http.Request, body []byte) (*response, error)
+
+// handle reads the (size limited) request body, calls the operation, and writes its response or errors.
+// The operation runs with a deadline, if it has not returned by then, the request fails without waiting for it,
+// and the operation stops at its next stage.
+func (s *server) handle(handler handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.options.MaxRequestSize))
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				writeError(w, http.StatusRequestEntityTooLarge, errors.Errorf("request body is larger than %d bytes", maxBytesError.Limit))
+				return
+			}
+			writeError(w, http.StatusBadRequest, errors.New(err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.options.Timeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, optionsKey{}, s.options)
+
+		done := make(chan result, 1)
+		go func() {
+			done <- s.process(ctx, handler, r, body)
+		}()
+
+		var res result
+		select {
+		case res = <-done:
+		case <-ctx.Done():
+			writeError(w, http.StatusServiceUnavailable, errors.Errorf("request did not complete within %s", s.options.Timeout))
+			return
+		}
+
+		if res.err != nil {
+			writeError(w, res.status, res.err)
+			return
+		}
+
+		w.Header().Set("Content-Type", res.response.contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(res.response.body)
+	}
+}
+
+// result is the outcome of an operation, with the HTTP status for its error
+type result struct {
+	response *response
+	status   int
+	err      error
+}
+
+// process calls the operation, and maps its errors to HTTP statuses
+func (s *server) process(ctx context.Context, handler handlerFunc, r *http.Request, body []byte) (res result) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			res = result{status: http.StatusInternalServerError, err: errors.Errorf("%v", recovered)}
+		}
+	}()
+
+	resp, err := handler(ctx, r, body)
+	if err != nil {
+		var badRequest *badRequestError
+		var limitError *render.LimitError
+		if errors.As(err, &badRequest) {
+			return result{status: http.StatusBadRequest, err: badRequest.Err}
+		} else if (errors.As(err, &limitError) && limitError.Timeout) || errors.Is(err, context.DeadlineExceeded) {
+			return result{status: http.StatusServiceUnavailable, err: errors.Errorf("request did not complete within %s", s.options.Timeout)}
+		} else if errors.As(err, &limitError) {
+			return result{status: http.StatusUnprocessableEntity, err: limitError}
+		}
+		return result{status: http.StatusUnprocessableEntity, err: err}
+	}
+
+	if int64(len(resp.body)) > s.options.MaxOutputSize {
+		return result{status: http.StatusUnprocessableEntity, err: errors.Errorf("response is larger than %d bytes", s.options.MaxOutputSize)}
+	}
+	return result{response: resp}
+}
+
+type optionsKey struct{}
+
+// checkContext fails once the request has timed out (or the client has gone away), so that operations stop between stages
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// newRenderFlags returns the flags for rendering posted templates, in a sandbox, and within the limits of the request
+func newRenderFlags(ctx context.Context) *render.CommonFlags {
+	cFlags := render.NewCommonFlags()
+	cFlags.Sandbox = true
+	if options, ok := ctx.Value(optionsKey{}).(Options); ok {
+		cFlags.MaxOutputSize = options.MaxOutputSize
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		cFlags.Deadline = deadline
+	}
+	return cFlags
+}
+
+// badRequestError is an error in the request itself (e.g. missing fields), as opposed to an error processing its contents
+type badRequestError struct {
+	Err error
+}
+
+func (e *badRequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *badRequestError) Unwrap() error {
+	return e.Err
+}
+
+func badRequest(format string, args ...any) error {
+	return &badRequestError{Err: errors.Errorf(format, args...)}
+}
+
+// decodeJSON decodes the JSON request body, unknown fields are rejected
+func decodeJSON(body []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &badRequestError{Err: errors.Errorf("invalid JSON request. %s", err.Error())}
+	}
+	return nil
+}
+
+// filesFS returns the posted files as an in memory file system
+func filesFS(files map[string]string) (fs.FS, error) {
+	fsys := fstest.MapFS{}
+	for name, contents := range files {
+		if !fs.ValidPath(name) || name == "." {
+			return nil, badRequest(`invalid file name "%s", it must be a relative slash-separated path without ".." elements`, name)
+		}
+		fsys[name] = &fstest.MapFile{Data: []byte(contents), Mode: 0644}
+	}
+	return fsys, nil
+}
+
+// requireFile checks that the named file was posted along with the request
+func requireFile(files map[string]string, field string, name string) error {
+	if name == "" {
+		return badRequest(`missing "%s" field`, field)
+	}
+	if _, found := files[name]; !found {
+		return badRequest(`"%s" file "%s" not found in "files"`, field, name)
+	}
+	return nil
+}
+
+func queryBool(r *http.Request, name string, defaultValue bool) (bool, error) {
+	switch r.URL.Query().Get(name) {
+	case "":
+		return defaultValue, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, badRequest(`query param "%s" must be "true" or "false"`, name)
+}
+
+func queryEnum(r *http.Request, name string, allowed ...string) (string, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return allowed[0], nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return value, nil
+		}
+	}
+	return "", badRequest(`query param "%s" must be one of %v`, name, allowed)
+}
+
+func zipResponse(body []byte) *response {
+	return &response{contentType: contentTypeZip, body: body}
+}
+
+func yamlResponse(body []byte) *response {
+	return &response{contentType: contentTypeYAML, body: body}
+}
+
+// textResponse returns JSON for the ".json" extension, or else YAML, same as the text transforms
+func textResponse(body []byte, ext string) *response {
+	if ext == ".json" {
+		return &response{contentType: contentTypeJSON, body: body}
+	}
+	return yamlResponse(body)
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, handler http.Handler, target string, contentType string, body []byte) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func postJSON(t *testing.T, handler http.Handler, target string, body any) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+	return post(t, handler, target, contentTypeJSON, jsonBody)
+}
+
+func requireErrors(t *testing.T, recorder *httptest.ResponseRecorder, status int, messages ...string) {
+	require.Equal(t, status, recorder.Code)
+	require.Equal(t, contentTypeJSON, recorder.Header().Get("Content-Type"))
+
+	response := ErrorResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Errors, len(messages))
+	for i, message := range messages {
+		require.Contains(t, response.Errors[i].Message, message)
+	}
+}
+
+func TestRenderAPIProxy(t *testing.T) {
+	handler := NewHandler(Options{})
+
+	request := RenderRequest{
+		Files: map[string]string{
+			"apiproxy.yaml": `APIProxy:
+  .revision: 1
+  .name: {{ .Values.name }}
+  Description: {{ .Values.spec.info.title }}
+Policies: []
+ProxyEndpoints: []
+TargetEndpoints: []
+Resources:
+  #{{ os_writefile "./openapi.yaml" .Values.spec_string }}
+  - Resource:
+      Type: oas
+      Path: ./openapi.yaml
+`,
+			"specs/openapi.yaml": "openapi: 3.0.0\ninfo:\n  title: Pets\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n    Pet:\n      $ref: ./pet.yaml\n",
+			"specs/pet.yaml":     "type: object\n",
+		},
+		Template: "apiproxy.yaml",
+		Values:   json.RawMessage(`{"name": "pets"}`),
+		Specs:    map[string]string{"spec": "specs/openapi.yaml"},
+	}
+
+	recorder := postJSON(t, handler, "/v1/render/apiproxy", request)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.Equal(t, contentTypeZip, recorder.Header().Get("Content-Type"))
+
+	bundleFS, err := zip.BytesFS(recorder.Body.Bytes())
+	require.NoError(t, err)
+	proxyXML, err := fs.ReadFile(bundleFS, "apiproxy/pets.xml")
+	require.NoError(t, err)
+	require.Contains(t, string(proxyXML), "<Description>Pets</Description>")
+	specText, err := fs.ReadFile(bundleFS, "apiproxy/resources/oas/openapi.yaml")
+	require.NoError(t, err)
+	require.Contains(t, string(specText), "type: object")
+
+	recorder = postJSON(t, handler, "/v1/render/apiproxy?format=yaml", request)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.Equal(t, contentTypeYAML, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), ".name: pets")
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	handler := NewHandler(Options{})
+	bundlesDir := filepath.Join("..", "apiproxy", "testdata", "bundles")
+
+	zipBytes := utils.MustReadFileBytes(filepath.Join(bundlesDir, "helloworld", "apiproxy.zip"))
+	recorder := post(t, handler, "/v1/transform/apiproxy-to-yaml", contentTypeZip, zipBytes)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.YAMLEq(t, string(utils.MustReadFileBytes(filepath.Join(bundlesDir, "helloworld", "apiproxy.yaml"))), recorder.Body.String())
+
+	recorder = post(t, handler, "/v1/transform/yaml-to-apiproxy?validate=false", contentTypeYAML, recorder.Body.Bytes())
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.Equal(t, contentTypeZip, recorder.Header().Get("Content-Type"))
+
+	// bundles with resources are returned as a zip with the YAML document next to them
+	zipBytes = utils.MustReadFileBytes(filepath.Join("..", "mock", "testdata", "mocks", "petstore", "exp-apiproxy.zip"))
+	recorder = post(t, handler, "/v1/transform/apiproxy-to-yaml", contentTypeZip, zipBytes)
+	requireErrors(t, recorder, http.StatusUnprocessableEntity, "use format=zip")
+
+	recorder = post(t, handler, "/v1/transform/apiproxy-to-yaml?format=zip", contentTypeZip, zipBytes)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	yamlFS, err := zip.BytesFS(recorder.Body.Bytes())
+	require.NoError(t, err)
+	_, err = fs.Stat(yamlFS, "openapi.json")
+	require.NoError(t, err)
+
+	recorder = post(t, handler, "/v1/transform/yaml-to-apiproxy?validate=false", contentTypeZip, recorder.Body.Bytes())
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+}
+
+func TestOASOverlay(t *testing.T) {
+	handler := NewHandler(Options{})
+
+	recorder := postJSON(t, handler, "/v1/transform/oas-overlay", OverlayRequest{
+		Files: map[string]string{
+			"openapi.json": `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0.0"}, "paths": {}}`,
+			"overlay.yaml": "overlay: 1.0.0\nactions:\n  - target: $.info\n    update:\n      title: Animals\n",
+		},
+		Spec:    "openapi.json",
+		Overlay: "overlay.yaml",
+	})
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.Equal(t, contentTypeJSON, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), `"title": "Animals"`)
+}
+
+func TestResolveRefs(t *testing.T) {
+	handler := NewHandler(Options{})
+
+	recorder := postJSON(t, handler, "/v1/transform/resolve-refs", ResolveRefsRequest{
+		Files: map[string]string{
+			"openapi.yaml":     "components:\n  schemas:\n    Pet:\n      $ref: ./schemas/pet.yaml\n",
+			"schemas/pet.yaml": "type: object\n",
+		},
+		Input: "openapi.yaml",
+	})
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.YAMLEq(t, "components:\n  schemas:\n    Pet:\n      type: object\n", recorder.Body.String())
+
+	// refs cannot reach files outside the request
+	recorder = postJSON(t, handler, "/v1/transform/resolve-refs", ResolveRefsRequest{
+		Files: map[string]string{"openapi.yaml": "info:\n  $ref: /etc/hostname\n"},
+		Input: "openapi.yaml",
+	})
+	requireErrors(t, recorder, http.StatusUnprocessableEntity, "could not process JSONRef /etc/hostname")
+}
+
+func TestErrors(t *testing.T) {
+	handler := NewHandler(Options{MaxRequestSize: 1024})
+
+	recorder := post(t, handler, "/v1/transform/apiproxy-to-yaml", contentTypeZip, bytes.Repeat([]byte("x"), 2048))
+	requireErrors(t, recorder, http.StatusRequestEntityTooLarge, "request body is larger than 1024 bytes")
+
+	recorder = post(t, handler, "/v1/render/apiproxy", contentTypeJSON, []byte(`{"unknown": true}`))
+	requireErrors(t, recorder, http.StatusBadRequest, `unknown field "unknown"`)
+
+	recorder = postJSON(t, handler, "/v1/transform/resolve-refs", ResolveRefsRequest{
+		Files: map[string]string{"../openapi.yaml": "{}"},
+		Input: "../openapi.yaml",
+	})
+	requireErrors(t, recorder, http.StatusBadRequest, `invalid file name "../openapi.yaml"`)
+
+	recorder = postJSON(t, handler, "/v1/render/apiproxy", RenderRequest{Files: map[string]string{}, Template: "apiproxy.yaml"})
+	requireErrors(t, recorder, http.StatusBadRequest, `"template" file "apiproxy.yaml" not found`)
+
+	recorder = post(t, handler, "/v1/unknown", contentTypeJSON, nil)
+	requireErrors(t, recorder, http.StatusNotFound, "POST /v1/unknown not found")
+
+	// validation errors are listed one by one, along with their position
+	yamlText := strings.Join([]string{
+		"APIProxy:",
+		"  .name: example",
+		"  Unknown: value",
+		"Policies: []",
+		"ProxyEndpoints: []",
+		"TargetEndpoints: []",
+	}, "\n")
+	recorder = post(t, handler, "/v1/transform/yaml-to-apiproxy", contentTypeYAML, []byte(yamlText))
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code, recorder.Body.String())
+
+	response := ErrorResponse{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Errors)
+	require.NotNil(t, response.Errors[0].Position)
+	require.Equal(t, 3, response.Errors[0].Position.Line)
+}
+
+func TestMockOAS(t *testing.T) {
+	handler := NewHandler(Options{})
+	specFile := filepath.Join("..", "utils", "testdata", "specs", "oas3", "petstore", "oas3.yaml")
+
+	recorder := postJSON(t, handler, "/v1/mock/oas", MockRequest{
+		Files: map[string]string{"oas3.yaml": string(utils.MustReadFileBytes(specFile))},
+		Spec:  "oas3.yaml",
+	})
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	bundleFS, err := zip.BytesFS(recorder.Body.Bytes())
+	require.NoError(t, err)
+	_, err = fs.Stat(bundleFS, "apiproxy/resources/oas/openapi.json")
+	require.NoError(t, err)
+}
+
+func TestRenderSandbox(t *testing.T) {
+	handler := NewHandler(Options{})
+	t.Setenv("APIGEE_GO_GEN_SECRET", "secret")
+
+	for _, text := range []string{`{{ os_getenv "APIGEE_GO_GEN_SECRET" }}`, `{{ os_getenvs }}`, `{{ env "APIGEE_GO_GEN_SECRET" }}`, `{{ expandenv "$APIGEE_GO_GEN_SECRET" }}`, `{{ fmt_printf "hello" }}`} {
+		recorder := postJSON(t, handler, "/v1/render/apiproxy", RenderRequest{
+			Files:    map[string]string{"apiproxy.yaml": "APIProxy:\n  .name: example\n  Description: " + text + "\n"},
+			Template: "apiproxy.yaml",
+		})
+		requireErrors(t, recorder, http.StatusUnprocessableEntity, "not defined")
+		require.NotContains(t, recorder.Body.String(), "secret")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	renderRequest := func(text string) RenderRequest {
+		return RenderRequest{
+			Files:    map[string]string{"apiproxy.yaml": "APIProxy:\n  .name: example\n  Description: " + text + "\n"},
+			Template: "apiproxy.yaml",
+		}
+	}
+
+	handler := NewHandler(Options{MaxOutputSize: 1024})
+	recorder := postJSON(t, handler, "/v1/render/apiproxy", renderRequest(`{{ range until 100 }}{{ repeat 20 "x" }}{{ end }}`))
+	requireErrors(t, recorder, http.StatusUnprocessableEntity, "rendered output is larger than 1024 bytes")
+
+	// lists are checked before they are created
+	recorder = postJSON(t, handler, "/v1/render/apiproxy", renderRequest(`{{ range until 1000000000 }}x{{ end }}`))
+	requireErrors(t, recorder, http.StatusUnprocessableEntity, "rendered output is larger than 1024 bytes")
+
+	zipBytes := utils.MustReadFileBytes(filepath.Join("..", "apiproxy", "testdata", "bundles", "helloworld", "apiproxy.zip"))
+	recorder = post(t, NewHandler(Options{MaxOutputSize: 100}), "/v1/transform/apiproxy-to-yaml", contentTypeZip, zipBytes)
+	requireErrors(t, recorder, http.StatusUnprocessableEntity, "response is larger than 100 bytes")
+
+	handler = NewHandler(Options{MaxOutputSize: 1 << 30, Timeout: 10 * time.Millisecond})
+	recorder = postJSON(t, handler, "/v1/render/apiproxy", renderRequest(`{{ range until 10000 }}{{ range until 10000 }}x{{ end }}{{ end }}`))
+	requireErrors(t, recorder, http.StatusServiceUnavailable, "request did not complete within 10ms")
+}
+
+func TestCanceledRequest(t *testing.T) {
+	body, err := json.Marshal(RenderRequest{
+		Files:    map[string]string{"apiproxy.yaml": "APIProxy:\n  .name: example\n"},
+		Template: "apiproxy.yaml",
+	})
+	require.NoError(t, err)
+
+	// operations stop at their next stage once the request is done, instead of running to completion in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = renderAPIProxy(ctx, httptest.NewRequest(http.MethodPost, "/v1/render/apiproxy", nil), body)
+	require.ErrorIs(t, err, context.Canceled)
+}
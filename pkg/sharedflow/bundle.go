@@ -22,15 +22,23 @@ import (
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
 	"io/fs"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 )
 
+// Bundle2YAMLFile converts the bundle zip or dir to a YAML document. Zips are checked against zip.DefaultLimits before reading them.
 func Bundle2YAMLFile(bundle string, outputFile string, dryRun bool) error {
+	return Bundle2YAMLFileLimits(bundle, outputFile, dryRun, zip.DefaultLimits)
+}
+
+// Bundle2YAMLFileLimits is like Bundle2YAMLFile, but zips are checked against the given limits
+func Bundle2YAMLFileLimits(bundle string, outputFile string, dryRun bool, limits zip.Limits) error {
 	extension := filepath.Ext(bundle)
 	if extension == ".zip" {
-		err := BundleZip2YAMLFile(bundle, outputFile, dryRun)
+		err := BundleZip2YAMLFileLimits(bundle, outputFile, dryRun, limits)
 		if err != nil {
 			return err
 		}
@@ -46,27 +54,33 @@ func Bundle2YAMLFile(bundle string, outputFile string, dryRun bool) error {
 	return nil
 }
 
+// BundleZip2YAMLFile reads the bundle zip in memory, without extracting it.
+// Entries with paths outside the zip root, symlinks, and zips above zip.DefaultLimits are rejected.
 func BundleZip2YAMLFile(inputZip string, outputFile string, dryRun bool) error {
-	tmpDir, err := os.MkdirTemp("", "unzipped-bundle-*")
+	return BundleZip2YAMLFileLimits(inputZip, outputFile, dryRun, zip.DefaultLimits)
+}
+
+// BundleZip2YAMLFileLimits is like BundleZip2YAMLFile, but the zip is checked against the given limits
+func BundleZip2YAMLFileLimits(inputZip string, outputFile string, dryRun bool, limits zip.Limits) error {
+	zipBytes, err := os.ReadFile(inputZip)
 	if err != nil {
 		return errors.New(err)
 	}
 
-	err = zip.Unzip(tmpDir, inputZip)
+	fSys, err := zip.BytesFSLimits(zipBytes, limits)
 	if err != nil {
-		return errors.New(err)
+		return errors.Errorf("%s: %s", inputZip, err.Error())
 	}
 
-	return BundleDir2YAMLFile(tmpDir, outputFile, dryRun)
+	docBytes, resources, err := BundleFS2YAML(fSys)
+	if err != nil {
+		return err
+	}
 
+	return writeYAMLFile(docBytes, resources, outputFile, dryRun)
 }
 
 func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
-	policyFiles := []string{}
-	sharedFlowsFiles := []string{}
-	resourcesFiles := []string{}
-	manifestFiles := []string{}
-
 	sharedFlowBundleDir := filepath.Join(inputDir, "sharedflowbundle")
 	stat, err := os.Stat(sharedFlowBundleDir)
 	if err != nil {
@@ -75,7 +89,78 @@ func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
 		return errors.Errorf("%s is not a directory", sharedFlowBundleDir)
 	}
 
-	fSys := os.DirFS(sharedFlowBundleDir)
+	docBytes, resources, err := bundleFS2YAML(os.DirFS(sharedFlowBundleDir), sharedFlowBundleDir)
+	if err != nil {
+		return err
+	}
+
+	return writeYAMLFile(docBytes, resources, outputFile, dryRun)
+}
+
+// writeYAMLFile writes the YAML document (or prints it for dry runs), along with its resource files
+func writeYAMLFile(docBytes []byte, resources map[string][]byte, outputFile string, dryRun bool) error {
+	//copy resource files
+	outputDir := filepath.Dir(outputFile)
+	for _, fileName := range slices.Sorted(maps.Keys(resources)) {
+		err := os.MkdirAll(outputDir, os.ModePerm)
+		if err != nil {
+			return errors.New(err)
+		}
+		err = os.WriteFile(filepath.Join(outputDir, fileName), resources[fileName], os.ModePerm)
+		if err != nil {
+			return errors.New(err)
+		}
+	}
+
+	if dryRun {
+		fmt.Print(string(docBytes))
+		return nil
+	}
+
+	err := os.MkdirAll(outputDir, os.ModePerm)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	err = os.WriteFile(outputFile, docBytes, os.ModePerm)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// BundleZip2YAML is like BundleFS2YAML, for the bundle zip bytes
+func BundleZip2YAML(zipBytes []byte) ([]byte, map[string][]byte, error) {
+	fSys, err := zip.BytesFS(zipBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return BundleFS2YAML(fSys)
+}
+
+// BundleFS2YAML is like BundleDir2YAMLFile, but it does not touch the local disk.
+// The "sharedflowbundle" directory must be at the root of fsys. It returns the YAML document,
+// and the resource files (by file name) that the document expects to find next to it.
+func BundleFS2YAML(fsys fs.FS) ([]byte, map[string][]byte, error) {
+	fSys, err := fs.Sub(fsys, "sharedflowbundle")
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+	if stat, err := fs.Stat(fSys, "."); err != nil {
+		return nil, nil, errors.Errorf("sharedflowbundle not found. %s", err.Error())
+	} else if !stat.IsDir() {
+		return nil, nil, errors.Errorf("sharedflowbundle is not a directory")
+	}
+	return bundleFS2YAML(fSys, "sharedflowbundle")
+}
+
+// bundleFS2YAML converts the files within fSys, which is rooted at the sharedflowbundle directory. The sharedFlowBundleDir is used in error messages.
+func bundleFS2YAML(fSys fs.FS, sharedFlowBundleDir string) ([]byte, map[string][]byte, error) {
+	policyFiles := []string{}
+	sharedFlowsFiles := []string{}
+	resourcesFiles := []string{}
+	manifestFiles := []string{}
 
 	manifestFiles, _ = fs.Glob(fSys, "*.xml")
 	policyFiles, _ = fs.Glob(fSys, "policies/*.xml")
@@ -84,7 +169,7 @@ func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
 
 	allFiles := []string{}
 	if len(manifestFiles) == 0 {
-		return errors.Errorf("no shared flow XML file found in %s", sharedFlowBundleDir)
+		return nil, nil, errors.Errorf("no shared flow XML file found in %s", sharedFlowBundleDir)
 	}
 
 	allFiles = append(allFiles, manifestFiles[0])
@@ -97,8 +182,7 @@ func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
 	}
 
 	fileToYAML := func(filePath string) (*yaml.Node, error) {
-		fullPath := filepath.Join(sharedFlowBundleDir, filePath)
-		fileContents, err := os.ReadFile(fullPath)
+		fileContents, err := fs.ReadFile(fSys, filePath)
 		if err != nil {
 			return nil, errors.New(err)
 		}
@@ -130,25 +214,26 @@ func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
 
 	manifestNode, err := fileToYAML(manifestFiles[0])
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	mainNode.Content = append(mainNode.Content, manifestNode.Content...)
 
 	err = addSequence(mainNode, "Policies", policyFiles)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	err = addSequence(mainNode, "SharedFlows", sharedFlowsFiles)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	//copy resource files
+	//collect resource files
+	resources := map[string][]byte{}
 	resourcesNode := createMapEntry(mainNode, "Resources", &yaml.Node{Kind: yaml.SequenceNode})
 	for _, resourceFile := range resourcesFiles {
-		dirName, fileName := filepath.Split(resourceFile)
-		fileType := filepath.Base(dirName)
+		dirName, fileName := path.Split(resourceFile)
+		fileType := path.Base(dirName)
 
 		location := path.Join(".", fileName)
 		resourceNode := &yaml.Node{Kind: yaml.MappingNode}
@@ -156,27 +241,16 @@ func BundleDir2YAMLFile(inputDir string, outputFile string, dryRun bool) error {
 		createMapEntry(resourceDataNode, "Type", &yaml.Node{Kind: yaml.ScalarNode, Value: fileType})
 		createMapEntry(resourceDataNode, "Path", &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprintf("./%s", location)})
 		resourcesNode.Content = append(resourcesNode.Content, resourceNode)
-		outputDir := filepath.Dir(outputFile)
-		err := utils.CopyFile(filepath.Join(outputDir, fileName), filepath.Join(sharedFlowBundleDir, resourceFile))
+		resources[fileName], err = fs.ReadFile(fSys, resourceFile)
 		if err != nil {
-			return err
+			return nil, nil, errors.New(err)
 		}
 	}
 
 	var docBytes []byte
 	if docBytes, err = utils.YAML2Text(docNode, 2); err != nil {
-		return err
-	}
-
-	if dryRun {
-		fmt.Print(string(docBytes))
-		return nil
+		return nil, nil, err
 	}
 
-	err = utils.YAMLDoc2File(docNode, outputFile)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return docBytes, resources, nil
 }
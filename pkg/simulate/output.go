@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-errors/errors"
+)
+
+func Text(result *Result) []byte {
+	var buffer bytes.Buffer
+	_, _ = fmt.Fprintf(&buffer, "ProxyEndpoint: %s (BasePath: %s)\n", result.ProxyEndpoint, result.BasePath)
+	_, _ = fmt.Fprintf(&buffer, "Flow: %s\n", valueOrNone(result.Flow))
+	_, _ = fmt.Fprintf(&buffer, "RouteRule: %s\n", valueOrNone(result.RouteRule))
+	if result.IntegrationEndpoint != "" {
+		_, _ = fmt.Fprintf(&buffer, "IntegrationEndpoint: %s\n", result.IntegrationEndpoint)
+	} else {
+		_, _ = fmt.Fprintf(&buffer, "TargetEndpoint: %s\n", valueOrNone(result.TargetEndpoint))
+	}
+	if result.TargetEndpoint != "" {
+		_, _ = fmt.Fprintf(&buffer, "TargetFlow: %s\n", valueOrNone(result.TargetFlow))
+		_, _ = fmt.Fprintf(&buffer, "TargetURL: %s\n", valueOrNone(result.TargetURL))
+	}
+
+	if result.Fault != "" {
+		_, _ = fmt.Fprintf(&buffer, "Fault: %s (FaultRule: %s)\n", result.Fault, valueOrNone(result.FaultRule))
+	}
+
+	buffer.WriteString("Steps:\n")
+	for _, step := range result.Steps {
+		mark := "+"
+		if !step.Executed {
+			mark = "-"
+		}
+		_, _ = fmt.Fprintf(&buffer, "  %s %s", mark, step.Policy)
+		if step.Type != "" {
+			_, _ = fmt.Fprintf(&buffer, " [%s]", step.Type)
+		}
+		_, _ = fmt.Fprintf(&buffer, " at %s", step.Phase)
+		if step.Disabled {
+			buffer.WriteString(" (skipped, disabled)")
+		} else if !step.Executed {
+			_, _ = fmt.Fprintf(&buffer, " (skipped, condition: %s)", step.Condition)
+		}
+		buffer.WriteString("\n")
+	}
+	return buffer.Bytes()
+}
+
+func JSON(result *Result) ([]byte, error) {
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return append(text, '\n'), nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
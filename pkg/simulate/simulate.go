@@ -0,0 +1,508 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulate
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/condition"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/go-errors/errors"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Request describes the incoming client request to simulate
+type Request struct {
+	Verb      string
+	Path      string
+	Headers   map[string]string
+	Query     map[string]string
+	Variables map[string]any
+}
+
+// StepResult is a single step that was reached during the simulation
+type StepResult struct {
+	Phase     string `json:"phase"`
+	Policy    string `json:"policy"`
+	Type      string `json:"type,omitempty"`
+	Condition string `json:"condition,omitempty"`
+	Disabled  bool   `json:"disabled,omitempty"`
+	Executed  bool   `json:"executed"`
+}
+
+// Result is the outcome of simulating a request against an API proxy
+type Result struct {
+	ProxyEndpoint       string       `json:"proxyEndpoint"`
+	BasePath            string       `json:"basePath"`
+	PathSuffix          string       `json:"pathSuffix"`
+	Flow                string       `json:"flow,omitempty"`
+	RouteRule           string       `json:"routeRule,omitempty"`
+	TargetEndpoint      string       `json:"targetEndpoint,omitempty"`
+	IntegrationEndpoint string       `json:"integrationEndpoint,omitempty"`
+	TargetFlow          string       `json:"targetFlow,omitempty"`
+	TargetURL           string       `json:"targetURL,omitempty"`
+	Fault               string       `json:"fault,omitempty"`
+	FaultRule           string       `json:"faultRule,omitempty"`
+	Steps               []StepResult `json:"steps"`
+}
+
+// Policies returns the names of the policies that executed, in order
+func (r *Result) Policies() []string {
+	var result []string
+	for _, step := range r.Steps {
+		if step.Executed {
+			result = append(result, step.Policy)
+		}
+	}
+	return result
+}
+
+type simulation struct {
+	model        *v1.APIProxyModel
+	vars         map[string]any
+	result       *Result
+	fault        string
+	faultHandled bool
+}
+
+// Simulate walks the API proxy the same way the Apigee runtime would for the given request,
+// and returns the endpoints, flows and steps that are selected along the way.
+// Policies themselves are not executed, so flow variables they would set are not available to later conditions.
+func Simulate(model v1.Model, request *Request) (*Result, error) {
+	proxyModel, ok := model.(*v1.APIProxyModel)
+	if !ok {
+		return nil, errors.New("simulation is only supported for API proxies")
+	}
+
+	requestURL, err := url.Parse(request.Path)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	path := requestURL.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	query := requestURL.Query()
+	for name, value := range request.Query {
+		query.Set(name, value)
+	}
+
+	proxyEndpoint, basePath := selectProxyEndpoint(proxyModel, path)
+	if proxyEndpoint == nil {
+		return nil, errors.Errorf(`no proxy endpoint has a base path matching "%s"`, path)
+	}
+
+	verb := strings.ToUpper(request.Verb)
+	if verb == "" {
+		verb = "GET"
+	}
+
+	s := &simulation{
+		model: proxyModel,
+		vars:  map[string]any{},
+		result: &Result{
+			ProxyEndpoint: proxyEndpoint.Name,
+			BasePath:      basePath,
+			PathSuffix:    strings.TrimPrefix(path, strings.TrimSuffix(basePath, "/")),
+			Steps:         []StepResult{},
+		},
+	}
+
+	s.vars["request.verb"] = verb
+	s.vars["request.path"] = path
+	s.vars["request.uri"] = requestURL.RequestURI()
+	s.vars["request.querystring"] = query.Encode()
+	s.vars["proxy.basepath"] = basePath
+	s.vars["proxy.pathsuffix"] = s.result.PathSuffix
+	s.vars["proxy.name"] = proxyEndpoint.Name
+	s.vars["apiproxy.name"] = proxyModel.Name()
+	for name, value := range request.Headers {
+		s.vars["request.header."+name] = value
+		s.vars["request.header."+strings.ToLower(name)] = value
+	}
+	for name := range query {
+		s.vars["request.queryparam."+name] = query.Get(name)
+	}
+	for name, value := range request.Variables {
+		s.vars[name] = value
+	}
+
+	if err = s.run(proxyEndpoint); err != nil {
+		return nil, err
+	}
+	return s.result, nil
+}
+
+// selectProxyEndpoint returns the proxy endpoint with the longest base path that matches the request path
+func selectProxyEndpoint(model *v1.APIProxyModel, path string) (*v1.ProxyEndpoint, string) {
+	var selected *v1.ProxyEndpoint
+	var selectedBasePath string
+	for _, proxyEndpoint := range model.ProxyEndpoints.List {
+		basePath := "/"
+		if proxyEndpoint.HTTPProxyConnection != nil && proxyEndpoint.HTTPProxyConnection.BasePath != "" {
+			basePath = proxyEndpoint.HTTPProxyConnection.BasePath
+		}
+		if !matchBasePath(basePath, path) {
+			continue
+		}
+		if selected == nil || len(strings.TrimSuffix(basePath, "/")) > len(strings.TrimSuffix(selectedBasePath, "/")) {
+			selected = proxyEndpoint
+			selectedBasePath = basePath
+		}
+	}
+	return selected, selectedBasePath
+}
+
+// matchBasePath checks if the base path is a prefix of the path, on a segment boundary
+func matchBasePath(basePath string, path string) bool {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" {
+		return true
+	}
+	return path == basePath || strings.HasPrefix(path, basePath+"/")
+}
+
+func (s *simulation) run(proxyEndpoint *v1.ProxyEndpoint) error {
+	proxyPath := fmt.Sprintf("ProxyEndpoint(name: %s)", proxyEndpoint.Name)
+
+	var proxyFlow *v1.Flow
+	if proxyEndpoint.Flows != nil {
+		var err error
+		if proxyFlow, err = s.selectFlow(proxyEndpoint.Flows.List, proxyPath); err != nil {
+			return err
+		}
+	}
+	if proxyFlow != nil {
+		s.result.Flow = proxyFlow.Name
+		s.vars["current.flow.name"] = proxyFlow.Name
+	}
+
+	//proxy request
+	if proxyEndpoint.PreFlow != nil {
+		if err := s.runRequest(proxyEndpoint.PreFlow.Request, proxyPath+".PreFlow.Request"); err != nil {
+			return err
+		}
+	}
+	if proxyFlow != nil {
+		if err := s.runRequest(proxyFlow.Request, fmt.Sprintf("%s.Flow(name: %s).Request", proxyPath, proxyFlow.Name)); err != nil {
+			return err
+		}
+	}
+	if proxyEndpoint.PostFlow != nil {
+		if err := s.runRequest(proxyEndpoint.PostFlow.Request, proxyPath+".PostFlow.Request"); err != nil {
+			return err
+		}
+	}
+
+	//route rule and target
+	if !s.faulted() {
+		routeRule, err := s.selectRouteRule(proxyEndpoint, proxyPath)
+		if err != nil {
+			return err
+		}
+		if routeRule != nil {
+			s.result.RouteRule = routeRule.Name
+			s.result.IntegrationEndpoint = routeRule.IntegrationEndpoint
+			if routeRule.TargetEndpoint != "" {
+				if err = s.runTarget(routeRule.TargetEndpoint); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	//proxy response
+	if proxyEndpoint.PreFlow != nil {
+		if err := s.runResponse(proxyEndpoint.PreFlow.Response, proxyPath+".PreFlow.Response"); err != nil {
+			return err
+		}
+	}
+	if proxyFlow != nil {
+		if err := s.runResponse(proxyFlow.Response, fmt.Sprintf("%s.Flow(name: %s).Response", proxyPath, proxyFlow.Name)); err != nil {
+			return err
+		}
+	}
+	if proxyEndpoint.PostFlow != nil {
+		if err := s.runResponse(proxyEndpoint.PostFlow.Response, proxyPath+".PostFlow.Response"); err != nil {
+			return err
+		}
+	}
+
+	//fault rules of the endpoint where the fault was raised
+	if s.faulted() && !s.faultHandled {
+		if err := s.runFaultRules(proxyEndpoint.FaultRules, proxyEndpoint.DefaultFaultRule, proxyPath, true); err != nil {
+			return err
+		}
+	}
+
+	//the PostClientFlow runs after the response is sent, even for error responses
+	if proxyEndpoint.PostClientFlow != nil {
+		s.fault, s.faultHandled = "", false
+		if err := s.runResponse(proxyEndpoint.PostClientFlow.Response, proxyPath+".PostClientFlow.Response"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *simulation) runTarget(name string) error {
+	var targetEndpoint *v1.TargetEndpoint
+	for _, candidate := range s.model.TargetEndpoints.List {
+		if candidate.Name == name {
+			targetEndpoint = candidate
+			break
+		}
+	}
+	if targetEndpoint == nil {
+		return errors.Errorf(`target endpoint "%s" does not exist`, name)
+	}
+
+	s.result.TargetEndpoint = targetEndpoint.Name
+	s.result.TargetURL = targetURL(targetEndpoint)
+	s.vars["target.name"] = targetEndpoint.Name
+	if s.result.TargetURL != "" {
+		s.vars["target.url"] = s.result.TargetURL
+	}
+
+	targetPath := fmt.Sprintf("TargetEndpoint(name: %s)", targetEndpoint.Name)
+	targetFlow, err := s.selectFlow(targetEndpoint.Flows.List, targetPath)
+	if err != nil {
+		return err
+	}
+	if targetFlow != nil {
+		s.result.TargetFlow = targetFlow.Name
+	}
+
+	if err = s.runRequest(targetEndpoint.PreFlow.Request, targetPath+".PreFlow.Request"); err != nil {
+		return err
+	}
+	if targetFlow != nil {
+		if err = s.runRequest(targetFlow.Request, fmt.Sprintf("%s.Flow(name: %s).Request", targetPath, targetFlow.Name)); err != nil {
+			return err
+		}
+	}
+	if err = s.runRequest(targetEndpoint.PostFlow.Request, targetPath+".PostFlow.Request"); err != nil {
+		return err
+	}
+
+	if err = s.runResponse(targetEndpoint.PreFlow.Response, targetPath+".PreFlow.Response"); err != nil {
+		return err
+	}
+	if targetFlow != nil {
+		if err = s.runResponse(targetFlow.Response, fmt.Sprintf("%s.Flow(name: %s).Response", targetPath, targetFlow.Name)); err != nil {
+			return err
+		}
+	}
+	if err = s.runResponse(targetEndpoint.PostFlow.Response, targetPath+".PostFlow.Response"); err != nil {
+		return err
+	}
+
+	if s.faulted() {
+		return s.runFaultRules(targetEndpoint.FaultRules, targetEndpoint.DefaultFaultRule, targetPath, false)
+	}
+	return nil
+}
+
+// runFaultRules executes the first matching fault rule, followed by the default fault rule if needed.
+// Fault rules in a proxy endpoint are evaluated from last to first, while in a target endpoint they are evaluated from first to last.
+func (s *simulation) runFaultRules(faultRules *v1.FaultRules, defaultFaultRule *v1.DefaultFaultRule, path string, reverse bool) error {
+	s.faultHandled = true
+
+	var list []*v1.FaultRule
+	if faultRules != nil {
+		list = append(list, faultRules.List...)
+	}
+	if reverse {
+		slices.Reverse(list)
+	}
+
+	//steps within fault rules execute even though a fault was raised
+	fault := s.fault
+	s.fault = ""
+	defer func() {
+		s.fault = fault
+	}()
+
+	matched := false
+	for _, faultRule := range list {
+		if faultRule == nil {
+			continue
+		}
+		faultRulePath := fmt.Sprintf("%s.FaultRule(name: %s)", path, faultRule.Name)
+		ok, err := s.evaluate(faultRule.Condition, faultRulePath)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		s.result.FaultRule = faultRule.Name
+		matched = true
+		if err = s.runSteps(faultRule.Steps, faultRulePath); err != nil {
+			return err
+		}
+		break
+	}
+
+	if defaultFaultRule != nil && (!matched || defaultFaultRule.AlwaysEnforce) {
+		if !matched {
+			s.result.FaultRule = "DefaultFaultRule"
+		}
+		return s.runSteps(defaultFaultRule.Steps, path+".DefaultFaultRule")
+	}
+	return nil
+}
+
+// targetURL returns the URL (or load balancer servers) the target endpoint sends requests to
+func targetURL(v *v1.TargetEndpoint) string {
+	if v.LocalTargetConnection != nil {
+		return "local"
+	}
+	if v.HTTPTargetConnection == nil {
+		return ""
+	}
+	if v.HTTPTargetConnection.URL != "" {
+		return strings.TrimSpace(v.HTTPTargetConnection.URL)
+	}
+	loadBalancer := v.HTTPTargetConnection.LoadBalancer
+	if loadBalancer == nil || loadBalancer.Servers == nil {
+		return ""
+	}
+	var servers []string
+	for _, server := range *loadBalancer.Servers {
+		servers = append(servers, server.Name)
+	}
+	return fmt.Sprintf("LoadBalancer(%s)%s", strings.Join(servers, ", "), v.HTTPTargetConnection.Path)
+}
+
+// selectFlow returns the first flow whose condition is true (flows without a condition always match)
+func (s *simulation) selectFlow(flows v1.FlowList, path string) (*v1.Flow, error) {
+	for _, flow := range flows {
+		if flow == nil {
+			continue
+		}
+		ok, err := s.evaluate(flow.Condition, fmt.Sprintf("%s.Flow(name: %s)", path, flow.Name))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return flow, nil
+		}
+	}
+	return nil, nil
+}
+
+// selectRouteRule returns the first route rule whose condition is true (route rules without a condition always match)
+func (s *simulation) selectRouteRule(proxyEndpoint *v1.ProxyEndpoint, path string) (*v1.RouteRule, error) {
+	if proxyEndpoint.RouteRules == nil {
+		return nil, nil
+	}
+	for _, routeRule := range *proxyEndpoint.RouteRules {
+		if routeRule == nil {
+			continue
+		}
+		ok, err := s.evaluate(routeRule.Condition, fmt.Sprintf("%s.RouteRule(name: %s)", path, routeRule.Name))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return routeRule, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *simulation) runRequest(v *v1.Request, phase string) error {
+	if v == nil {
+		return nil
+	}
+	return s.runSteps(v.Steps, phase)
+}
+
+func (s *simulation) runResponse(v *v1.Response, phase string) error {
+	if v == nil {
+		return nil
+	}
+	return s.runSteps(v.Steps, phase)
+}
+
+func (s *simulation) runSteps(steps v1.StepList, phase string) error {
+	for _, step := range steps {
+		if s.faulted() {
+			return nil
+		}
+		if step == nil {
+			continue
+		}
+		executed, err := s.evaluate(step.Condition, fmt.Sprintf("%s.Step(name: %s)", phase, step.Name))
+		if err != nil {
+			return err
+		}
+		policy := s.policy(step.Name)
+		disabled := policy != nil && policy.Attr("enabled") == "false"
+		executed = executed && !disabled
+		s.result.Steps = append(s.result.Steps, StepResult{
+			Phase:     phase,
+			Policy:    step.Name,
+			Type:      policyType(policy),
+			Condition: strings.TrimSpace(step.Condition),
+			Disabled:  disabled,
+			Executed:  executed,
+		})
+
+		//a RaiseFault policy always puts the flow into the error state
+		if executed && policyType(policy) == "RaiseFault" && policy.Attr("continueOnError") != "true" {
+			s.fault = step.Name
+			s.result.Fault = step.Name
+			s.vars["fault.name"] = "RaiseFault"
+		}
+	}
+	return nil
+}
+
+func (s *simulation) faulted() bool {
+	return s.fault != ""
+}
+
+func (s *simulation) policy(name string) *v1.Policy {
+	for _, policy := range s.model.Policies.List {
+		if policy.Name() == name {
+			return policy
+		}
+	}
+	return nil
+}
+
+func policyType(policy *v1.Policy) string {
+	if policy == nil {
+		return ""
+	}
+	return policy.Type()
+}
+
+func (s *simulation) evaluate(cond string, location string) (bool, error) {
+	if strings.TrimSpace(cond) == "" {
+		return true, nil
+	}
+	ok, err := condition.EvaluateString(cond, s.vars)
+	if err != nil {
+		return false, errors.Errorf(`could not evaluate condition '%s' at "%s". %s`, cond, location, err.Error())
+	}
+	return ok, nil
+}
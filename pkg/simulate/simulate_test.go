@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulate
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/bundle"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        Request
+		proxyEndpoint  string
+		flow           string
+		routeRule      string
+		targetEndpoint string
+		fault          string
+		faultRule      string
+		policies       []string
+		wantErr        string
+	}{
+		{
+			name:           "get-pets",
+			request:        Request{Verb: "GET", Path: "/v1/petstore/pets"},
+			proxyEndpoint:  "default",
+			flow:           "listPets",
+			routeRule:      "default",
+			targetEndpoint: "default",
+			policies:       []string{"Spike-Arrest", "VA-Verify", "AM-Target", "AM-Response", "ML-Logging-OK"},
+		},
+		{
+			name:          "get-pet-by-id-mock",
+			request:       Request{Verb: "get", Path: "/v1/petstore/pets/123", Headers: map[string]string{"X-Mock": "true"}},
+			proxyEndpoint: "default",
+			flow:          "showPetById",
+			routeRule:     "mock",
+			policies:      []string{"Spike-Arrest", "VA-Verify", "AM-Response", "ML-Logging-OK"},
+		},
+		{
+			name:          "options-skips-verify",
+			request:       Request{Verb: "OPTIONS", Path: "/v1/petstore/pets", Headers: map[string]string{"x-mock": "true"}},
+			proxyEndpoint: "default",
+			flow:          "CatchAll",
+			fault:         "RF-CatchAll",
+			faultRule:     "DefaultFaultRule",
+			policies:      []string{"Spike-Arrest", "RF-CatchAll", "AM-Error", "ML-Logging-OK"},
+		},
+		{
+			name:          "catch-all",
+			request:       Request{Verb: "DELETE", Path: "/v1/petstore/pets/1"},
+			proxyEndpoint: "default",
+			flow:          "CatchAll",
+			fault:         "RF-CatchAll",
+			faultRule:     "DefaultFaultRule",
+			policies:      []string{"Spike-Arrest", "VA-Verify", "RF-CatchAll", "AM-Error", "ML-Logging-OK"},
+		},
+		{
+			name:          "fault-rule",
+			request:       Request{Path: "/v1/petstore/admin/users/1"},
+			proxyEndpoint: "default",
+			flow:          "adminOnly",
+			fault:         "RF-Forbidden",
+			faultRule:     "forbidden",
+			policies:      []string{"Spike-Arrest", "VA-Verify", "RF-Forbidden", "AM-Forbidden", "ML-Logging-OK"},
+		},
+		{
+			name:           "longest-base-path",
+			request:        Request{Path: "/v1/petstore/internal/status?mock=true"},
+			proxyEndpoint:  "internal",
+			routeRule:      "default",
+			targetEndpoint: "default",
+			policies:       []string{"AM-Mock", "AM-Target"},
+		},
+		{
+			name:           "query-param-flag",
+			request:        Request{Path: "/v1/petstore/internal", Query: map[string]string{"mock": "false"}},
+			proxyEndpoint:  "internal",
+			routeRule:      "default",
+			targetEndpoint: "default",
+			policies:       []string{"AM-Target"},
+		},
+		{
+			name:    "no-base-path",
+			request: Request{Path: "/v1/petstores"},
+			wantErr: `no proxy endpoint has a base path matching "/v1/petstores"`,
+		},
+	}
+
+	model, err := bundle.LoadModel(filepath.Join("testdata", "petstore", "apiproxy.yaml"))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Simulate(model, &tt.request)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.proxyEndpoint, result.ProxyEndpoint)
+			require.Equal(t, tt.flow, result.Flow)
+			require.Equal(t, tt.routeRule, result.RouteRule)
+			require.Equal(t, tt.targetEndpoint, result.TargetEndpoint)
+			require.Equal(t, tt.fault, result.Fault)
+			require.Equal(t, tt.faultRule, result.FaultRule)
+			require.Equal(t, tt.policies, result.Policies())
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatepkg
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Masterminds/semver/v3"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// ManifestFile is the file at the root of a template package that describes it
+const ManifestFile = "package.yaml"
+
+// DependenciesDir is the directory within a package where its dependencies are placed, one directory per package name
+const DependenciesDir = "packages"
+
+// Package types, each rendered the same way as the matching render command.
+// Library packages only provide helper templates and policies to the packages that depend on them.
+const (
+	TypeAPIProxy   = "apiproxy"
+	TypeSharedFlow = "sharedflow"
+	TypeTemplate   = "template"
+	TypeDir        = "dir"
+	TypeLibrary    = "library"
+)
+
+var Types = []string{TypeAPIProxy, TypeSharedFlow, TypeTemplate, TypeDir, TypeLibrary}
+
+// InputKinds are the flags that can provide a package input
+var InputKinds = []string{"set", "set-string", "set-json", "set-file", "set-oas", "set-grpc", "set-graphql", "set-tf", "values"}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+type Manifest struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+	Type        string `yaml:"type"`
+
+	// Template is the main template (or the template directory for the "dir" type), relative to the package root.
+	// Library packages do not have one.
+	Template string `yaml:"template"`
+
+	// Includes are helper templates (globs allowed) relative to the package root.
+	// These are also included when rendering any package that depends on this one.
+	Includes []string `yaml:"includes,omitempty"`
+
+	Inputs       []Input      `yaml:"inputs,omitempty"`
+	Values       yaml.Node    `yaml:"values,omitempty"`
+	Dependencies []Dependency `yaml:"dependencies,omitempty"`
+}
+
+// Input is a value the package expects in $.Values, along with the flag that provides it
+type Input struct {
+	Name        string `yaml:"name"`
+	Kind        string `yaml:"kind,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Dependency is another template package placed in "packages/<name>"
+type Dependency struct {
+	Name string `yaml:"name"`
+
+	// Version is a semantic version constraint, e.g. "^1.2.0"
+	Version string `yaml:"version,omitempty"`
+
+	// Source is the path to the package directory or archive, relative to the manifest.
+	// If empty, the package must already be present in "packages/<name>".
+	Source string `yaml:"source,omitempty"`
+}
+
+func LoadManifest(manifestFile string) (*Manifest, error) {
+	return loadManifest(manifestFile, manifestFile)
+}
+
+// loadManifest reads the manifest, using displayName for the file in errors (e.g. the original location of an unpacked package)
+func loadManifest(manifestFile string, displayName string) (*Manifest, error) {
+	text, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, errors.Errorf("%s: %s", displayName, errors.Unwrap(err).Error())
+	}
+
+	manifest := &Manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(text))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(manifest); err != nil {
+		return nil, errors.Errorf("%s: %s", displayName, err.Error())
+	}
+
+	if err = manifest.Validate(); err != nil {
+		return nil, prefixErrors(displayName, err)
+	}
+
+	return manifest, nil
+}
+
+// Validate checks the manifest fields, without looking at the package files
+func (m *Manifest) Validate() error {
+	var errs []error
+
+	if m.Name == "" {
+		errs = append(errs, errors.New(`missing "name"`))
+	} else if !namePattern.MatchString(m.Name) {
+		errs = append(errs, errors.Errorf(`name "%s" must contain only lowercase letters, digits and dashes`, m.Name))
+	}
+
+	if m.Version == "" {
+		errs = append(errs, errors.New(`missing "version"`))
+	} else if _, err := semver.StrictNewVersion(m.Version); err != nil {
+		errs = append(errs, errors.Errorf(`version "%s" is not a semantic version (e.g. 1.0.0)`, m.Version))
+	}
+
+	if m.Type == "" {
+		errs = append(errs, errors.New(`missing "type"`))
+	} else if !slices.Contains(Types, m.Type) {
+		errs = append(errs, errors.Errorf(`unknown type "%s", must be one of %v`, m.Type, Types))
+	}
+
+	if m.Template == "" && m.Type != TypeLibrary {
+		errs = append(errs, errors.New(`missing "template"`))
+	} else if m.Template != "" && !filepath.IsLocal(m.Template) {
+		errs = append(errs, errors.Errorf(`template "%s" must be within the package`, m.Template))
+	}
+
+	for _, include := range m.Includes {
+		if !filepath.IsLocal(include) {
+			errs = append(errs, errors.Errorf(`include "%s" must be within the package`, include))
+		}
+	}
+
+	for i, input := range m.Inputs {
+		if input.Name == "" {
+			errs = append(errs, errors.Errorf(`inputs[%d]: missing "name"`, i))
+		}
+		if input.Kind != "" && !slices.Contains(InputKinds, input.Kind) {
+			errs = append(errs, errors.Errorf(`inputs[%d]: unknown kind "%s", must be one of %v`, i, input.Kind, InputKinds))
+		}
+	}
+
+	if m.Values.Kind != 0 && m.Values.Kind != yaml.MappingNode {
+		errs = append(errs, errors.Errorf(`line %d: "values" must be a map`, m.Values.Line))
+	}
+
+	seen := map[string]bool{}
+	for i, dependency := range m.Dependencies {
+		if dependency.Name == "" {
+			errs = append(errs, errors.Errorf(`dependencies[%d]: missing "name"`, i))
+		} else if !namePattern.MatchString(dependency.Name) {
+			errs = append(errs, errors.Errorf(`dependencies[%d]: name "%s" must contain only lowercase letters, digits and dashes`, i, dependency.Name))
+		} else if seen[dependency.Name] {
+			errs = append(errs, errors.Errorf(`dependencies[%d]: duplicate dependency "%s"`, i, dependency.Name))
+		}
+		seen[dependency.Name] = true
+
+		// sources are relative to the package, and may point to sibling packages (e.g. "../security-policies"), but not above them
+		if dependency.Source != "" && !filepath.IsLocal(filepath.Join("package", dependency.Source)) {
+			errs = append(errs, errors.Errorf(`dependencies[%d]: source "%s" must be a relative path within the package, or the directory that contains it`, i, dependency.Source))
+		}
+
+		if dependency.Version != "" {
+			if _, err := semver.NewConstraint(dependency.Version); err != nil {
+				errs = append(errs, errors.Errorf(`dependencies[%d]: invalid version constraint "%s"`, i, dependency.Version))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return utils.MultiError{Errors: errs}
+	}
+	return nil
+}
+
+// InputKind returns the flag that provides the input (e.g. "set-oas"), defaulting to "set"
+func (i Input) InputKind() string {
+	if i.Kind == "" {
+		return "set"
+	}
+	return i.Kind
+}
+
+// Satisfies checks whether a package version matches the dependency's version constraint
+func (d Dependency) Satisfies(version string) (bool, error) {
+	if d.Version == "" {
+		return true, nil
+	}
+
+	constraint, err := semver.NewConstraint(d.Version)
+	if err != nil {
+		return false, errors.New(err)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, errors.New(err)
+	}
+	return constraint.Check(v), nil
+}
+
+func prefixErrors(prefix string, err error) error {
+	if multiErr, ok := err.(utils.MultiError); ok {
+		var errs []error
+		for _, e := range multiErr.Errors {
+			errs = append(errs, errors.Errorf("%s: %s", prefix, e.Error()))
+		}
+		return utils.MultiError{Errors: errs}
+	}
+	return errors.Errorf("%s: %s", prefix, err.Error())
+}
+
+// String returns the package name and version, e.g. "orders-v1@1.2.0"
+func (m *Manifest) String() string {
+	return fmt.Sprintf("%s@%s", m.Name, m.Version)
+}
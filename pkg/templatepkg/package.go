@@ -0,0 +1,184 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatepkg
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/zip"
+	"github.com/go-errors/errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Package is a template package unpacked into a directory, along with its dependencies
+type Package struct {
+	Manifest     *Manifest
+	Dir          string
+	Dependencies []*Package
+}
+
+// Load unpacks a template package from a package directory or archive (.zip) into a temporary directory.
+// Dependencies with a source are copied into the "packages" directory, replacing any copy already there.
+// The returned cleanup function removes the temporary directory.
+func Load(source string) (*Package, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "package-*")
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+	cleanup := func() { utils.LenientRemoveAll(tmpDir) }
+
+	pkgDir := filepath.Join(tmpDir, "package")
+	pkg, err := fetch(pkgDir, source, nil)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return pkg, cleanup, nil
+}
+
+// Create packages a template package directory (along with its dependencies) into "<name>-<version>.zip" within outputDir
+func Create(source string, outputDir string) (string, error) {
+	pkg, cleanup, err := Load(source)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	archive := filepath.Join(outputDir, fmt.Sprintf("%s-%s.zip", pkg.Manifest.Name, pkg.Manifest.Version))
+	if err = zip.Zip(archive, pkg.Dir); err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+// fetch copies (or unzips) the package source into dir, and opens it.
+// The stack holds the package directories being fetched, to detect circular dependencies.
+func fetch(dir string, source string, stack []string) (*Package, error) {
+	displayDir := source
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	if info.IsDir() {
+		if slices.Contains(stack, source) {
+			return nil, errors.Errorf("circular dependency: %s", strings.Join(append(stack, source), " -> "))
+		}
+		if err = utils.CopyDir(dir, source); err != nil {
+			return nil, errors.New(err)
+		}
+		if err = os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+			return nil, errors.New(err)
+		}
+		return open(dir, source, displayDir, append(stack, source))
+	}
+
+	if filepath.Ext(source) != ".zip" {
+		return nil, errors.Errorf("%s is not a package directory or archive (.zip)", source)
+	}
+	if err = zip.Unzip(dir, source); err != nil {
+		return nil, err
+	}
+	return open(dir, "", displayDir, stack)
+}
+
+// open reads the manifest of the package in dir, and opens its dependencies.
+// When sourceDir is set, dependencies with a source are fetched relative to it.
+// Otherwise (e.g. for archives), dependencies must already be in the "packages" directory.
+// The displayDir is shown in errors in place of dir.
+func open(dir string, sourceDir string, displayDir string, stack []string) (*Package, error) {
+	manifest, err := loadManifest(filepath.Join(dir, ManifestFile), filepath.Join(displayDir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Template != "" {
+		info, err := os.Stat(filepath.Join(dir, manifest.Template))
+		if err != nil {
+			return nil, errors.Errorf(`%s: template "%s" not found in package`, manifest, manifest.Template)
+		}
+		if isDir := manifest.Type == TypeDir; info.IsDir() != isDir {
+			kind := map[bool]string{true: "directory", false: "file"}[isDir]
+			return nil, errors.Errorf(`%s: template "%s" must be a %s for type "%s"`, manifest, manifest.Template, kind, manifest.Type)
+		}
+	}
+
+	pkg := &Package{Manifest: manifest, Dir: dir}
+	for _, dependency := range manifest.Dependencies {
+		// names are checked when loading the manifest, make sure the directory is within "packages" before replacing it
+		if !filepath.IsLocal(dependency.Name) || filepath.Base(dependency.Name) != dependency.Name {
+			return nil, errors.Errorf(`%s: dependency "%s" is not a valid package name`, manifest, dependency.Name)
+		}
+		dependencyDir := filepath.Join(dir, DependenciesDir, dependency.Name)
+
+		var dependencyPkg *Package
+		if sourceDir != "" && dependency.Source != "" {
+			if err = os.RemoveAll(dependencyDir); err != nil {
+				return nil, errors.New(err)
+			}
+			dependencyPkg, err = fetch(dependencyDir, filepath.Join(sourceDir, dependency.Source), stack)
+		} else if _, err = os.Stat(dependencyDir); err != nil {
+			return nil, errors.Errorf(`%s: dependency "%s" not found in %s/%s`, manifest, dependency.Name, DependenciesDir, dependency.Name)
+		} else {
+			dependencyPkg, err = open(dependencyDir, "", filepath.Join(displayDir, DependenciesDir, dependency.Name), stack)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if dependencyPkg.Manifest.Name != dependency.Name {
+			return nil, errors.Errorf(`%s: dependency "%s" is package "%s"`, manifest, dependency.Name, dependencyPkg.Manifest.Name)
+		}
+		satisfied, err := dependency.Satisfies(dependencyPkg.Manifest.Version)
+		if err != nil {
+			return nil, err
+		}
+		if !satisfied {
+			return nil, errors.Errorf(`%s: dependency "%s" version %s does not match "%s"`, manifest, dependency.Name, dependencyPkg.Manifest.Version, dependency.Version)
+		}
+
+		pkg.Dependencies = append(pkg.Dependencies, dependencyPkg)
+	}
+
+	return pkg, nil
+}
+
+// Files lists the files in the package, relative to its root
+func (p *Package) Files() ([]string, error) {
+	var files []string
+	err := fs.WalkDir(os.DirFS(p.Dir), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return files, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatepkg
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/globals"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		dir     string
+		wantErr string
+	}{
+		{
+			"orders",
+			"",
+		},
+		{
+			"bad-version",
+			`bad-version@1.0.0: dependency "security-policies" version 1.2.0 does not match "^2.0.0"`,
+		},
+		{
+			"circular-a",
+			"circular dependency",
+		},
+		{
+			"unknown-field",
+			"testdata/unknown-field/package.yaml: yaml: unmarshal errors:\n  line 17: field dependecies not found in type templatepkg.Manifest",
+		},
+		{
+			"traversal-name",
+			`testdata/traversal-name/package.yaml: dependencies[0]: name "../../../../victim" must contain only lowercase letters, digits and dashes`,
+		},
+		{
+			"traversal-source",
+			`testdata/traversal-source/package.yaml: dependencies[0]: source "../../security-policies" must be a relative path within the package, or the directory that contains it`,
+		},
+		{
+			"invalid-manifest",
+			`name "Invalid Name" must contain only lowercase letters, digits and dashes
+testdata/invalid-manifest/package.yaml: version "v1" is not a semantic version (e.g. 1.0.0)
+testdata/invalid-manifest/package.yaml: unknown type "proxy"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			pkg, cleanup, err := Load(filepath.Join("testdata", tt.dir))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer cleanup()
+
+			require.Len(t, pkg.Dependencies, 1)
+			require.Equal(t, "security-policies@1.2.0", pkg.Dependencies[0].Manifest.String())
+			require.FileExists(t, filepath.Join(pkg.Dir, DependenciesDir, "security-policies", "_policies.tmpl"))
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	archive, err := Create(filepath.Join("testdata", "orders"), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "orders-1.0.0.zip", filepath.Base(archive))
+
+	// dependencies are vendored into the archive, so it no longer needs their sources
+	pkg, cleanup, err := Load(archive)
+	require.NoError(t, err)
+	defer cleanup()
+
+	files, err := pkg.Files()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"apiproxy.yaml",
+		"package.yaml",
+		"packages/security-policies/_policies.tmpl",
+		"packages/security-policies/package.yaml",
+	}, files)
+}
+
+func TestPackage_Render(t *testing.T) {
+	showStack := flags.NewBool(false)
+	globals.ShowStack = &showStack
+
+	tests := []struct {
+		name    string
+		set     []string
+		wantErr string
+	}{
+		{
+			"defaults",
+			[]string{"target_url=https://orders.example.com"},
+			"",
+		},
+		{
+			"missing-input",
+			nil,
+			`orders@1.0.0: missing required input "target_url" (URL of the backend). Use --set-string target_url=..., or --values with a file that has it`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, cleanup, err := Load(filepath.Join("testdata", "orders"))
+			require.NoError(t, err)
+			defer cleanup()
+
+			outputDir := t.TempDir()
+			cFlags := render.NewCommonFlags()
+			cFlags.OutputFile = flags.String(outputDir)
+			setString := flags.NewSetString(cFlags.Values)
+			for _, value := range tt.set {
+				require.NoError(t, setString.Set(value))
+			}
+
+			_, err = pkg.Render(cFlags, true, "", false)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			expected, err := utils.ReadBundleDir(filepath.Join("testdata", "exp-orders"))
+			require.NoError(t, err)
+			actual, err := utils.ReadBundleDir(outputDir)
+			require.NoError(t, err)
+
+			diffs, err := utils.BundleDiff(expected, actual)
+			require.NoError(t, err)
+			require.Empty(t, diffs)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatepkg
+
+import (
+	"fmt"
+	"github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/apigee/apigee-go-gen/pkg/render"
+	"github.com/apigee/apigee-go-gen/pkg/utils"
+	"github.com/apigee/apigee-go-gen/pkg/values"
+	"github.com/go-errors/errors"
+	"path/filepath"
+	"strings"
+)
+
+// ApplyDefaults sets the default values of the package, and then those of its dependencies, for keys that are not set yet
+func (p *Package) ApplyDefaults(data *values.Map, sources values.Sources) error {
+	return p.walk(func(pkg *Package, relDir string) error {
+		if pkg.Manifest.Values.Kind == 0 {
+			return nil
+		}
+
+		defaults := values.Map{}
+		defaultSources := values.Sources{}
+		if err := defaults.Merge(&pkg.Manifest.Values, filepath.Join(relDir, ManifestFile), defaultSources); err != nil {
+			return err
+		}
+		data.SetDefaults(defaults, defaultSources, sources)
+		return nil
+	})
+}
+
+// CheckInputs verifies that all the required inputs of the package and its dependencies are set
+func (p *Package) CheckInputs(data values.Map) error {
+	var errs []error
+	seen := map[string]bool{}
+	_ = p.walk(func(pkg *Package, relDir string) error {
+		for _, input := range pkg.Manifest.Inputs {
+			if !input.Required || seen[input.Name] || hasValue(data, input.Name) {
+				continue
+			}
+			seen[input.Name] = true
+
+			description := ""
+			if input.Description != "" {
+				description = fmt.Sprintf(" (%s)", input.Description)
+			}
+			errs = append(errs, errors.Errorf(`%s: missing required input "%s"%s. %s`, pkg.Manifest, input.Name, description, inputHint(input)))
+		}
+		return nil
+	})
+
+	if len(errs) > 0 {
+		return utils.MultiError{Errors: errs}
+	}
+	return nil
+}
+
+// Render renders the package the same way as the render command for its type.
+// The default values are applied, and the required inputs checked, before rendering.
+// For the template and dir types, any dryRun value prints the rendered output instead of writing it.
+// The validation warnings of API proxies and shared flows are returned (see render.GenerateBundleWarnings).
+func (p *Package) Render(cFlags *render.CommonFlags, validate bool, dryRun string, debug bool) ([]error, error) {
+	if p.Manifest.Type == TypeLibrary {
+		return nil, errors.Errorf(`%s is a library package, it can only be used as a dependency`, p.Manifest)
+	}
+	if err := p.ApplyDefaults(cFlags.Values, nil); err != nil {
+		return nil, err
+	}
+	if err := p.CheckInputs(*cFlags.Values); err != nil {
+		return nil, err
+	}
+
+	err := p.walk(func(pkg *Package, relDir string) error {
+		for _, include := range pkg.Manifest.Includes {
+			cFlags.IncludeList = append(cFlags.IncludeList, filepath.Join(pkg.Dir, include))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cFlags.TemplateFile = flags.String(filepath.Join(p.Dir, p.Manifest.Template))
+	cFlags.TemplateFileAlias = flags.String(p.Manifest.Template)
+
+	switch p.Manifest.Type {
+	case TypeAPIProxy:
+		return render.GenerateBundleWarnings(func(input string) (v1.Model, error) {
+			return v1.NewAPIProxyModel(input)
+		}, cFlags, validate, dryRun, debug)
+	case TypeSharedFlow:
+		return render.GenerateBundleWarnings(func(input string) (v1.Model, error) {
+			return v1.NewSharedFlowBundleModel(input)
+		}, cFlags, validate, dryRun, debug)
+	case TypeTemplate:
+		return nil, render.RenderGenericTemplateLocal(cFlags, dryRun != "")
+	case TypeDir:
+		return nil, render.RenderDir(cFlags, dryRun != "")
+	}
+	return nil, errors.Errorf(`unknown package type "%s"`, p.Manifest.Type)
+}
+
+// Describe returns a summary of the package, its inputs, default values, dependencies and files
+func (p *Package) Describe() (string, error) {
+	var text strings.Builder
+	manifest := p.Manifest
+
+	fmt.Fprintf(&text, "Name:        %s\n", manifest.Name)
+	fmt.Fprintf(&text, "Version:     %s\n", manifest.Version)
+	fmt.Fprintf(&text, "Type:        %s\n", manifest.Type)
+	if manifest.Template != "" {
+		fmt.Fprintf(&text, "Template:    %s\n", manifest.Template)
+	}
+	if manifest.Description != "" {
+		fmt.Fprintf(&text, "Description: %s\n", strings.TrimSpace(manifest.Description))
+	}
+
+	if len(manifest.Inputs) > 0 {
+		text.WriteString("\nInputs:\n")
+		for _, input := range manifest.Inputs {
+			required := ""
+			if input.Required {
+				required = ", required"
+			}
+			fmt.Fprintf(&text, "  %s (--%s%s)", input.Name, input.InputKind(), required)
+			if input.Description != "" {
+				fmt.Fprintf(&text, " %s", input.Description)
+			}
+			text.WriteString("\n")
+		}
+	}
+
+	if manifest.Values.Kind != 0 {
+		defaults, err := utils.YAML2Text(&manifest.Values, 2)
+		if err != nil {
+			return "", err
+		}
+		text.WriteString("\nDefault values:\n")
+		for _, line := range strings.Split(strings.TrimRight(string(defaults), "\n"), "\n") {
+			fmt.Fprintf(&text, "  %s\n", line)
+		}
+	}
+
+	if len(p.Dependencies) > 0 {
+		text.WriteString("\nDependencies:\n")
+		for i, dependency := range manifest.Dependencies {
+			constraint := dependency.Version
+			if constraint == "" {
+				constraint = "*"
+			}
+			fmt.Fprintf(&text, "  %s %s (%s)\n", dependency.Name, constraint, p.Dependencies[i].Manifest.Version)
+		}
+	}
+
+	files, err := p.Files()
+	if err != nil {
+		return "", err
+	}
+	text.WriteString("\nFiles:\n")
+	for _, file := range files {
+		fmt.Fprintf(&text, "  %s\n", file)
+	}
+
+	return text.String(), nil
+}
+
+// walk calls fn for the package and then for each of its dependencies (depth first), along with their directory relative to the package root
+func (p *Package) walk(fn func(pkg *Package, relDir string) error) error {
+	var visit func(pkg *Package, relDir string) error
+	visit = func(pkg *Package, relDir string) error {
+		if err := fn(pkg, relDir); err != nil {
+			return err
+		}
+		for _, dependency := range pkg.Dependencies {
+			if err := visit(dependency, filepath.Join(relDir, DependenciesDir, dependency.Manifest.Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(p, "")
+}
+
+func hasValue(data values.Map, key string) bool {
+	var current any = map[string]any(data)
+	for _, part := range strings.Split(key, ".") {
+		var child map[string]any
+		switch typed := current.(type) {
+		case values.Map:
+			child = typed
+		case map[string]any:
+			child = typed
+		default:
+			return false
+		}
+		current = child[part]
+	}
+	return current != nil
+}
+
+func inputHint(input Input) string {
+	if input.InputKind() == "values" {
+		return "Use --values with a file that has it"
+	}
+	return fmt.Sprintf("Use --%s %s=..., or --values with a file that has it", input.InputKind(), input.Name)
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BundleDiff compares the files of two bundles (keyed by their path within the bundle).
+// XML files are compared by their YAML representation, and other files are compared ignoring YAML comments.
+// It returns a description for each difference found.
+func BundleDiff(expected map[string][]byte, actual map[string][]byte) ([]string, error) {
+	var names []string
+	for name := range expected {
+		names = append(names, name)
+	}
+	for name := range actual {
+		if _, found := expected[name]; !found {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var diffs []string
+	for _, name := range names {
+		expectedContents, inExpected := expected[name]
+		actualContents, inActual := actual[name]
+		if !inActual {
+			diffs = append(diffs, fmt.Sprintf("%s: missing file", name))
+			continue
+		}
+		if !inExpected {
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected file", name))
+			continue
+		}
+
+		expectedText, err := normalizeBundleFile(name, expectedContents)
+		if err != nil {
+			return nil, err
+		}
+		actualText, err := normalizeBundleFile(name, actualContents)
+		if err != nil {
+			return nil, err
+		}
+		if diff := LineDiff(expectedText, actualText); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("%s: %s", name, diff))
+		}
+	}
+
+	return diffs, nil
+}
+
+func normalizeBundleFile(name string, contents []byte) (string, error) {
+	if filepath.Ext(name) != ".xml" {
+		return string(RemoveYAMLComments(contents)), nil
+	}
+
+	yamlText, err := XMLText2YAMLText(bytes.NewReader(contents))
+	if err != nil {
+		return "", errors.Errorf("%s: %s", name, err.Error())
+	}
+	return NormalizeYAML(yamlText)
+}
+
+// NormalizeYAML re-encodes the YAML text with sorted keys and no comments, so that it can be compared line by line
+func NormalizeYAML(text []byte) (string, error) {
+	var value any
+	if err := yaml.Unmarshal(text, &value); err != nil {
+		return "", errors.New(err)
+	}
+	normalized, err := yaml.Marshal(value)
+	if err != nil {
+		return "", errors.New(err)
+	}
+	return string(normalized), nil
+}
+
+// LineDiff describes the first line that differs between the expected and actual text, or returns "" if they are equal
+func LineDiff(expected string, actual string) string {
+	if expected == actual {
+		return ""
+	}
+
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		expectedLine, actualLine := "<end of file>", "<end of file>"
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine != actualLine {
+			return fmt.Sprintf("line %d differs\n  expected: %s\n  actual:   %s", i+1, expectedLine, actualLine)
+		}
+	}
+	return "contents differ"
+}
+
+// ReadBundleDir reads all files within the directory, keyed by their slash separated path relative to the directory
+func ReadBundleDir(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = contents
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return files, nil
+}
+
+// ReadBundleZip reads all files within the zip, keyed by their path within the zip
+func ReadBundleZip(zipFile string) (map[string][]byte, error) {
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer MustClose(reader)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		contents, err := io.ReadAll(fileReader)
+		MustClose(fileReader)
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		files[file.Name] = contents
+	}
+	return files, nil
+}
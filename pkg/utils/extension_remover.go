@@ -28,42 +28,25 @@ func RemoveExtensions(input string, output string) error {
 		return err
 	}
 
-	//unmarshall input as YAML
-	var yamlNode *yaml.Node
-	yamlNode = &yaml.Node{}
-	err = yaml.Unmarshal(text, yamlNode)
-	if err != nil {
-		return errors.New(err)
-	}
-
-	yamlNode, err = RemoveOASExtensions(yamlNode)
-	if err != nil {
-		return err
-	}
-
-	//convert back to text
+	//convert back to text, using the output file extension, or else the input file extension
 	ext := filepath.Ext(output)
 	if ext == "" {
 		ext = filepath.Ext(input)
 	}
 
-	//depending on the file extension write output as either JSON or YAML
-	var outputText []byte
-	if ext == ".json" {
-		outputText, err = libopenapijson.YAMLNodeToJSON(yamlNode, "  ")
-		if err != nil {
-			return errors.New(err)
-		}
-	} else {
-		outputText, err = YAML2Text(UnFlowYAMLNode(yamlNode), 2)
-		if err != nil {
-			return err
-		}
+	outputText, err := RemoveExtensionsText(text, ext)
+	if err != nil {
+		return err
 	}
 
 	return WriteOutputText(output, outputText)
 }
 
+// RemoveExtensionsText is like RemoveExtensions, for text in memory. The output is JSON for the ".json" extension, or else YAML.
+func RemoveExtensionsText(text []byte, ext string) ([]byte, error) {
+	return transformOASText(text, ext, RemoveOASExtensions)
+}
+
 func RemoveOASExtensions(root *yaml.Node) (*yaml.Node, error) {
 	modified, err := RemoveOASExtensionsRecursive(root, "")
 	if err != nil {
@@ -142,42 +125,25 @@ func RemoveSchemaExtensions(input string, output string) error {
 		return err
 	}
 
-	//unmarshall input as YAML
-	var yamlNode *yaml.Node
-	yamlNode = &yaml.Node{}
-	err = yaml.Unmarshal(text, yamlNode)
-	if err != nil {
-		return errors.New(err)
-	}
-
-	yamlNode, err = RemoveOASSchemaExtensions(yamlNode)
-	if err != nil {
-		return err
-	}
-
-	//convert back to text
+	//convert back to text, using the output file extension, or else the input file extension
 	ext := filepath.Ext(output)
 	if ext == "" {
 		ext = filepath.Ext(input)
 	}
 
-	//depending on the file extension write output as either JSON or YAML
-	var outputText []byte
-	if ext == ".json" {
-		outputText, err = libopenapijson.YAMLNodeToJSON(yamlNode, "  ")
-		if err != nil {
-			return errors.New(err)
-		}
-	} else {
-		outputText, err = YAML2Text(UnFlowYAMLNode(yamlNode), 2)
-		if err != nil {
-			return err
-		}
+	outputText, err := RemoveSchemaExtensionsText(text, ext)
+	if err != nil {
+		return err
 	}
 
 	return WriteOutputText(output, outputText)
 }
 
+// RemoveSchemaExtensionsText is like RemoveSchemaExtensions, for text in memory. The output is JSON for the ".json" extension, or else YAML.
+func RemoveSchemaExtensionsText(text []byte, ext string) ([]byte, error) {
+	return transformOASText(text, ext, RemoveOASSchemaExtensions)
+}
+
 func RemoveOASSchemaExtensions(root *yaml.Node) (*yaml.Node, error) {
 	var err error
 
@@ -294,3 +260,33 @@ func findMappingNode(node *yaml.Node, path []string) *yaml.Node {
 	}
 	return current
 }
+
+func transformOASText(text []byte, ext string, transform func(*yaml.Node) (*yaml.Node, error)) ([]byte, error) {
+	//unmarshall input as YAML
+	var yamlNode *yaml.Node
+	yamlNode = &yaml.Node{}
+	err := yaml.Unmarshal(text, yamlNode)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	yamlNode, err = transform(yamlNode)
+	if err != nil {
+		return nil, err
+	}
+
+	//depending on the file extension write output as either JSON or YAML
+	var outputText []byte
+	if ext == ".json" {
+		outputText, err = libopenapijson.YAMLNodeToJSON(yamlNode, "  ")
+		if err != nil {
+			return nil, errors.New(err)
+		}
+	} else {
+		outputText, err = YAML2Text(UnFlowYAMLNode(yamlNode), 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputText, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// OSFS is the local file system as an fs.FS. Unlike os.DirFS, it takes OS paths as they are
+// (absolute, or relative to the working directory), so that functions that read from an fs.FS
+// also work with the paths given on the command line.
+var OSFS fs.FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// FSJoin joins path elements, with OS separators for OSFS, or with forward slashes for any other fs.FS
+func FSJoin(fsys fs.FS, elem ...string) string {
+	if fsys == OSFS {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// FSDir returns the directory of the path, see FSJoin
+func FSDir(fsys fs.FS, name string) string {
+	if fsys == OSFS {
+		return filepath.Dir(name)
+	}
+	return path.Dir(name)
+}
+
+// FSAbs returns a unique path for the file, which is the absolute path for OSFS
+func FSAbs(fsys fs.FS, name string) (string, error) {
+	if fsys == OSFS {
+		return filepath.Abs(name)
+	}
+	return path.Clean(name), nil
+}
+
+// FSIsAbs reports whether the path is absolute, paths within an fs.FS other than OSFS never are
+func FSIsAbs(fsys fs.FS, name string) bool {
+	if fsys == OSFS {
+		return filepath.IsAbs(name)
+	}
+	return false
+}
+
+// FSSub returns the file system rooted at dir (see FSJoin)
+func FSSub(fsys fs.FS, dir string) (fs.FS, error) {
+	if fsys == OSFS {
+		return os.DirFS(dir), nil
+	}
+	return fs.Sub(fsys, dir)
+}
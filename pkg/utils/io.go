@@ -162,6 +162,15 @@ func ReadInputText(input string) ([]byte, error) {
 	return text, nil
 }
 
+// inputRefsFile returns the file JSON $refs within the input are relative to.
+// For stdin, they are relative to the current working directory.
+func inputRefsFile(input string) string {
+	if input == "-" || len(input) == 0 {
+		return "./input.yaml"
+	}
+	return input
+}
+
 func ReadInputTextFile(input string) ([]byte, error) {
 	var text []byte
 	var err error
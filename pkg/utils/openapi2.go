@@ -82,7 +82,7 @@ func OAS2FileToOAS3File(input string, output string, allowCycles bool) error {
 		return errors.Errorf("input %s is not an OpenAPI 2.0 Description", input)
 	}
 
-	cycles, err := YAMLDetectRefCycles(oas2node, input)
+	cycles, err := YAMLDetectRefCycles(oas2node, inputRefsFile(input))
 	if err != nil {
 		return err
 	}
@@ -62,45 +62,68 @@ func OASOverlay(overlayFile string, specFile string, outputFile string) error {
 		return err
 	}
 
+	ext := filepath.Ext(outputFile)
+	if ext == "" {
+		ext = filepath.Ext(specFile)
+	}
+
+	outputText, err := applyOASOverlayText(overlayNode, specText, overlayFile, specFile, ext)
+	if err != nil {
+		return err
+	}
+
+	return WriteOutputText(outputFile, outputText)
+}
+
+// OASOverlayText is like OASOverlay, for an Overlay and OpenAPI Description in memory.
+// The 'extends' field of the Overlay is ignored. The output is JSON for the ".json" extension, or else YAML.
+// The file names are only used in error messages.
+func OASOverlayText(overlayText []byte, specText []byte, overlayFile string, specFile string, ext string) ([]byte, error) {
+	var overlayNode *yaml.Node
+	overlayNode = &yaml.Node{}
+	err := yaml.Unmarshal(overlayText, overlayNode)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return applyOASOverlayText(overlayNode, specText, overlayFile, specFile, ext)
+}
+
+func applyOASOverlayText(overlayNode *yaml.Node, specText []byte, overlayFile string, specFile string, ext string) ([]byte, error) {
 	var specNode *yaml.Node
 	specNode = &yaml.Node{}
-	err = yaml.Unmarshal(specText, specNode)
+	err := yaml.Unmarshal(specText, specNode)
 	if err != nil {
-		return errors.New(err)
+		return nil, errors.New(err)
 	}
 
 	//verify we are actually working with OAS3
-	if slices.IndexFunc(specNode.Content[0].Content, func(n *yaml.Node) bool {
+	if len(specNode.Content) == 0 || slices.IndexFunc(specNode.Content[0].Content, func(n *yaml.Node) bool {
 		return n.Value == "openapi"
 	}) < 0 {
-		return errors.Errorf("%s is not an OpenAPI 3.X Description file", specFile)
+		return nil, errors.Errorf("%s is not an OpenAPI 3.X Description file", specFile)
 	}
 
 	resultNode, err := ApplyOASOverlay(overlayNode, specNode, overlayFile, specFile)
 	if err != nil {
-		return err
-	}
-
-	ext := filepath.Ext(outputFile)
-	if ext == "" {
-		ext = filepath.Ext(specFile)
+		return nil, err
 	}
 
-	//depending on the file extension write the outputFile as either JSON or YAML
+	//depending on the file extension write the output as either JSON or YAML
 	var outputText []byte
 	if ext == ".json" {
 		outputText, err = libopenapijson.YAMLNodeToJSON(resultNode, "  ")
 		if err != nil {
-			return errors.New(err)
+			return nil, errors.New(err)
 		}
 	} else {
 		outputText, err = YAML2Text(UnFlowYAMLNode(resultNode), 2)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return WriteOutputText(outputFile, outputText)
+	return outputText, nil
 }
 
 func ApplyOASOverlay(overlayNode *yaml.Node, specNode *yaml.Node, overlayFile string, specFile string) (*yaml.Node, error) {
@@ -15,9 +15,8 @@
 package utils
 
 import (
-	"github.com/go-errors/errors"
-	libopenapijson "github.com/pb33f/libopenapi/json"
 	"gopkg.in/yaml.v3"
+	"io/fs"
 	"path/filepath"
 )
 
@@ -27,39 +26,24 @@ func ResolveDollarRefs(input string, output string, allowCycles bool) error {
 		return err
 	}
 
-	//unmarshall input as YAML
-	var yamlNode *yaml.Node
-	yamlNode = &yaml.Node{}
-	err = yaml.Unmarshal(text, yamlNode)
-	if err != nil {
-		return errors.New(err)
-	}
-
-	//resolve references
-	yamlNode, err = YAMLResolveRefs(yamlNode, input, allowCycles)
-	if err != nil {
-		return err
-	}
-
-	//convert back to text
+	//convert back to text, using the output file extension, or else the input file extension
 	ext := filepath.Ext(output)
 	if ext == "" {
 		ext = filepath.Ext(input)
 	}
 
-	//depending on the file extension write output as either JSON or YAML
-	var outputText []byte
-	if ext == ".json" {
-		outputText, err = libopenapijson.YAMLNodeToJSON(yamlNode, "  ")
-		if err != nil {
-			return errors.New(err)
-		}
-	} else {
-		outputText, err = YAML2Text(UnFlowYAMLNode(yamlNode), 2)
-		if err != nil {
-			return err
-		}
+	outputText, err := ResolveDollarRefsText(OSFS, text, inputRefsFile(input), allowCycles, ext)
+	if err != nil {
+		return err
 	}
 
 	return WriteOutputText(output, outputText)
 }
+
+// ResolveDollarRefsText is like ResolveDollarRefs, for text in memory. JSON $refs are relative to filePath,
+// and the files they point to are read from fsys. The output is JSON for the ".json" extension, or else YAML.
+func ResolveDollarRefsText(fsys fs.FS, text []byte, filePath string, allowCycles bool, ext string) ([]byte, error) {
+	return transformOASText(text, ext, func(yamlNode *yaml.Node) (*yaml.Node, error) {
+		return YAMLResolveRefsFS(fsys, yamlNode, filePath, allowCycles)
+	})
+}
@@ -118,6 +118,10 @@ func XMLText2XML(reader io.Reader) (*etree.Document, error) {
 	return doc, nil
 }
 
+// Struct2XMLDocText encodes the struct as an XML document. The output is stable: it always has the same declaration,
+// two space indentation, and LF line endings, so that the same struct produces the same bytes regardless of the OS.
+// Carriage returns within text and attributes are content, and are kept escaped as "&#xD;"
+// (line endings within YAML files are already normalized to LF when parsing).
 func Struct2XMLDocText(p any) ([]byte, error) {
 	var err error
 	outXML := bytes.Buffer{}
@@ -132,13 +136,16 @@ func Struct2XMLDocText(p any) ([]byte, error) {
 		return nil, err
 	}
 
+	// etree writes carriage returns within attributes as-is (text is already escaped), escape them the same way as text
+	fmtXML = bytes.ReplaceAll(fmtXML, []byte("\r"), []byte("&#xD;"))
+
 	w := &bytes.Buffer{}
 	w.Write([]byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"))
 	w.Write(fmtXML)
-
-	if err != nil {
-		return nil, err
+	if !bytes.HasSuffix(fmtXML, []byte("\n")) {
+		w.WriteByte('\n')
 	}
+
 	return w.Bytes(), nil
 }
 
@@ -82,3 +82,19 @@ func TestXMLText2YAMLText(t *testing.T) {
 		})
 	}
 }
+
+func TestStruct2XMLDocText(t *testing.T) {
+	type element struct {
+		XMLName struct{} `xml:"Element"`
+		Name    string   `xml:"name,attr"`
+		Text    string   `xml:"Text"`
+	}
+
+	lf, err := Struct2XMLDocText(element{Name: "a\nb", Text: "line1\nline2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Element name=\"a\nb\">\n  <Text>line1\nline2</Text>\n</Element>\n", string(lf))
+
+	crlf, err := Struct2XMLDocText(element{Name: "a\r\nb", Text: "line1\r\nline2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Element name=\"a&#xD;\nb\">\n  <Text>line1&#xD;\nline2</Text>\n</Element>\n", string(crlf))
+}
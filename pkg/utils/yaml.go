@@ -22,6 +22,7 @@ import (
 	libopenapijson "github.com/pb33f/libopenapi/json"
 	"gopkg.in/yaml.v3"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -67,6 +68,17 @@ func YAML2Text(node *yaml.Node, indent int) ([]byte, error) {
 }
 
 func Text2YAML(reader io.Reader) (*yaml.Node, error) {
+	return FileText2YAML(reader, "./input.yaml")
+}
+
+// FileText2YAML is like Text2YAML, but JSON $refs are resolved relative to the directory of
+// the given file (where the text came from), instead of the current working directory
+func FileText2YAML(reader io.Reader, filePath string) (*yaml.Node, error) {
+	return FileText2YAMLFS(OSFS, reader, filePath)
+}
+
+// FileText2YAMLFS is like FileText2YAML, but the files JSON $refs point to are read from fsys
+func FileText2YAMLFS(fsys fs.FS, reader io.Reader, filePath string) (*yaml.Node, error) {
 	var err error
 	decoder := yaml.NewDecoder(reader)
 	yamlNode := yaml.Node{}
@@ -74,8 +86,11 @@ func Text2YAML(reader io.Reader) (*yaml.Node, error) {
 		return nil, errors.New(err)
 	}
 
-	filePath := "./input.yaml"
-	resultNode, err := YAMLResolveRefs(&yamlNode, filePath, false)
+	return resolveYAMLDoc(fsys, &yamlNode, filePath)
+}
+
+func resolveYAMLDoc(fsys fs.FS, yamlNode *yaml.Node, filePath string) (*yaml.Node, error) {
+	resultNode, err := YAMLResolveRefsFS(fsys, yamlNode, filePath, false)
 	if err != nil {
 		return nil, err
 	}
@@ -181,23 +196,39 @@ func YAMLDoc2File(docNode *yaml.Node, outputFile string) error {
 }
 
 func YAMLFile2YAML(filePath string) (*yaml.Node, error) {
-	var file *os.File
+	dataNode, _, err := YAMLFile2YAMLWithPositions(filePath)
+	return dataNode, err
+}
+
+// YAMLFile2YAMLWithPositions is like YAMLFile2YAML, but it also keeps track of which nodes
+// came from the file itself, so that their line and column can be reported
+func YAMLFile2YAMLWithPositions(filePath string) (*yaml.Node, *YAMLPositions, error) {
+	return YAMLFile2YAMLWithPositionsFS(OSFS, filePath)
+}
+
+// YAMLFile2YAMLWithPositionsFS is like YAMLFile2YAMLWithPositions, but the file (and the files its JSON $refs point to) are read from fsys
+func YAMLFile2YAMLWithPositionsFS(fsys fs.FS, filePath string) (*yaml.Node, *YAMLPositions, error) {
+	var file fs.File
 	var err error
-	if file, err = os.Open(filePath); err != nil {
-		return nil, errors.New(err)
+	if file, err = fsys.Open(filePath); err != nil {
+		return nil, nil, errors.New(err)
 	}
 	defer func() { MustClose(file) }()
 
-	//switch to directory relative to the YAML file so that JSON $refs are valid
-	popd := PushDir(filepath.Dir(filePath))
-	defer popd()
+	decoder := yaml.NewDecoder(file)
+	yamlNode := yaml.Node{}
+	if err = decoder.Decode(&yamlNode); err != nil {
+		return nil, nil, errors.Errorf("%s: %s", filePath, err.Error())
+	}
+
+	positions := NewYAMLPositions(filePath, &yamlNode)
 
-	dataNode, err := Text2YAML(file)
+	dataNode, err := resolveYAMLDoc(fsys, &yamlNode, filePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return dataNode, nil
+	return dataNode, positions, nil
 }
 
 func UnFlowYAMLNode(node *yaml.Node) *yaml.Node {
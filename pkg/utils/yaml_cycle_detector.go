@@ -18,32 +18,35 @@ import (
 	"fmt"
 	"github.com/go-errors/errors"
 	"gopkg.in/yaml.v3"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strings"
 )
 
 func YAMLDetectRefCycles(root *yaml.Node, filePath string) (cycles [][]string, err error) {
-	fileDir := filepath.Dir(filePath)
-	filePath = filepath.Base(filePath)
-
-	PopD := PushDir(fileDir)
-	defer PopD()
+	absFilePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, errors.New(err)
+	}
 
-	err = YAMLDetectRefCyclesRecursive(root, "", filePath, []string{}, &map[string]*yaml.Node{}, &cycles)
+	err = yamlDetectRefCyclesRecursive(OSFS, root, "", absFilePath, []string{}, &map[string]*yaml.Node{}, &cycles)
 
 	return cycles, err
 }
 
 func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentFile string, activePaths []string, loaded *map[string]*yaml.Node, cycles *[][]string) error {
+	return yamlDetectRefCyclesRecursive(OSFS, node, relParentPath, parentFile, activePaths, loaded, cycles)
+}
+
+func yamlDetectRefCyclesRecursive(fsys fs.FS, node *yaml.Node, relParentPath string, parentFile string, activePaths []string, loaded *map[string]*yaml.Node, cycles *[][]string) error {
 	var err error
 
 	if node == nil {
 		return nil
 	}
 
-	absFilePath, err := filepath.Abs(parentFile)
+	absFilePath, err := FSAbs(fsys, parentFile)
 	if err != nil {
 		return errors.Errorf("could not process %s:%s. %s", parentFile, relParentPath, err.Error())
 	}
@@ -74,11 +77,9 @@ func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentF
 			return errors.Errorf("could not process JSONRef %s at %s. %s", jsonRef, parentFile, err.Error())
 		}
 
-		if refFilePath == "" {
-			refFilePath = parentFile
-		}
+		refFilePath = refFilePathFrom(fsys, parentFile, refFilePath)
 
-		refFileNode, err := loadYAMLFile(refFilePath, loaded)
+		refFileNode, err := loadYAMLFile(fsys, refFilePath, loaded)
 
 		if err != nil {
 			return errors.Errorf("could not process JSONRef %s at %s. %s", jsonRef, parentFile, err.Error())
@@ -89,11 +90,7 @@ func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentF
 			return errors.Errorf("could not process JSONRef %s at %s, %s", jsonRef, parentFile, err.Error())
 		}
 
-		//switch-dir
-		PopD := PushDir(filepath.Dir(refFilePath))
-		defer PopD()
-
-		err = YAMLDetectRefCyclesRecursive(yamlNode, refJSONPath, filepath.Base(refFilePath), activePaths, loaded, cycles)
+		err = yamlDetectRefCyclesRecursive(fsys, yamlNode, refJSONPath, refFilePath, activePaths, loaded, cycles)
 		if err != nil {
 			return err
 		}
@@ -103,13 +100,13 @@ func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentF
 		for i := 0; i+1 < len(node.Content); i += 2 {
 			curPath := fmt.Sprintf("%s.%s", relParentPath, node.Content[i].Value)
 
-			err = YAMLDetectRefCyclesRecursive(node.Content[i+1], curPath, parentFile, activePaths, loaded, cycles)
+			err = yamlDetectRefCyclesRecursive(fsys, node.Content[i+1], curPath, parentFile, activePaths, loaded, cycles)
 			if err != nil {
 				return err
 			}
 		}
 	} else if node.Kind == yaml.DocumentNode {
-		err = YAMLDetectRefCyclesRecursive(node.Content[0], "$", parentFile, activePaths, loaded, cycles)
+		err = yamlDetectRefCyclesRecursive(fsys, node.Content[0], "$", parentFile, activePaths, loaded, cycles)
 		if err != nil {
 			return err
 		}
@@ -117,7 +114,7 @@ func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentF
 		for i := 0; i < len(node.Content); i += 1 {
 			curPath := fmt.Sprintf("%s.%d", relParentPath, i)
 
-			err = YAMLDetectRefCyclesRecursive(node.Content[i], curPath, parentFile, activePaths, loaded, cycles)
+			err = yamlDetectRefCyclesRecursive(fsys, node.Content[i], curPath, parentFile, activePaths, loaded, cycles)
 			if err != nil {
 				return err
 			}
@@ -127,10 +124,10 @@ func YAMLDetectRefCyclesRecursive(node *yaml.Node, relParentPath string, parentF
 	return nil
 }
 
-func loadYAMLFile(filePath string, loaded *map[string]*yaml.Node) (*yaml.Node, error) {
+func loadYAMLFile(fsys fs.FS, filePath string, loaded *map[string]*yaml.Node) (*yaml.Node, error) {
 	var err error
 
-	absFilePath, err := filepath.Abs(filePath)
+	absFilePath, err := FSAbs(fsys, filePath)
 	if err != nil {
 		return nil, errors.New(err)
 	}
@@ -141,10 +138,11 @@ func loadYAMLFile(filePath string, loaded *map[string]*yaml.Node) (*yaml.Node, e
 		return cachedNode, nil
 	}
 
-	file, err := os.Open(absFilePath)
+	file, err := fsys.Open(absFilePath)
 	if err != nil {
 		return nil, errors.New(err)
 	}
+	defer MustClose(file)
 
 	decoder := yaml.NewDecoder(file)
 	yamlNode := yaml.Node{}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLPosition is the location of a node within a YAML file
+type YAMLPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (p YAMLPosition) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// YAMLPositions keeps track of the nodes that were parsed from a YAML file,
+// as opposed to nodes that were brought in from other files by resolving $refs
+type YAMLPositions struct {
+	File  string
+	nodes map[*yaml.Node]bool
+}
+
+func NewYAMLPositions(file string, root *yaml.Node) *YAMLPositions {
+	positions := &YAMLPositions{
+		File:  file,
+		nodes: map[*yaml.Node]bool{},
+	}
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node == nil || positions.nodes[node] {
+			return
+		}
+		positions.nodes[node] = true
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return positions
+}
+
+// Position returns the position of the node, if the node was parsed from the file
+func (p *YAMLPositions) Position(node *yaml.Node) (YAMLPosition, bool) {
+	if p == nil || node == nil || !p.nodes[node] || node.Line == 0 {
+		return YAMLPosition{}, false
+	}
+	return YAMLPosition{File: p.File, Line: node.Line, Column: node.Column}, true
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/vmware-labs/yaml-jsonpath/pkg/yamlpath"
 	"gopkg.in/yaml.v3"
+	"io/fs"
 	"net/url"
 	"path/filepath"
 	"slices"
@@ -26,14 +27,13 @@ import (
 )
 
 func YAMLResolveAllRefs(root *yaml.Node, filePath string, allowCycles bool) (*yaml.Node, error) {
-	fileDir := filepath.Dir(filePath)
-	filePath = filepath.Base(filePath)
-
-	PopD := PushDir(fileDir)
-	defer PopD()
+	absFilePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, errors.New(err)
+	}
 
 	cycles := [][]string{}
-	resolved, err := YAMLResolveRefsRecursive(root, "", filePath, []string{}, &map[string]*yaml.Node{}, &cycles, true)
+	resolved, err := yamlResolveRefsRecursive(OSFS, root, "", absFilePath, []string{}, &map[string]*yaml.Node{}, &cycles, true)
 	if err != nil {
 		return nil, err
 	}
@@ -50,14 +50,18 @@ func YAMLResolveAllRefs(root *yaml.Node, filePath string, allowCycles bool) (*ya
 }
 
 func YAMLResolveRefs(root *yaml.Node, filePath string, allowCycles bool) (*yaml.Node, error) {
-	fileDir := filepath.Dir(filePath)
-	filePath = filepath.Base(filePath)
+	return YAMLResolveRefsFS(OSFS, root, filePath, allowCycles)
+}
 
-	PopD := PushDir(fileDir)
-	defer PopD()
+// YAMLResolveRefsFS is like YAMLResolveRefs, but the file, and the files its JSON $refs point to, are read from fsys
+func YAMLResolveRefsFS(fsys fs.FS, root *yaml.Node, filePath string, allowCycles bool) (*yaml.Node, error) {
+	absFilePath, err := FSAbs(fsys, filePath)
+	if err != nil {
+		return nil, errors.New(err)
+	}
 
 	cycles := [][]string{}
-	resolved, err := YAMLResolveRefsRecursive(root, "", filePath, []string{}, &map[string]*yaml.Node{}, &cycles, false)
+	resolved, err := yamlResolveRefsRecursive(fsys, root, "", absFilePath, []string{}, &map[string]*yaml.Node{}, &cycles, false)
 	if err != nil {
 		return nil, err
 	}
@@ -74,13 +78,17 @@ func YAMLResolveRefs(root *yaml.Node, filePath string, allowCycles bool) (*yaml.
 }
 
 func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile string, activePaths []string, loaded *map[string]*yaml.Node, cycles *[][]string, resolveMainRefs bool) (*yaml.Node, error) {
+	return yamlResolveRefsRecursive(OSFS, node, relParentPath, parentFile, activePaths, loaded, cycles, resolveMainRefs)
+}
+
+func yamlResolveRefsRecursive(fsys fs.FS, node *yaml.Node, relParentPath string, parentFile string, activePaths []string, loaded *map[string]*yaml.Node, cycles *[][]string, resolveMainRefs bool) (*yaml.Node, error) {
 	var err error
 
 	if node == nil {
 		return nil, nil
 	}
 
-	absFilePath, err := filepath.Abs(parentFile)
+	absFilePath, err := FSAbs(fsys, parentFile)
 	if err != nil {
 		return nil, errors.Errorf("could not process %s:%s. %s", parentFile, relParentPath, err.Error())
 	}
@@ -112,18 +120,16 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 			return nil, errors.Errorf("could not process JSONRef %s at %s. %s", jsonRef, parentFile, err.Error())
 		}
 
-		if refFilePath == "" {
-			refFilePath = parentFile
-		}
+		refFilePath = refFilePathFrom(fsys, parentFile, refFilePath)
 
 		//do not resolve refs that point back to the main file back
-		absRefFilePath, _ := filepath.Abs(refFilePath)
+		absRefFilePath, _ := FSAbs(fsys, refFilePath)
 		rootPath, _, _ := strings.Cut(activePaths[0], ":")
 		if absRefFilePath == rootPath && resolveMainRefs == false {
 			return node, nil
 		}
 
-		refFileNode, err := loadYAMLFile(refFilePath, loaded)
+		refFileNode, err := loadYAMLFile(fsys, refFilePath, loaded)
 
 		if err != nil {
 			return nil, errors.Errorf("could not process JSONRef %s at %s. %s", jsonRef, parentFile, err.Error())
@@ -134,11 +140,7 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 			return nil, errors.Errorf("could not process JSONRef %s at %s, %s", jsonRef, parentFile, err.Error())
 		}
 
-		//switch-dir
-		popd := PushDir(filepath.Dir(refFilePath))
-		defer popd()
-
-		resolved, err := YAMLResolveRefsRecursive(yamlNode, refJSONPath, filepath.Base(refFilePath), activePaths, loaded, cycles, resolveMainRefs)
+		resolved, err := yamlResolveRefsRecursive(fsys, yamlNode, refJSONPath, refFilePath, activePaths, loaded, cycles, resolveMainRefs)
 		if err != nil {
 			return nil, err
 		}
@@ -148,7 +150,7 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 		for i := 0; i+1 < len(node.Content); i += 2 {
 			curPath := fmt.Sprintf("%s.%s", relParentPath, node.Content[i].Value)
 
-			resolved, err := YAMLResolveRefsRecursive(node.Content[i+1], curPath, parentFile, activePaths, loaded, cycles, resolveMainRefs)
+			resolved, err := yamlResolveRefsRecursive(fsys, node.Content[i+1], curPath, parentFile, activePaths, loaded, cycles, resolveMainRefs)
 			if err != nil {
 				return nil, err
 			}
@@ -156,7 +158,7 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 		}
 		return node, nil
 	} else if node.Kind == yaml.DocumentNode {
-		resolved, err := YAMLResolveRefsRecursive(node.Content[0], "$", parentFile, activePaths, loaded, cycles, resolveMainRefs)
+		resolved, err := yamlResolveRefsRecursive(fsys, node.Content[0], "$", parentFile, activePaths, loaded, cycles, resolveMainRefs)
 		if err != nil {
 			return nil, err
 		}
@@ -166,7 +168,7 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 		for i := 0; i < len(node.Content); i += 1 {
 			curPath := fmt.Sprintf("%s.%d", relParentPath, i)
 
-			resolved, err := YAMLResolveRefsRecursive(node.Content[i], curPath, parentFile, activePaths, loaded, cycles, resolveMainRefs)
+			resolved, err := yamlResolveRefsRecursive(fsys, node.Content[i], curPath, parentFile, activePaths, loaded, cycles, resolveMainRefs)
 			if err != nil {
 				return nil, err
 			}
@@ -178,6 +180,21 @@ func YAMLResolveRefsRecursive(node *yaml.Node, relParentPath string, parentFile
 	return node, nil
 }
 
+// refFilePathFrom returns the path of the file a JSONRef points to.
+// Relative paths are relative to the directory of the file that contains the JSONRef (not to the working directory).
+func refFilePathFrom(fsys fs.FS, parentFile string, refFilePath string) string {
+	if refFilePath == "" {
+		return parentFile
+	}
+	if fsys == OSFS {
+		refFilePath = filepath.FromSlash(refFilePath)
+	}
+	if FSIsAbs(fsys, refFilePath) {
+		return refFilePath
+	}
+	return FSJoin(fsys, FSDir(fsys, parentFile), refFilePath)
+}
+
 func isYAMLRef(node *yaml.Node) bool {
 	if node == nil {
 		return false
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+// SetDefaults deep merges the defaults into the map, without replacing values that are already set.
+// Maps are merged key by key, while any other value (including lists) is only taken from the defaults if missing.
+// The sources of the defaults are added to sources, for keys that do not have a source yet.
+func (m *Map) SetDefaults(defaults Map, defaultSources Sources, sources Sources) {
+	if *m == nil {
+		*m = Map{}
+	}
+	setDefaults(map[string]any(*m), map[string]any(defaults))
+
+	if sources == nil {
+		return
+	}
+	for key, source := range defaultSources {
+		if _, found := sources[key]; !found {
+			sources[key] = source
+		}
+	}
+}
+
+func setDefaults(current map[string]any, defaults map[string]any) {
+	for key, defaultValue := range defaults {
+		value, found := current[key]
+		if !found || value == nil {
+			current[key] = defaultValue
+			continue
+		}
+
+		currentMap, isMap := asMap(value)
+		defaultMap, isDefaultMap := asMap(defaultValue)
+		if isMap && isDefaultMap {
+			setDefaults(currentMap, defaultMap)
+		}
+	}
+}
+
+func asMap(value any) (map[string]any, bool) {
+	switch typed := value.(type) {
+	case Map:
+		return typed, true
+	case map[string]any:
+		return typed, true
+	}
+	return nil, false
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// List merge strategies, selected with a tag on the list within the YAML document being merged (e.g. "servers: !append [...]")
+const (
+	ListReplace    = "replace"
+	ListAppend     = "append"
+	ListMergeByKey = "merge"
+)
+
+// DefaultMergeKey is the field used to match list items when using "!merge" without a key (e.g. "!merge:id")
+const DefaultMergeKey = "name"
+
+// Source records where a leaf value came from, along with the value at the time
+type Source struct {
+	Name  string
+	Value any
+}
+
+// Sources maps the key of each leaf value (e.g. "servers[0].url") to where it came from
+type Sources map[string]Source
+
+// Merge deep merges a YAML document into the map.
+// Maps are merged key by key, while lists are replaced unless tagged with !append or !merge.
+// The source name (e.g. the file path) of each leaf value is recorded in sources, if not nil.
+func (m *Map) Merge(node *yaml.Node, source string, sources Sources) error {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	if node.Kind == 0 || isNull(node) {
+		return nil
+	}
+
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return errors.Errorf("%s: line %d: values must be a map", source, node.Line)
+	}
+
+	merger := &merger{source: source, sources: sources}
+	if *m == nil {
+		*m = Map{}
+	}
+	return merger.mergeMap((*map[string]any)(m), node, "")
+}
+
+type merger struct {
+	source  string
+	sources Sources
+}
+
+func (r *merger) merge(current any, node *yaml.Node, key string) (any, error) {
+	node = resolveAlias(node)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		switch typed := current.(type) {
+		case Map:
+			err := r.mergeMap((*map[string]any)(&typed), node, key)
+			return typed, err
+		case map[string]any:
+			err := r.mergeMap(&typed, node, key)
+			return typed, err
+		}
+		result := Map{}
+		err := r.mergeMap((*map[string]any)(&result), node, key)
+		return result, err
+	case yaml.SequenceNode:
+		return r.mergeList(current, node, key)
+	case yaml.ScalarNode:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, errors.Errorf("%s: line %d: %s", r.source, node.Line, err.Error())
+		}
+		if r.sources != nil {
+			r.sources[key] = Source{Name: r.source, Value: value}
+		}
+		return value, nil
+	}
+
+	return nil, errors.Errorf("%s: line %d: unsupported YAML node at %q", r.source, node.Line, key)
+}
+
+func (r *merger) mergeMap(current *map[string]any, node *yaml.Node, key string) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := node.Content[i+1]
+
+		//merge keys ("<<: *anchor") are applied as if their content was inline
+		if keyNode.Tag == "!!merge" {
+			if err := r.mergeMergeKey(current, valueNode, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		childKey := keyNode.Value
+		if key != "" {
+			childKey = fmt.Sprintf("%s.%s", key, keyNode.Value)
+		}
+
+		value, err := r.merge((*current)[keyNode.Value], valueNode, childKey)
+		if err != nil {
+			return err
+		}
+		(*current)[keyNode.Value] = value
+	}
+
+	if len(node.Content) == 0 && r.sources != nil && key != "" {
+		r.sources[key] = Source{Name: r.source, Value: *current}
+	}
+	return nil
+}
+
+func (r *merger) mergeMergeKey(current *map[string]any, node *yaml.Node, key string) error {
+	node = resolveAlias(node)
+	if node.Kind == yaml.MappingNode {
+		return r.mergeMap(current, node, key)
+	}
+	if node.Kind != yaml.SequenceNode {
+		return errors.Errorf("%s: line %d: merge key value must be a map or list of maps", r.source, node.Line)
+	}
+	for _, item := range node.Content {
+		if err := r.mergeMergeKey(current, item, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *merger) mergeList(current any, node *yaml.Node, key string) (any, error) {
+	strategy, mergeKey, err := listStrategy(node)
+	if err != nil {
+		return nil, errors.Errorf("%s: line %d: %s", r.source, node.Line, err.Error())
+	}
+
+	var result []any
+	if strategy != ListReplace {
+		switch typed := current.(type) {
+		case []any:
+			result = typed
+		case Slice:
+			result = typed
+		}
+	}
+
+	for _, itemNode := range node.Content {
+		index := len(result)
+		var existing any
+
+		if strategy == ListMergeByKey {
+			if itemKey, ok := mergeKeyValue(resolveAlias(itemNode), mergeKey); ok {
+				for i, item := range result {
+					if value, found := mapValue(item, mergeKey); found && fmt.Sprint(value) == itemKey {
+						index, existing = i, item
+						break
+					}
+				}
+			}
+		}
+
+		item, err := r.merge(existing, itemNode, fmt.Sprintf("%s[%d]", key, index))
+		if err != nil {
+			return nil, err
+		}
+
+		if index == len(result) {
+			result = append(result, item)
+		} else {
+			result[index] = item
+		}
+	}
+
+	if len(result) == 0 && r.sources != nil {
+		r.sources[key] = Source{Name: r.source, Value: []any{}}
+	}
+	if result == nil {
+		result = []any{}
+	}
+	return result, nil
+}
+
+// listStrategy returns the merge strategy (and key for merging) from the tag of a YAML list
+func listStrategy(node *yaml.Node) (string, string, error) {
+	tag := node.Tag
+	if tag == "" || tag == "!!seq" {
+		return ListReplace, "", nil
+	}
+
+	strategy, mergeKey, _ := strings.Cut(strings.TrimPrefix(tag, "!"), ":")
+	switch strategy {
+	case ListReplace, ListAppend:
+		if mergeKey == "" {
+			return strategy, "", nil
+		}
+	case ListMergeByKey:
+		if mergeKey == "" {
+			mergeKey = DefaultMergeKey
+		}
+		return strategy, mergeKey, nil
+	}
+
+	return "", "", errors.Errorf("unknown list merge strategy %q. Use one of !%s, !%s, or !%s[:key]", tag, ListReplace, ListAppend, ListMergeByKey)
+}
+
+func mergeKeyValue(node *yaml.Node, mergeKey string) (string, bool) {
+	if node.Kind != yaml.MappingNode {
+		return "", false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == mergeKey {
+			var value any
+			if err := node.Content[i+1].Decode(&value); err != nil {
+				return "", false
+			}
+			return fmt.Sprint(value), true
+		}
+	}
+	return "", false
+}
+
+func mapValue(item any, key string) (any, bool) {
+	switch typed := item.(type) {
+	case Map:
+		value, found := typed[key]
+		return value, found
+	case map[string]any:
+		value, found := typed[key]
+		return value, found
+	}
+	return nil, false
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
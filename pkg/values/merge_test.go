@@ -0,0 +1,202 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestMap_Merge(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		overlay string
+		want    Map
+		wantErr string
+	}{
+		{
+			"nested maps",
+			`
+target:
+  url: https://dev.example.com
+  timeout: 30`,
+			`
+target:
+  url: https://prod.example.com`,
+			Map{"target": Map{"url": "https://prod.example.com", "timeout": 30}},
+			"",
+		},
+		{
+			"replace list",
+			`servers: [a, b]`,
+			`servers: [c]`,
+			Map{"servers": []any{"c"}},
+			"",
+		},
+		{
+			"append list",
+			`servers: [a, b]`,
+			`servers: !append [c]`,
+			Map{"servers": []any{"a", "b", "c"}},
+			"",
+		},
+		{
+			"merge list by name",
+			`
+servers:
+  - name: a
+    url: https://a
+  - name: b
+    url: https://b`,
+			`
+servers: !merge
+  - name: b
+    url: https://b-prod
+  - name: c
+    url: https://c`,
+			Map{"servers": []any{
+				Map{"name": "a", "url": "https://a"},
+				Map{"name": "b", "url": "https://b-prod"},
+				Map{"name": "c", "url": "https://c"},
+			}},
+			"",
+		},
+		{
+			"merge list by custom key",
+			`
+servers:
+  - id: 1
+    url: https://a`,
+			`
+servers: !merge:id
+  - id: 1
+    weight: 10`,
+			Map{"servers": []any{
+				Map{"id": 1, "url": "https://a", "weight": 10},
+			}},
+			"",
+		},
+		{
+			"merge key",
+			`defaults: &defaults {timeout: 30, retries: 3}`,
+			`
+defaults: &defaults {timeout: 30, retries: 3}
+target:
+  <<: *defaults
+  retries: 5`,
+			Map{"defaults": Map{"timeout": 30, "retries": 3}, "target": Map{"timeout": 30, "retries": 5}},
+			"",
+		},
+		{
+			"unknown strategy",
+			`servers: [a]`,
+			`servers: !prepend [b]`,
+			nil,
+			`unknown list merge strategy "!prepend"`,
+		},
+		{
+			"not a map",
+			`name: a`,
+			`[a, b]`,
+			nil,
+			"values must be a map",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := Map{}
+			require.NoError(t, mergeYAML(data, tt.base, "base.yaml", nil))
+
+			err := mergeYAML(data, tt.overlay, "overlay.yaml", nil)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, data)
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	data := Map{}
+	sources := Sources{}
+	require.NoError(t, mergeYAML(data, `
+name: orders
+target:
+  url: https://dev.example.com
+  timeout: 30`, "base.yaml", sources))
+	require.NoError(t, mergeYAML(data, "target: {url: https://prod.example.com}", "prod.yaml", sources))
+
+	data.Set("target.timeout", 60)
+	data.Set("tags", []any{"a"})
+
+	text, err := Print(data, sources)
+	require.NoError(t, err)
+	require.Equal(t, `name: orders # base.yaml
+tags:
+  - a # (command line)
+target:
+  timeout: 60 # (command line)
+  url: https://prod.example.com # prod.yaml
+`, string(text))
+}
+
+func TestMap_SetDefaults(t *testing.T) {
+	defaults := Map{}
+	defaultSources := Sources{}
+	require.NoError(t, mergeYAML(defaults, `
+name: orders
+servers: [a, b]
+target:
+  url: https://dev.example.com
+  timeout: 30`, "package.yaml", defaultSources))
+
+	data := Map{}
+	sources := Sources{}
+	require.NoError(t, mergeYAML(data, `
+servers: [c]
+target:
+  url: https://prod.example.com`, "prod.yaml", sources))
+
+	data.SetDefaults(defaults, defaultSources, sources)
+
+	require.Equal(t, Map{
+		"name":    "orders",
+		"servers": []any{"c"},
+		"target":  Map{"url": "https://prod.example.com", "timeout": 30},
+	}, data)
+
+	text, err := Print(data, sources)
+	require.NoError(t, err)
+	require.Equal(t, `name: orders # package.yaml
+servers:
+  - c # prod.yaml
+target:
+  timeout: 30 # package.yaml
+  url: https://prod.example.com # prod.yaml
+`, string(text))
+}
+
+func mergeYAML(data Map, text string, source string, sources Sources) error {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(text), node); err != nil {
+		return err
+	}
+	return data.Merge(node, source, sources)
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// CommandLine is the source shown for values that were not set from a values file (e.g. --set, --set-oas)
+const CommandLine = "(command line)"
+
+// Print renders the map as YAML, with a comment next to each leaf value showing where it came from
+func Print(m Map, sources Sources) ([]byte, error) {
+	node, err := printNode(map[string]any(m), "", sources)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(node); err != nil {
+		return nil, errors.New(err)
+	}
+	if err = encoder.Close(); err != nil {
+		return nil, errors.New(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func printNode(value any, key string, sources Sources) (*yaml.Node, error) {
+	switch typed := value.(type) {
+	case Map:
+		return printNode(map[string]any(typed), key, sources)
+	case Slice:
+		return printNode([]any(typed), key, sources)
+	case map[string]any:
+		if len(typed) == 0 {
+			break
+		}
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := make([]string, 0, len(typed))
+		for childKey := range typed {
+			keys = append(keys, childKey)
+		}
+		slices.Sort(keys)
+		for _, childKey := range keys {
+			fullKey := childKey
+			if key != "" {
+				fullKey = fmt.Sprintf("%s.%s", key, childKey)
+			}
+			child, err := printNode(typed[childKey], fullKey, sources)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: childKey}, child)
+		}
+		return node, nil
+	case []any:
+		if len(typed) == 0 {
+			break
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i, item := range typed {
+			child, err := printNode(item, fmt.Sprintf("%s[%d]", key, i), sources)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(printableValue(value)); err != nil {
+		return nil, errors.New(err)
+	}
+
+	source, found := sources[key]
+	if !found || !reflect.DeepEqual(source.Value, value) {
+		source.Name = CommandLine
+	}
+	node.LineComment = source.Name
+	return node, nil
+}
+
+// printableValue avoids dumping complex values (e.g. a parsed GraphQL schema) that are not plain data
+func printableValue(value any) any {
+	if value == nil {
+		return nil
+	}
+	if _, ok := value.(time.Time); ok {
+		return value
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Map, reflect.Slice:
+		return value
+	}
+	return fmt.Sprintf("<%T>", value)
+}
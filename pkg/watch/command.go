@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"fmt"
+	v1 "github.com/apigee/apigee-go-gen/pkg/apigee/v1"
+	"github.com/apigee/apigee-go-gen/pkg/git"
+	"github.com/go-errors/errors"
+	"github.com/spf13/pflag"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Flag is the name of the flag that turns on watch mode
+const Flag = "watch"
+
+// pathFlags are the flags that read files, along with a function that returns the paths to watch for the flag's value
+var pathFlags = map[string]func(value string) []string{
+	// the template directory also holds the helpers, the included files and the values schema
+	"template":    dirPath,
+	"input":       dirPath,
+	"package":     dirPath,
+	"include":     filePath,
+	"values":      valuesPaths,
+	"set-file":    keyPath,
+	"set-oas":     keyPath,
+	"set-grpc":    keyPath,
+	"set-graphql": keyPath,
+	"set-tf":      keyPath,
+}
+
+// outputFlags are the flags for files written by the command, which must not trigger another run
+var outputFlags = []string{"output"}
+
+// FlagPaths returns the paths to watch, and the paths to ignore, for the flags within the command line arguments
+func FlagPaths(flagSet *pflag.FlagSet, args []string) ([]string, []string, error) {
+	var paths []string
+	var ignore []string
+
+	flagSetCopy := pflag.NewFlagSet(Flag, pflag.ContinueOnError)
+	flagSetCopy.SetOutput(io.Discard)
+	flagSetCopy.AddFlagSet(flagSet)
+	err := flagSetCopy.ParseAll(args, func(flag *pflag.Flag, value string) error {
+		if isRemote(value) {
+			return nil
+		}
+		if watchPaths, found := pathFlags[flag.Name]; found {
+			paths = append(paths, watchPaths(value)...)
+		}
+		for _, outputFlag := range outputFlags {
+			if flag.Name == outputFlag && value != "-" && value != "" {
+				// along with the SHA-256 manifest written next to bundles
+				ignore = append(ignore, value, value+v1.ManifestExtension)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+
+	return paths, ignore, nil
+}
+
+// dirPath returns the directory of the file, or the value itself if it is a directory (e.g. for render dir)
+func dirPath(value string) []string {
+	if info, err := os.Stat(value); err == nil && info.IsDir() {
+		return []string{value}
+	}
+	return []string{filepath.Dir(value)}
+}
+
+func filePath(value string) []string {
+	return []string{value}
+}
+
+// valuesPaths returns the values file, along with its overlays for any profile (e.g. "values.prod.yaml" for "values.yaml")
+func valuesPaths(value string) []string {
+	ext := filepath.Ext(value)
+	return []string{value, strings.TrimSuffix(value, ext) + ".*" + ext}
+}
+
+func keyPath(value string) []string {
+	_, path, found := strings.Cut(value, "=")
+	if !found || isRemote(path) {
+		return nil
+	}
+	return []string{path}
+}
+
+func isRemote(value string) bool {
+	return git.IsGitURI(value) || strings.Contains(value, "://")
+}
+
+// RemoveFlag returns the arguments without the given flag and its value (e.g. "--watch true" or "--watch=true")
+func RemoveFlag(args []string, name string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(result, args[i:]...)
+		case arg == "--"+name:
+			i++
+		case strings.HasPrefix(arg, "--"+name+"="):
+		default:
+			result = append(result, arg)
+		}
+	}
+	return result
+}
+
+// Command runs the current command again (without the watch flag) each time one of the files it reads changes.
+// The output and errors of each run are printed as they are, and do not stop watching. It returns when interrupted (e.g. Ctrl+C).
+func Command(flagSet *pflag.FlagSet) error {
+	args := os.Args[1:]
+	paths, ignore, err := FlagPaths(flagSet, args)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.New(err)
+	}
+
+	watcher := NewWatcher(paths, ignore)
+	childArgs := RemoveFlag(args, Flag)
+	run := func() {
+		start := time.Now()
+		child := exec.Command(executable, childArgs...)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		if err := child.Run(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[%s] failed (%s)\n", start.Format(time.TimeOnly), time.Since(start).Round(time.Millisecond))
+		} else {
+			fmt.Printf("[%s] done (%s)\n", start.Format(time.TimeOnly), time.Since(start).Round(time.Millisecond))
+		}
+		fmt.Printf("Watching %d file(s) for changes, press Ctrl+C to stop\n", len(watcher.Files()))
+	}
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	run()
+	watcher.Watch(stop, func(changed []string) {
+		fmt.Printf("\nChanged: %s\n", strings.Join(relPaths(changed), ", "))
+		run()
+	})
+	return nil
+}
+
+func relPaths(paths []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return paths
+	}
+	var result []string
+	for _, path := range paths {
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+		result = append(result, path)
+	}
+	return result
+}
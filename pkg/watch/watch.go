@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"github.com/bmatcuk/doublestar/v4"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const DefaultInterval = 300 * time.Millisecond
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watcher polls files, directories (recursively) and globs for changes.
+// Polling is used rather than file system notifications, so that it works the same on all platforms, and for editors that replace files when saving.
+type Watcher struct {
+	Paths    []string
+	Ignore   []string
+	Interval time.Duration
+	Debounce time.Duration
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+type snapshot map[string]fileState
+
+func NewWatcher(paths []string, ignore []string) *Watcher {
+	return &Watcher{
+		Paths:    absPaths(paths),
+		Ignore:   absPaths(ignore),
+		Interval: DefaultInterval,
+		Debounce: DefaultDebounce,
+	}
+}
+
+// Watch calls onChange with the changed files, once they have not changed for the debounce period.
+// It returns when the stop channel is closed.
+func (w *Watcher) Watch(stop <-chan struct{}, onChange func(changed []string)) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	previous := w.snapshot()
+	var pending []string
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current := w.snapshot()
+		if changed := previous.diff(current); len(changed) > 0 {
+			for _, file := range changed {
+				if !slices.Contains(pending, file) {
+					pending = append(pending, file)
+				}
+			}
+			previous = current
+			lastChange = time.Now()
+			continue
+		}
+
+		if len(pending) > 0 && time.Since(lastChange) >= w.Debounce {
+			slices.Sort(pending)
+			onChange(pending)
+			pending = nil
+		}
+	}
+}
+
+// Files returns the files currently matched by the watched paths
+func (w *Watcher) Files() []string {
+	var files []string
+	for file := range w.snapshot() {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+	return files
+}
+
+func (w *Watcher) snapshot() snapshot {
+	result := snapshot{}
+	for _, path := range w.Paths {
+		if isGlob(path) {
+			basePath, pattern := doublestar.SplitPattern(filepath.ToSlash(path))
+			matches, _ := doublestar.Glob(os.DirFS(basePath), pattern)
+			for _, match := range matches {
+				w.add(result, filepath.Join(basePath, match))
+			}
+			continue
+		}
+		w.add(result, path)
+	}
+	return result
+}
+
+// add records the state of the file, or of all files within the directory
+func (w *Watcher) add(result snapshot, path string) {
+	_ = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if w.ignored(file) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if file != path && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		result[file] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+}
+
+func (w *Watcher) ignored(file string) bool {
+	for _, ignore := range w.Ignore {
+		if rel, err := filepath.Rel(ignore, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the files that were added, removed or modified
+func (s snapshot) diff(other snapshot) []string {
+	var changed []string
+	for file, state := range s {
+		if otherState, found := other[file]; !found || otherState.size != state.size || !otherState.modTime.Equal(state.modTime) {
+			changed = append(changed, file)
+		}
+	}
+	for file := range other {
+		if _, found := s[file]; !found {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
+
+func absPaths(paths []string) []string {
+	var result []string
+	for _, path := range paths {
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+		result = append(result, path)
+	}
+	return result
+}
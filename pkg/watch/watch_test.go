@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"github.com/apigee/apigee-go-gen/pkg/flags"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "apiproxy.yaml")
+	helpersFile := filepath.Join(dir, "policies", "helpers.tmpl")
+	outputFile := filepath.Join(dir, "out", "apiproxy.zip")
+	for _, file := range []string{templateFile, helpersFile, outputFile} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+		require.NoError(t, os.WriteFile(file, []byte("a"), os.ModePerm))
+	}
+
+	watcher := NewWatcher([]string{dir}, []string{filepath.Join(dir, "out")})
+	watcher.Interval = 10 * time.Millisecond
+	watcher.Debounce = 50 * time.Millisecond
+	require.Equal(t, []string{templateFile, helpersFile}, watcher.Files())
+
+	changes := make(chan []string, 10)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watcher.Watch(stop, func(changed []string) { changes <- changed })
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(outputFile, []byte("ignored"), os.ModePerm))
+	require.NoError(t, os.WriteFile(templateFile, []byte("changed"), os.ModePerm))
+	require.NoError(t, os.WriteFile(helpersFile, []byte("changed"), os.ModePerm))
+
+	select {
+	case changed := <-changes:
+		require.Equal(t, []string{templateFile, helpersFile}, changed)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change detected")
+	}
+
+	close(stop)
+	<-done
+	require.Empty(t, changes)
+}
+
+func TestFlagPaths(t *testing.T) {
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	for _, name := range []string{"template", "include", "output", "values", "set", "set-oas", "set-file"} {
+		var value flags.String
+		flagSet.Var(&value, name, "")
+	}
+	var strict flags.Bool
+	flagSet.Var(&strict, "strict", "")
+
+	args := []string{
+		"render", "apiproxy",
+		"--template", "templates/apiproxy.yaml",
+		"--strict", "true",
+		"--include=helpers/*.tmpl",
+		"--values", "values.yaml",
+		"--set", "name=hello",
+		"--set-oas", "spec=specs/petstore.yaml",
+		"--set-oas", "remote=https://example.com/petstore.yaml",
+		"--set-file", "data=data.json",
+		"--output", "out/apiproxy.zip",
+	}
+	paths, ignore, err := FlagPaths(flagSet, args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"templates", "helpers/*.tmpl", "values.yaml", "values.*.yaml", "specs/petstore.yaml", "data.json"}, paths)
+	require.Equal(t, []string{"out/apiproxy.zip", "out/apiproxy.zip.sha256"}, ignore)
+}
+
+func TestRemoveFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"render", "template", "--watch", "true", "-t", "a.yaml"}, []string{"render", "template", "-t", "a.yaml"}},
+		{[]string{"render", "template", "--watch=true", "-t", "a.yaml"}, []string{"render", "template", "-t", "a.yaml"}},
+		{[]string{"render", "template", "-t", "a.yaml", "--", "--watch"}, []string{"render", "template", "-t", "a.yaml", "--", "--watch"}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, RemoveFlag(tt.args, Flag))
+	}
+}
@@ -16,22 +16,84 @@ package zip
 
 import (
 	"archive/zip"
+	"bytes"
 	"github.com/go-errors/errors"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"testing/fstest"
+	"time"
 )
 
+// Limits bounds the contents of the zip files that are read, to protect against zip bombs
+type Limits struct {
+	// MaxEntries is the maximum number of entries (files and directories)
+	MaxEntries int
+
+	// MaxFileSize is the maximum decompressed size of each file in bytes
+	MaxFileSize int64
+
+	// MaxTotalSize is the maximum decompressed size of all files in bytes
+	MaxTotalSize int64
+}
+
+// DefaultLimits are generous for API proxy and shared flow bundles, while keeping untrusted zips in check
+var DefaultLimits = Limits{
+	MaxEntries:   10000,
+	MaxFileSize:  100 << 20,
+	MaxTotalSize: 500 << 20,
+}
+
+// Check validates the entries of the zip before any of them is read.
+// Entries must have relative paths that stay within the zip root, must not be symlinks, and must be within the limits.
+// The decompressed sizes are those declared in the zip, archive/zip fails reading any entry that goes above its declared size.
+func (l Limits) Check(reader *zip.Reader) error {
+	if len(reader.File) > l.MaxEntries {
+		return errors.Errorf("zip has %d entries, which is above the limit of %d entries", len(reader.File), l.MaxEntries)
+	}
+
+	var totalSize uint64
+	for _, zipFile := range reader.File {
+		name := strings.TrimSuffix(zipFile.Name, "/")
+		if !fs.ValidPath(name) || name == "." || strings.Contains(name, "\\") {
+			return errors.Errorf("zip entry %q is not allowed, it must be a relative slash-separated path without \"..\" elements", zipFile.Name)
+		}
+		if zipFile.Mode()&fs.ModeSymlink != 0 {
+			return errors.Errorf("zip entry %q is not allowed, it is a symlink", zipFile.Name)
+		}
+		if zipFile.UncompressedSize64 > uint64(l.MaxFileSize) {
+			return errors.Errorf("zip entry %q is %d bytes decompressed, which is above the limit of %d bytes", zipFile.Name, zipFile.UncompressedSize64, l.MaxFileSize)
+		}
+		totalSize += zipFile.UncompressedSize64
+		if totalSize > uint64(l.MaxTotalSize) {
+			return errors.Errorf("zip is at least %d bytes decompressed, which is above the limit of %d bytes", totalSize, l.MaxTotalSize)
+		}
+	}
+	return nil
+}
+
+// Unzip extracts srcZip into destDir, after checking its entries against DefaultLimits
 func Unzip(destDir string, srcZip string) error {
+	return UnzipLimits(destDir, srcZip, DefaultLimits)
+}
+
+// UnzipLimits extracts srcZip into destDir, after checking its entries against the limits
+func UnzipLimits(destDir string, srcZip string, limits Limits) error {
 	r, err := zip.OpenReader(srcZip)
 	if err != nil {
 		return errors.New(err)
 	}
 	defer MustClose(r)
 
+	if err = limits.Check(&r.Reader); err != nil {
+		return errors.Errorf("%s: %s", srcZip, err.Error())
+	}
+
 	unzipFile := func(zipFile *zip.File) error {
-		path := filepath.Join(destDir, zipFile.Name)
+		// names were checked above, so the path is always within destDir
+		path := filepath.Join(destDir, filepath.FromSlash(zipFile.Name))
 
 		if zipFile.FileInfo().IsDir() {
 			err := os.MkdirAll(path, os.ModePerm)
@@ -91,25 +153,66 @@ func Zip(destZip string, srcDir string) error {
 	}
 	defer MustClose(outFile)
 
-	writer := zip.NewWriter(outFile)
-	defer MustClose(writer)
+	return ZipFS(outFile, os.DirFS(srcDir))
+}
+
+// BytesFS returns the files within the zip bytes as an fs.FS, without extracting them.
+// The entries are checked against DefaultLimits.
+func BytesFS(zipBytes []byte) (fs.FS, error) {
+	return BytesFSLimits(zipBytes, DefaultLimits)
+}
+
+// BytesFSLimits is like BytesFS, with the entries checked against the limits
+func BytesFSLimits(zipBytes []byte, limits Limits) (fs.FS, error) {
+	reader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	if err = limits.Check(reader); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// ZipFiles zips the files in memory, it maps the slash-separated path of each file (e.g. "apiproxy/policies/AM-Example.xml") to its contents
+func ZipFiles(files map[string][]byte) ([]byte, error) {
+	fSys := fstest.MapFS{}
+	for name, contents := range files {
+		fSys[name] = &fstest.MapFile{Data: contents, Mode: 0644}
+	}
+
+	buffer := bytes.Buffer{}
+	if err := ZipFS(&buffer, fSys); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
 
-	fSys := os.DirFS(srcDir)
+// ModTime is the modification time of all zip entries, so that zipping the same files always produces the same bytes.
+// It is the earliest time that can be represented within a zip file.
+var ModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
 
-	err = fs.WalkDir(fSys, ".", func(name string, dir fs.DirEntry, err error) error {
+// ZipFS writes a zip with all the files and directories in fSys.
+// The zip is reproducible: entries are sorted by name, and have fixed timestamps and permissions
+// (regardless of the metadata of the files in fSys).
+func ZipFS(out io.Writer, fSys fs.FS) error {
+	writer := zip.NewWriter(out)
+
+	// fs.WalkDir visits entries in lexical order
+	err := fs.WalkDir(fSys, ".", func(name string, dir fs.DirEntry, err error) error {
 		if err != nil || name == "." {
 			return err
 		}
 
 		if dir.IsDir() {
-			_, err = writer.Create(name + "/")
+			_, err = writer.CreateHeader(newFileHeader(name+"/", fs.ModeDir|0755, zip.Store))
 			if err != nil {
 				return errors.New(err)
 			}
 			return nil
 		}
 
-		dstFile, err := writer.Create(name)
+		dstFile, err := writer.CreateHeader(newFileHeader(name, 0644, zip.Deflate))
 		if err != nil {
 			return err
 		}
@@ -128,7 +231,7 @@ func Zip(destZip string, srcDir string) error {
 		return errors.New(err)
 	}
 
-	err = writer.Flush()
+	err = writer.Close()
 	if err != nil {
 		return errors.New(err)
 	}
@@ -136,6 +239,16 @@ func Zip(destZip string, srcDir string) error {
 	return nil
 }
 
+func newFileHeader(name string, mode fs.FileMode, method uint16) *zip.FileHeader {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   method,
+		Modified: ModTime,
+	}
+	header.SetMode(mode)
+	return header
+}
+
 func MustClose(c io.Closer) {
 	err := c.Close()
 	if err != nil {
@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zip
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type zipEntry struct {
+	name     string
+	mode     fs.FileMode
+	contents string
+}
+
+func createZip(t *testing.T, entries ...zipEntry) []byte {
+	buffer := bytes.Buffer{}
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		mode := entry.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		header := newFileHeader(entry.name, mode, zip.Deflate)
+		out, err := writer.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = out.Write([]byte(entry.contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestBytesFSLimits(t *testing.T) {
+	limits := Limits{MaxEntries: 3, MaxFileSize: 1024, MaxTotalSize: 2048}
+
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			entries: []zipEntry{{name: "apiproxy/", mode: fs.ModeDir | 0755}, {name: "apiproxy/hello.xml", contents: "<APIProxy/>"}},
+		},
+		{
+			name:    "parent dir",
+			entries: []zipEntry{{name: "../../etc/hello.xml", contents: "<APIProxy/>"}},
+			wantErr: `zip entry "../../etc/hello.xml" is not allowed`,
+		},
+		{
+			name:    "inner parent dir",
+			entries: []zipEntry{{name: "apiproxy/../../hello.xml", contents: "<APIProxy/>"}},
+			wantErr: `zip entry "apiproxy/../../hello.xml" is not allowed`,
+		},
+		{
+			name:    "absolute path",
+			entries: []zipEntry{{name: "/etc/hello.xml", contents: "<APIProxy/>"}},
+			wantErr: `zip entry "/etc/hello.xml" is not allowed`,
+		},
+		{
+			name:    "backslash",
+			entries: []zipEntry{{name: `..\hello.xml`, contents: "<APIProxy/>"}},
+			wantErr: `zip entry "..\\hello.xml" is not allowed`,
+		},
+		{
+			name:    "symlink",
+			entries: []zipEntry{{name: "apiproxy/hello.xml", mode: fs.ModeSymlink | 0777, contents: "/etc/passwd"}},
+			wantErr: `zip entry "apiproxy/hello.xml" is not allowed, it is a symlink`,
+		},
+		{
+			name:    "too many entries",
+			entries: []zipEntry{{name: "a"}, {name: "b"}, {name: "c"}, {name: "d"}},
+			wantErr: "zip has 4 entries, which is above the limit of 3 entries",
+		},
+		{
+			name:    "file too large",
+			entries: []zipEntry{{name: "a", contents: strings.Repeat("x", 1025)}},
+			wantErr: `zip entry "a" is 1025 bytes decompressed, which is above the limit of 1024 bytes`,
+		},
+		{
+			name:    "total too large",
+			entries: []zipEntry{{name: "a", contents: strings.Repeat("x", 1000)}, {name: "b", contents: strings.Repeat("x", 1000)}, {name: "c", contents: strings.Repeat("x", 1000)}},
+			wantErr: "zip is at least 3000 bytes decompressed, which is above the limit of 2048 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys, err := BytesFSLimits(createZip(t, tt.entries...), limits)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			contents, err := fs.ReadFile(fsys, "apiproxy/hello.xml")
+			require.NoError(t, err)
+			require.Equal(t, "<APIProxy/>", string(contents))
+		})
+	}
+}
+
+func TestUnzipLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	destDir := filepath.Join(tmpDir, "dest")
+
+	srcZip := filepath.Join(tmpDir, "bundle.zip")
+	require.NoError(t, os.WriteFile(srcZip, createZip(t, zipEntry{name: "../escaped.xml", contents: "<APIProxy/>"}), 0644))
+
+	err := UnzipLimits(destDir, srcZip, DefaultLimits)
+	require.ErrorContains(t, err, `zip entry "../escaped.xml" is not allowed`)
+	require.NoFileExists(t, filepath.Join(tmpDir, "escaped.xml"))
+
+	require.NoError(t, os.WriteFile(srcZip, createZip(t, zipEntry{name: "apiproxy/hello.xml", contents: "<APIProxy/>"}), 0644))
+	require.NoError(t, UnzipLimits(destDir, srcZip, DefaultLimits))
+	require.FileExists(t, filepath.Join(destDir, "apiproxy", "hello.xml"))
+}
+
+func TestZipReproducible(t *testing.T) {
+	files := map[string]string{
+		"apiproxy/hello.xml":                  "<APIProxy/>",
+		"apiproxy/policies/AM-Hello.xml":      "<AssignMessage/>",
+		"apiproxy/resources/jsc/hello.js":     "print('hello');",
+		"apiproxy/proxies/default.xml":        "<ProxyEndpoint/>",
+		"apiproxy/targets/default-target.xml": "<TargetEndpoint/>",
+	}
+
+	createDir := func(modTime time.Time, mode fs.FileMode) string {
+		dir := t.TempDir()
+		for name, contents := range files {
+			file := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+			require.NoError(t, os.WriteFile(file, []byte(contents), mode))
+			require.NoError(t, os.Chmod(file, mode))
+			require.NoError(t, os.Chtimes(file, modTime, modTime))
+		}
+		return dir
+	}
+
+	tmpDir := t.TempDir()
+	zip1 := filepath.Join(tmpDir, "bundle-1.zip")
+	zip2 := filepath.Join(tmpDir, "bundle-2.zip")
+	require.NoError(t, Zip(zip1, createDir(time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC), 0600)))
+	require.NoError(t, Zip(zip2, createDir(time.Date(2024, time.July, 8, 9, 10, 11, 0, time.Local), 0755)))
+
+	bytes1, err := os.ReadFile(zip1)
+	require.NoError(t, err)
+	bytes2, err := os.ReadFile(zip2)
+	require.NoError(t, err)
+	require.Equal(t, bytes1, bytes2)
+
+	reader, err := zip.NewReader(bytes.NewReader(bytes1), int64(len(bytes1)))
+	require.NoError(t, err)
+	for _, zipFile := range reader.File {
+		require.Truef(t, zipFile.Modified.Equal(ModTime), "%s: got modified time %s", zipFile.Name, zipFile.Modified)
+		if zipFile.FileInfo().IsDir() {
+			require.Equal(t, fs.ModeDir|0755, zipFile.Mode(), zipFile.Name)
+			continue
+		}
+		require.Equal(t, fs.FileMode(0644), zipFile.Mode(), zipFile.Name)
+	}
+}